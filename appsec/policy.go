@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
 	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/appsec"
 )
 
@@ -48,14 +49,14 @@ func (c *Client) createPolicyOnce(ctx context.Context, input types.CreatePolicyR
 		return types.Policy{}, err
 	}
 	var ans types.Policy
-	_, err = c.internalClient.Do(ctx, http.MethodPost, PoliciesEndpoint, nil, nil, body, &ans, nil)
+	_, err = c.internalClient.Do(ctx, http.MethodPost, PoliciesEndpoint, nil, nil, body, &ans, &client.DoOptions{Operation: "CreatePolicy"})
 	return ans, err
 }
 
 // GetPolicy retrieves a policy by ID.
 func (c *Client) GetPolicy(ctx context.Context, policyID string) (types.Policy, error) {
 	var ans types.Policy
-	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesEndpoint, &[]string{policyID}, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesEndpoint, &[]string{policyID}, nil, nil, &ans, &client.DoOptions{Operation: "GetPolicy"})
 	return ans, err
 }
 
@@ -66,7 +67,7 @@ func (c *Client) ListPolicies(ctx context.Context, input types.ListPoliciesReque
 	queryValues := input.ToQueryValues()
 
 	var ans []types.Policy
-	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesEndpoint, nil, &queryValues, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesEndpoint, nil, &queryValues, nil, &ans, &client.DoOptions{Operation: "ListPolicies"})
 	return ans, err
 }
 
@@ -99,14 +100,14 @@ func (c *Client) updatePolicyOnce(ctx context.Context, policyID string, input ty
 		return types.Policy{}, err
 	}
 	var ans types.Policy
-	_, err = c.internalClient.Do(ctx, http.MethodPut, PoliciesEndpoint, &[]string{policyID}, nil, body, &ans, nil)
+	_, err = c.internalClient.Do(ctx, http.MethodPut, PoliciesEndpoint, &[]string{policyID}, nil, body, &ans, &client.DoOptions{Operation: "UpdatePolicy"})
 	return ans, err
 }
 
 // DeletePolicy deletes the specified Application Security policy.
 func (c *Client) DeletePolicy(ctx context.Context, policyID string) error {
 	var ans types.DeletePolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, PoliciesEndpoint, &[]string{policyID}, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, PoliciesEndpoint, &[]string{policyID}, nil, nil, &ans, &client.DoOptions{Operation: "DeletePolicy"})
 	return err
 }
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
 	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/appsec"
 )
 
@@ -28,7 +29,7 @@ import (
 // "Validate Code" button in the rule definition creation screen.
 func (c *Client) Validate(ctx context.Context, input []types.ValidateRequest) (types.ValidateResponse, error) {
 	var ans types.ValidateResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, RulesValidationEndpoint, nil, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, RulesValidationEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "Validate"})
 
 	return ans, err
 }
@@ -41,7 +42,7 @@ func (c *Client) Validate(ctx context.Context, input []types.ValidateRequest) (t
 // Otherwise, a new rule will be created with the provided input values.
 func (c *Client) CreateOrClone(ctx context.Context, input types.CreateOrCloneRequest) (types.Rule, error) {
 	var ans types.Rule
-	_, err := c.internalClient.Do(ctx, http.MethodPost, RulesEndpoint, nil, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, RulesEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "CreateOrClone"})
 
 	return ans, err
 }
@@ -50,7 +51,7 @@ func (c *Client) CreateOrClone(ctx context.Context, input types.CreateOrCloneReq
 // ID value.
 func (c *Client) Get(ctx context.Context, id string) (types.Rule, error) {
 	var ans types.Rule
-	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesEndpoint, &[]string{id}, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesEndpoint, &[]string{id}, nil, nil, &ans, &client.DoOptions{Operation: "Get"})
 
 	return ans, err
 }
@@ -58,7 +59,7 @@ func (c *Client) Get(ctx context.Context, id string) (types.Rule, error) {
 // GetLabels retrieves all available Application Security rule labels.
 func (c *Client) GetLabels(ctx context.Context) (types.GetLabelsResponse, error) {
 	var ans types.GetLabelsResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesLabelsEndpoint, nil, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesLabelsEndpoint, nil, nil, nil, &ans, &client.DoOptions{Operation: "GetLabels"})
 
 	return ans, err
 }
@@ -71,7 +72,7 @@ func (c *Client) List(ctx context.Context, input types.ListRequest) (types.ListR
 	queryValues := input.ToQueryValues()
 
 	var ans types.ListResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesEndpoint, nil, &queryValues, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, RulesEndpoint, nil, &queryValues, nil, &ans, &client.DoOptions{Operation: "List"})
 
 	return ans, err
 }
@@ -90,14 +91,14 @@ func (c *Client) List(ctx context.Context, input types.ListRequest) (types.ListR
 func (c *Client) Update(ctx context.Context, ruleId string, input types.UpdateRequest) (types.UpdateResponse, error) {
 	var ans types.UpdateResponse
 
-	_, err := c.internalClient.Do(ctx, http.MethodPatch, RulesEndpoint, &[]string{ruleId}, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPatch, RulesEndpoint, &[]string{ruleId}, nil, input, &ans, &client.DoOptions{Operation: "Update"})
 
 	return ans, err
 }
 
 // Delete deletes the specified Application Security rule.
 func (c *Client) Delete(ctx context.Context, id string) error {
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, RulesEndpoint, &[]string{id}, nil, nil, nil, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, RulesEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{Operation: "Delete"})
 
 	return err
 }
@@ -0,0 +1,80 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// nonceLength is the length of the nonce of advanced API key signatures.
+	nonceLength = 64
+	// nonceCharset is the character set of the nonce.
+	nonceCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Standard authenticates requests with a standard API key, which is sent as
+// is in the Authorization header.
+type Standard struct {
+	APIKey   string
+	APIKeyID int
+}
+
+// Authenticate sets the Authorization and x-xdr-auth-id headers of req.
+func (s Standard) Authenticate(req *http.Request) error {
+	req.Header.Set(HeaderAuthID, strconv.Itoa(s.APIKeyID))
+	req.Header.Set(HeaderAuthorization, s.APIKey)
+	return nil
+}
+
+// Advanced authenticates requests with an advanced API key, which signs a
+// nonce and the current time: the Authorization header is the hex-encoded
+// SHA-256 digest of the key, the nonce and the timestamp in milliseconds.
+// The tenant rejects signatures whose timestamp is too far off its clock.
+type Advanced struct {
+	APIKey   string
+	APIKeyID int
+
+	// Now returns the time of the signatures; it defaults to time.Now.
+	Now func() time.Time
+}
+
+// Authenticate sets the Authorization, x-xdr-auth-id, x-xdr-nonce and
+// x-xdr-timestamp headers of req.
+func (a Advanced) Authenticate(req *http.Request) error {
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if a.Now != nil {
+		now = a.Now
+	}
+	timestamp := strconv.FormatInt(now().UnixMilli(), 10)
+	digest := sha256.Sum256([]byte(a.APIKey + nonce + timestamp))
+
+	req.Header.Set(HeaderAuthID, strconv.Itoa(a.APIKeyID))
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderAuthorization, hex.EncodeToString(digest[:]))
+	return nil
+}
+
+// newNonce returns a random nonce of nonceLength alphanumeric characters.
+func newNonce() (string, error) {
+	nonce := make([]byte, nonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	for i, b := range nonce {
+		nonce[i] = nonceCharset[b%byte(len(nonceCharset))]
+	}
+	return string(nonce), nil
+}
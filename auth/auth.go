@@ -0,0 +1,102 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package auth signs requests to the Cortex Cloud API.
+//
+// The SDK's clients sign their requests with the Authenticator matching the
+// type of their API key. The same authenticators can sign requests sent by
+// any other HTTP client, either directly or through a Transport:
+//
+//	authenticator, err := auth.New(credentials.Credentials{APIKey: key, APIKeyID: 42})
+//	httpClient := &http.Client{Transport: auth.NewTransport(authenticator, nil)}
+//	resp, err := httpClient.Post(apiURL+"/public_api/v1/endpoint/", "application/json", body)
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+)
+
+// The headers that carry the authentication of a request.
+const (
+	HeaderAuthorization = "Authorization"
+	HeaderAuthID        = "x-xdr-auth-id"
+	HeaderNonce         = "x-xdr-nonce"
+	HeaderTimestamp     = "x-xdr-timestamp"
+)
+
+// The types of API keys.
+const (
+	KeyTypeStandard = "standard"
+	KeyTypeAdvanced = "advanced"
+)
+
+// Authenticator signs requests to the Cortex Cloud API.
+type Authenticator interface {
+	// Authenticate sets the authentication headers of req, which must be
+	// sent right away: signatures may only be valid for a short time. The
+	// context of req bounds any work needed to obtain credentials.
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc is an Authenticator implemented by a function.
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error { return f(req) }
+
+// Option configures the authenticators returned by New and NewFromProvider.
+type Option func(*options)
+
+type options struct {
+	now            func() time.Time
+	defaultKeyType string
+}
+
+// WithClock sets the clock whose time advanced API key signatures carry,
+// e.g. one corrected for the offset to the tenant's clock. It defaults to
+// time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithDefaultKeyType sets the type of the API keys of credentials that do not
+// set one, e.g. the key type of a client configuration. It defaults to
+// advanced.
+func WithDefaultKeyType(keyType string) Option {
+	return func(o *options) {
+		o.defaultKeyType = keyType
+	}
+}
+
+// New returns the authenticator of the API key of creds: Standard or
+// Advanced depending on its type, which defaults to the one set by
+// WithDefaultKeyType.
+func New(creds credentials.Credentials, opts ...Option) (Authenticator, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newAuthenticator(creds, o)
+}
+
+func newAuthenticator(creds credentials.Credentials, o options) (Authenticator, error) {
+	keyType := creds.APIKeyType
+	if keyType == "" {
+		keyType = o.defaultKeyType
+	}
+	switch strings.ToLower(keyType) {
+	case KeyTypeStandard:
+		return Standard{APIKey: creds.APIKey, APIKeyID: creds.APIKeyID}, nil
+	case KeyTypeAdvanced, "":
+		return Advanced{APIKey: creds.APIKey, APIKeyID: creds.APIKeyID, Now: o.now}, nil
+	default:
+		return nil, fmt.Errorf("unsupported API key type %q", keyType)
+	}
+}
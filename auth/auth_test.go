@@ -0,0 +1,143 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com/public_api/v1/test", nil)
+	require.NoError(t, err)
+	return req
+}
+
+func TestNew(t *testing.T) {
+	for keyType, expected := range map[string]Authenticator{
+		"standard": Standard{APIKey: "key", APIKeyID: 7},
+		"Standard": Standard{APIKey: "key", APIKeyID: 7},
+		"advanced": Advanced{APIKey: "key", APIKeyID: 7},
+		"":         Advanced{APIKey: "key", APIKeyID: 7},
+	} {
+		authenticator, err := New(credentials.Credentials{APIKey: "key", APIKeyID: 7, APIKeyType: keyType})
+		require.NoError(t, err)
+		assert.Equal(t, expected, authenticator, keyType)
+	}
+
+	_, err := New(credentials.Credentials{APIKey: "key", APIKeyID: 7, APIKeyType: "oauth"})
+	assert.ErrorContains(t, err, `unsupported API key type "oauth"`)
+
+	t.Run("should use the default key type of credentials without one", func(t *testing.T) {
+		authenticator, err := New(credentials.Credentials{APIKey: "key", APIKeyID: 7}, WithDefaultKeyType("standard"))
+		require.NoError(t, err)
+		assert.Equal(t, Standard{APIKey: "key", APIKeyID: 7}, authenticator)
+
+		authenticator, err = New(credentials.Credentials{APIKey: "key", APIKeyID: 7, APIKeyType: "advanced"}, WithDefaultKeyType("standard"))
+		require.NoError(t, err)
+		assert.Equal(t, Advanced{APIKey: "key", APIKeyID: 7}, authenticator)
+	})
+}
+
+func TestStandard(t *testing.T) {
+	req := newRequest(t)
+	require.NoError(t, Standard{APIKey: "key", APIKeyID: 7}.Authenticate(req))
+	assert.Equal(t, "key", req.Header.Get(HeaderAuthorization))
+	assert.Equal(t, "7", req.Header.Get(HeaderAuthID))
+	assert.Empty(t, req.Header.Get(HeaderNonce))
+}
+
+func TestAdvanced(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	authenticator, err := New(credentials.Credentials{APIKey: "key", APIKeyID: 7}, WithClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	req := newRequest(t)
+	require.NoError(t, authenticator.Authenticate(req))
+
+	nonce := req.Header.Get(HeaderNonce)
+	assert.Len(t, nonce, nonceLength)
+	assert.Equal(t, "1700000000123", req.Header.Get(HeaderTimestamp))
+	assert.Equal(t, "7", req.Header.Get(HeaderAuthID))
+	digest := sha256.Sum256([]byte("key" + nonce + "1700000000123"))
+	assert.Equal(t, hex.EncodeToString(digest[:]), req.Header.Get(HeaderAuthorization))
+
+	other := newRequest(t)
+	require.NoError(t, authenticator.Authenticate(other))
+	assert.NotEqual(t, nonce, other.Header.Get(HeaderNonce))
+}
+
+func TestBearer(t *testing.T) {
+	t.Run("should send the static token", func(t *testing.T) {
+		req := newRequest(t)
+		require.NoError(t, Bearer{Token: "token"}.Authenticate(req))
+		assert.Equal(t, "Bearer token", req.Header.Get(HeaderAuthorization))
+	})
+
+	t.Run("should get a token for each request", func(t *testing.T) {
+		type key struct{}
+		bearer := Bearer{TokenFunc: func(ctx context.Context) (string, error) {
+			return ctx.Value(key{}).(string), nil
+		}}
+		req := newRequest(t).WithContext(context.WithValue(context.Background(), key{}, "fresh"))
+		require.NoError(t, bearer.Authenticate(req))
+		assert.Equal(t, "Bearer fresh", req.Header.Get(HeaderAuthorization))
+	})
+
+	t.Run("should fail without a token", func(t *testing.T) {
+		assert.ErrorContains(t, Bearer{}.Authenticate(newRequest(t)), "bearer token not set")
+
+		failing := Bearer{TokenFunc: func(context.Context) (string, error) { return "", errors.New("boom") }}
+		assert.ErrorContains(t, failing.Authenticate(newRequest(t)), "failed to get bearer token: boom")
+	})
+}
+
+// expiringProvider returns expired credentials until it is expired.
+type expiringProvider struct {
+	creds   credentials.Credentials
+	expired int
+}
+
+func (p *expiringProvider) Retrieve(context.Context) (credentials.Credentials, error) {
+	creds := p.creds
+	if p.expired == 0 {
+		creds.Expires = time.Now().Add(-time.Minute)
+	}
+	return creds, nil
+}
+
+func (p *expiringProvider) Expire() { p.expired++ }
+
+func TestNewFromProvider(t *testing.T) {
+	t.Run("should sign with the current credentials", func(t *testing.T) {
+		provider := &expiringProvider{creds: credentials.Credentials{APIKey: "key", APIKeyID: 7, APIKeyType: "standard"}}
+		req := newRequest(t)
+		require.NoError(t, NewFromProvider(provider).Authenticate(req))
+		assert.Equal(t, "key", req.Header.Get(HeaderAuthorization))
+		assert.Equal(t, 1, provider.expired)
+	})
+
+	t.Run("should use the default key type of credentials without one", func(t *testing.T) {
+		provider := &expiringProvider{creds: credentials.Credentials{APIKey: "key", APIKeyID: 7}, expired: 1}
+		req := newRequest(t)
+		require.NoError(t, NewFromProvider(provider, WithDefaultKeyType("standard")).Authenticate(req))
+		assert.Equal(t, "key", req.Header.Get(HeaderAuthorization))
+		assert.Empty(t, req.Header.Get(HeaderNonce))
+	})
+
+	t.Run("should fail on missing credentials", func(t *testing.T) {
+		err := NewFromProvider(credentials.NewChain()).Authenticate(newRequest(t))
+		assert.ErrorIs(t, err, credentials.ErrNotFound)
+	})
+}
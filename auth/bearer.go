@@ -0,0 +1,38 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Bearer authenticates requests with a bearer token, such as an OAuth 2.0
+// access token, sent in the Authorization header.
+type Bearer struct {
+	// Token is the token of every request, unless TokenFunc is set.
+	Token string
+
+	// TokenFunc, if set, returns the token of each request, so that
+	// short-lived tokens can be refreshed. It is called with the context of
+	// the request.
+	TokenFunc func(ctx context.Context) (string, error)
+}
+
+// Authenticate sets the Authorization header of req.
+func (b Bearer) Authenticate(req *http.Request) error {
+	token := b.Token
+	if b.TokenFunc != nil {
+		var err error
+		if token, err = b.TokenFunc(req.Context()); err != nil {
+			return fmt.Errorf("failed to get bearer token: %w", err)
+		}
+	}
+	if token == "" {
+		return fmt.Errorf("bearer token not set")
+	}
+	req.Header.Set(HeaderAuthorization, "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+)
+
+// providerAuthenticator signs each request with the current credentials of
+// a credentials provider.
+type providerAuthenticator struct {
+	provider credentials.Provider
+	options  options
+}
+
+// NewFromProvider returns an authenticator signing each request with the API
+// key retrieved from provider at that time, so that rotated keys are picked
+// up. Expired credentials are retrieved again once before failing with
+// credentials.ErrExpired.
+func NewFromProvider(provider credentials.Provider, opts ...Option) Authenticator {
+	a := &providerAuthenticator{provider: provider}
+	for _, opt := range opts {
+		opt(&a.options)
+	}
+	return a
+}
+
+// Authenticate sets the authentication headers of req for the current
+// credentials of the provider.
+func (a *providerAuthenticator) Authenticate(req *http.Request) error {
+	creds, err := a.provider.Retrieve(req.Context())
+	if err == nil && creds.Expired() {
+		a.provider.Expire()
+		creds, err = a.provider.Retrieve(req.Context())
+		if err == nil && creds.Expired() {
+			err = credentials.ErrExpired
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	authenticator, err := newAuthenticator(creds, a.options)
+	if err != nil {
+		return err
+	}
+	return authenticator.Authenticate(req)
+}
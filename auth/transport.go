@@ -0,0 +1,41 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"net/http"
+)
+
+// Transport is an http.RoundTripper that signs every request with its
+// Authenticator before sending it through its Base transport. Each retry of
+// a request goes through RoundTrip again, and so is signed anew.
+type Transport struct {
+	Authenticator Authenticator
+	// Base sends the signed requests; it defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewTransport returns a Transport signing requests with authenticator and
+// sending them through base, or http.DefaultTransport if base is nil.
+func NewTransport(authenticator Authenticator, base http.RoundTripper) *Transport {
+	return &Transport{Authenticator: authenticator, Base: base}
+}
+
+// RoundTrip signs a copy of req and sends it.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it is given
+	signed := req.Clone(req.Context())
+	if err := t.Authenticator.Authenticate(signed); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
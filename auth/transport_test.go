@@ -0,0 +1,49 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("should sign a copy of each request", func(t *testing.T) {
+		client := &http.Client{Transport: NewTransport(Standard{APIKey: "key", APIKeyID: 7}, nil)}
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+		require.NoError(t, err)
+		req.Header.Set("X-Custom", "1")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, "key", received.Get(HeaderAuthorization))
+		assert.Equal(t, "7", received.Get(HeaderAuthID))
+		assert.Equal(t, "1", received.Get("X-Custom"))
+		assert.Empty(t, req.Header.Get(HeaderAuthorization))
+	})
+
+	t.Run("should not send requests it fails to sign", func(t *testing.T) {
+		received = nil
+		failing := AuthenticatorFunc(func(*http.Request) error { return errors.New("no key") })
+		client := &http.Client{Transport: NewTransport(failing, http.DefaultTransport)}
+
+		_, err := client.Get(server.URL)
+		assert.ErrorContains(t, err, "no key")
+		assert.Nil(t, received)
+	})
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package circuit provides a circuit breaker that stops SDK clients from
+// hammering a Cortex tenant that is down.
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultThreshold is the number of consecutive failures that trips a
+	// Breaker created without WithThreshold.
+	DefaultThreshold = 5
+	// DefaultCooldown is how long a Breaker created without WithCooldown
+	// stays open before probing the tenant.
+	DefaultCooldown = 30 * time.Second
+)
+
+// ErrOpen is matched by errors.Is for every error returned while a Breaker
+// rejects requests.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// StateClosed lets every request through.
+	StateClosed State = iota
+	// StateOpen rejects every request until the cooldown elapses.
+	StateOpen
+	// StateHalfOpen lets a single probe request through to decide whether
+	// the breaker closes again.
+	StateHalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Probe selects the request used to check whether the tenant has recovered.
+type Probe int
+
+const (
+	// ProbeValidateAPIKey probes the tenant with the API key validation
+	// endpoint.
+	ProbeValidateAPIKey Probe = iota
+	// ProbeHealthCheck probes the tenant with the health check endpoint.
+	ProbeHealthCheck
+)
+
+// OpenError is returned while a Breaker rejects requests.
+type OpenError struct {
+	State    State     // The state the breaker was in when the request was rejected.
+	Failures int       // The number of consecutive failures that tripped the breaker.
+	RetryAt  time.Time // The earliest time the tenant will be probed again.
+}
+
+// Error implements the error interface.
+func (e *OpenError) Error() string {
+	if e.State == StateHalfOpen {
+		return "circuit breaker is half-open and waiting for the probe request to complete"
+	}
+	return fmt.Sprintf("circuit breaker is open after %d consecutive failure(s), next probe at %s",
+		e.Failures, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrOpen.
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Breaker tracks consecutive failed attempts against a tenant. Once the
+// threshold is reached it opens and rejects requests until the cooldown
+// elapses, after which it lets a single probe through: a successful probe
+// closes the breaker, a failed one opens it for another cooldown.
+//
+// A Breaker is safe for concurrent use. Pass the same instance to every
+// module client of a tenant through the WithCircuitBreaker option so that
+// they trip together.
+type Breaker struct {
+	mu sync.Mutex
+
+	threshold     int
+	cooldown      time.Duration
+	probe         Probe
+	onStateChange func(from, to State)
+
+	state    State
+	failures int
+	openedAt time.Time
+
+	now func() time.Time
+}
+
+// Option configures a Breaker.
+type Option func(*Breaker)
+
+// WithThreshold returns an Option that sets the number of consecutive
+// failures that trips the breaker.
+func WithThreshold(n int) Option {
+	return func(b *Breaker) {
+		b.threshold = max(n, 1)
+	}
+}
+
+// WithCooldown returns an Option that sets how long the breaker stays open
+// before probing the tenant.
+func WithCooldown(d time.Duration) Option {
+	return func(b *Breaker) {
+		b.cooldown = d
+	}
+}
+
+// WithProbe returns an Option that sets the request used to probe the
+// tenant while the breaker is half-open.
+func WithProbe(p Probe) Option {
+	return func(b *Breaker) {
+		b.probe = p
+	}
+}
+
+// WithStateChangeFunc returns an Option that sets a function called after
+// every state transition. It is called synchronously and must not block.
+func WithStateChangeFunc(fn func(from, to State)) Option {
+	return func(b *Breaker) {
+		b.onStateChange = fn
+	}
+}
+
+// New returns a closed Breaker configured with the given options.
+func New(opts ...Option) *Breaker {
+	b := &Breaker{
+		threshold: DefaultThreshold,
+		cooldown:  DefaultCooldown,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Probe returns the request used to probe the tenant.
+func (b *Breaker) Probe() Probe {
+	return b.probe
+}
+
+// Err returns an *OpenError if the breaker is not closed, or nil otherwise.
+func (b *Breaker) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err()
+}
+
+// Allow reports whether a request may be sent. It returns an *OpenError if
+// the breaker rejects the request. If the cooldown has elapsed, the breaker
+// moves to half-open and probe is true: the caller must then send the probe
+// request and report its outcome through Success or Failure.
+func (b *Breaker) Allow() (probe bool, err error) {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateOpen:
+		if b.now().Before(b.openedAt.Add(b.cooldown)) {
+			err = b.err()
+			break
+		}
+		b.state = StateHalfOpen
+		probe = true
+	case StateHalfOpen:
+		err = b.err()
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.transitioned(from, to)
+	return probe, err
+}
+
+// Success records a successful attempt. It closes a half-open breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateClosed:
+		b.failures = 0
+	case StateHalfOpen:
+		b.state = StateClosed
+		b.failures = 0
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.transitioned(from, to)
+}
+
+// Failure records a failed attempt. It opens a closed breaker once the
+// threshold is reached, and reopens a half-open breaker.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	case StateHalfOpen:
+		b.open()
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.transitioned(from, to)
+}
+
+// open moves the breaker to the open state. Callers must hold b.mu.
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+}
+
+// err returns the error reported for the current state. Callers must hold
+// b.mu.
+func (b *Breaker) err() error {
+	if b.state == StateClosed {
+		return nil
+	}
+	return &OpenError{
+		State:    b.state,
+		Failures: b.failures,
+		RetryAt:  b.openedAt.Add(b.cooldown),
+	}
+}
+
+// transitioned calls the state change function if the state changed.
+func (b *Breaker) transitioned(from, to State) {
+	if from != to && b.onStateChange != nil {
+		b.onStateChange(from, to)
+	}
+}
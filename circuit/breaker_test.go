@@ -0,0 +1,101 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transition struct{ from, to State }
+
+func newTestBreaker(now *time.Time, transitions *[]transition, opts ...Option) *Breaker {
+	opts = append(opts, WithStateChangeFunc(func(from, to State) {
+		*transitions = append(*transitions, transition{from, to})
+	}))
+	b := New(opts...)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestBreaker_TripsAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions, WithThreshold(3), WithCooldown(time.Minute))
+
+	b.Failure()
+	b.Failure()
+	b.Success() // resets the count
+	b.Failure()
+	b.Failure()
+	assert.Equal(t, StateClosed, b.State())
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, []transition{{StateClosed, StateOpen}}, transitions)
+
+	probe, err := b.Allow()
+	assert.False(t, probe)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrOpen)
+
+	var openErr *OpenError
+	require.True(t, errors.As(err, &openErr))
+	assert.Equal(t, 3, openErr.Failures)
+	assert.Equal(t, now.Add(time.Minute), openErr.RetryAt)
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var transitions []transition
+	b := newTestBreaker(&now, &transitions, WithThreshold(1), WithCooldown(time.Minute))
+
+	b.Failure()
+	now = now.Add(time.Minute)
+
+	probe, err := b.Allow()
+	require.NoError(t, err)
+	assert.True(t, probe)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	// Only one probe is let through at a time.
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrOpen)
+
+	t.Run("failed probe reopens the breaker", func(t *testing.T) {
+		b.Failure()
+		assert.Equal(t, StateOpen, b.State())
+		_, err := b.Allow()
+		assert.ErrorIs(t, err, ErrOpen)
+	})
+
+	t.Run("successful probe closes the breaker", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		probe, err := b.Allow()
+		require.NoError(t, err)
+		require.True(t, probe)
+
+		b.Success()
+		assert.Equal(t, StateClosed, b.State())
+		assert.NoError(t, b.Err())
+	})
+
+	assert.Equal(t, []transition{
+		{StateClosed, StateOpen},
+		{StateOpen, StateHalfOpen},
+		{StateHalfOpen, StateOpen},
+		{StateOpen, StateHalfOpen},
+		{StateHalfOpen, StateClosed},
+	}, transitions)
+}
+
+func TestState_String(t *testing.T) {
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "State(7)", State(7).String())
+}
@@ -32,6 +32,7 @@ func (c *Client) ListCloudAccountsByInstance(ctx context.Context, instanceID str
 		resp listCloudAccountsByInstanceResponse
 	)
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListAccountsByInstanceEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "ListCloudAccountsByInstance",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -46,14 +47,14 @@ type enableDisableAccountsInInstancesRequest struct {
 }
 
 func (c *Client) EnableCloudAccounts(ctx context.Context, instanceID string, accountIDs []string) error {
-	return c.toggleCloudAccounts(ctx, instanceID, accountIDs, true)
+	return c.toggleCloudAccounts(ctx, "EnableCloudAccounts", instanceID, accountIDs, true)
 }
 
 func (c *Client) DisableCloudAccounts(ctx context.Context, instanceID string, accountIDs []string) error {
-	return c.toggleCloudAccounts(ctx, instanceID, accountIDs, false)
+	return c.toggleCloudAccounts(ctx, "DisableCloudAccounts", instanceID, accountIDs, false)
 }
 
-func (c *Client) toggleCloudAccounts(ctx context.Context, instanceIDs string, accountIDs []string, enable bool) error {
+func (c *Client) toggleCloudAccounts(ctx context.Context, operation, instanceIDs string, accountIDs []string, enable bool) error {
 	req := enableDisableAccountsInInstancesRequest{
 		InstanceId: instanceIDs,
 		Ids:        accountIDs,
@@ -61,6 +62,7 @@ func (c *Client) toggleCloudAccounts(ctx context.Context, instanceIDs string, ac
 	}
 
 	_, err := c.internalClient.Do(ctx, http.MethodPost, EnableDisableAccountsInInstancesEndpoint, nil, nil, req, nil, &client.DoOptions{
+		Operation:           operation,
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -15,6 +15,7 @@ import (
 func (c *Client) CreateIntegrationTemplate(ctx context.Context, input *types.CreateIntegrationTemplateRequest) (types.CreateTemplateOrEditIntegrationInstanceResponse, error) {
 	var ans types.CreateTemplateOrEditIntegrationInstanceResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateIntegrationTemplateEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "CreateIntegrationTemplate",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -25,6 +26,7 @@ func (c *Client) CreateIntegrationTemplate(ctx context.Context, input *types.Cre
 func (c *Client) GetIntegrationInstanceDetails(ctx context.Context, instanceID string) (types.IntegrationInstance, error) {
 	var ans types.GetIntegrationInstanceResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetIntegrationInstanceDetailsEndpoint, nil, nil, types.NewGetIntegrationInstanceRequest(instanceID), &ans, &client.DoOptions{
+		Operation:           "GetIntegrationInstanceDetails",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -38,6 +40,7 @@ func (c *Client) GetIntegrationInstanceDetails(ctx context.Context, instanceID s
 func (c *Client) ListIntegrationInstances(ctx context.Context, input *types.ListIntegrationInstancesRequest) ([]types.IntegrationInstance, error) {
 	var ans types.ListIntegrationInstancesResponseWrapper
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListIntegrationInstancesEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "ListIntegrationInstances",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -49,6 +52,7 @@ func (c *Client) ListIntegrationInstances(ctx context.Context, input *types.List
 func (c *Client) EditIntegrationInstance(ctx context.Context, input *types.EditIntegrationInstanceRequest) (types.CreateTemplateOrEditIntegrationInstanceResponse, error) {
 	var ans types.CreateTemplateOrEditIntegrationInstanceResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, EditIntegrationInstanceEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "EditIntegrationInstance",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -58,6 +62,7 @@ func (c *Client) EditIntegrationInstance(ctx context.Context, input *types.EditI
 
 func (c *Client) EnableIntegrationInstances(ctx context.Context, instanceIDs []string) error {
 	_, err := c.internalClient.Do(ctx, http.MethodPost, EnableOrDisableIntegrationInstancesEndpoint, nil, nil, types.NewEnableOrDisableIntegrationInstancesRequest(instanceIDs, true), nil, &client.DoOptions{
+		Operation:          "EnableIntegrationInstances",
 		RequestWrapperKeys: []string{"request_data"},
 	})
 	return err
@@ -65,6 +70,7 @@ func (c *Client) EnableIntegrationInstances(ctx context.Context, instanceIDs []s
 
 func (c *Client) DisableIntegrationInstances(ctx context.Context, instanceIDs []string) error {
 	_, err := c.internalClient.Do(ctx, http.MethodPost, EnableOrDisableIntegrationInstancesEndpoint, nil, nil, types.NewEnableOrDisableIntegrationInstancesRequest(instanceIDs, false), nil, &client.DoOptions{
+		Operation:          "DisableIntegrationInstances",
 		RequestWrapperKeys: []string{"request_data"},
 	})
 	return err
@@ -72,6 +78,7 @@ func (c *Client) DisableIntegrationInstances(ctx context.Context, instanceIDs []
 
 func (c *Client) DeleteIntegrationInstances(ctx context.Context, instanceIDs []string) error {
 	_, err := c.internalClient.Do(ctx, http.MethodPost, DeleteIntegrationInstancesEndpoint, nil, nil, types.NewDeleteIntegrationInstanceRequest(instanceIDs), nil, &client.DoOptions{
+		Operation:          "DeleteIntegrationInstances",
 		RequestWrapperKeys: []string{"request_data"},
 	})
 	return err
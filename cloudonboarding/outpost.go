@@ -15,6 +15,7 @@ import (
 func (c *Client) CreateOutpostTemplate(ctx context.Context, input *types.CreateOutpostTemplateRequest) (*types.CreateTemplateOrEditIntegrationInstanceResponse, error) {
 	var ans types.CreateTemplateOrEditIntegrationInstanceResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateOutpostTemplateEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "CreateOutpostTemplate",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -27,6 +28,7 @@ func (c *Client) CreateOutpostTemplate(ctx context.Context, input *types.CreateO
 // UpdateOutpost updates an existing Outpost.
 func (c *Client) UpdateOutpost(ctx context.Context, input *types.UpdateOutpostRequest) error {
 	_, err := c.internalClient.Do(ctx, http.MethodPost, UpdateOutpostEndpoint, nil, nil, input, nil, &client.DoOptions{
+		Operation:          "UpdateOutpost",
 		RequestWrapperKeys: []string{"request_data"},
 	})
 	return err
@@ -36,6 +38,7 @@ func (c *Client) UpdateOutpost(ctx context.Context, input *types.UpdateOutpostRe
 func (c *Client) ListOutposts(ctx context.Context, input *types.ListOutpostsRequest) (*types.ListOutpostsResponse, error) {
 	var ans types.ListOutpostsResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListOutpostsEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "ListOutposts",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
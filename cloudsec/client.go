@@ -44,12 +44,32 @@ var (
 	WithCortexAPIKeyID = config.WithCortexAPIKeyID
 	// WithCortexAPIKeyType is an option to set the Cortex API key type.
 	WithCortexAPIKeyType = config.WithCortexAPIKeyType
+	// WithCredentialsProvider is an option to load the API key and key ID from a credentials provider on every request.
+	WithCredentialsProvider = config.WithCredentialsProvider
+	// WithAuthenticator is an option to sign requests with a custom authenticator, such as a bearer token.
+	WithAuthenticator = config.WithAuthenticator
 	// WithCortexAPIPort is an option to set the Cortex API port.
 	WithHeaders = config.WithHeaders
 	// WithAgent is an option to set the user agent.
 	WithAgent = config.WithAgent
 	// WithSkipSSLVerify is an option to skip TLS certificate verification.
 	WithSkipSSLVerify = config.WithSkipSSLVerify
+	// WithCABundle is an option to trust additional PEM-encoded CA certificates.
+	WithCABundle = config.WithCABundle
+	// WithCABundleFile is an option to trust the CA certificates of a PEM file.
+	WithCABundleFile = config.WithCABundleFile
+	// WithClientCertificate is an option to set the client certificate for mutual TLS.
+	WithClientCertificate = config.WithClientCertificate
+	// WithClientCertificateFile is an option to load the client certificate for mutual TLS from PEM files.
+	WithClientCertificateFile = config.WithClientCertificateFile
+	// WithMinTLSVersion is an option to set the minimum TLS version.
+	WithMinTLSVersion = config.WithMinTLSVersion
+	// WithProxy is an option to set the HTTP proxy URL.
+	WithProxy = config.WithProxy
+	// WithProxyAuth is an option to set the proxy credentials.
+	WithProxyAuth = config.WithProxyAuth
+	// WithPinnedPublicKeys is an option to pin the public keys of the tenant certificate chain.
+	WithPinnedPublicKeys = config.WithPinnedPublicKeys
 	// WithTransport is an option to set the HTTP transport.
 	WithTransport = config.WithTransport
 	// WithTimeout is an option to set the HTTP timeout.
@@ -58,16 +78,80 @@ var (
 	WithMaxRetries = config.WithMaxRetries
 	// WithRetryMaxDelay is an option to set the maximum retry delay.
 	WithRetryMaxDelay = config.WithRetryMaxDelay
+	// WithMaxResponseSize is an option to set the maximum response size in bytes.
+	WithMaxResponseSize = config.WithMaxResponseSize
+	// WithCompression is an option to gzip request bodies and negotiate gzip-encoded responses.
+	WithCompression = config.WithCompression
+	// WithUncompressedEndpoints is an option to exclude endpoints from request body compression.
+	WithUncompressedEndpoints = config.WithUncompressedEndpoints
 	// WithCrashStackDir is an option to set the crash stack directory.
 	WithCrashStackDir = config.WithCrashStackDir
+	// WithSkipCrashBundle is an option to skip writing the diagnostic bundle of a recovered panic.
+	WithSkipCrashBundle = config.WithSkipCrashBundle
 	// WithLogLevel is an option to set the log level.
 	WithLogLevel = config.WithLogLevel
 	// WithLogger is an option to set the logger.
 	WithLogger = config.WithLogger
 	// WithSkipLoggingTransport is an option to skip logging transport.
 	WithSkipLoggingTransport = config.WithSkipLoggingTransport
+	// WithLogRedactedFields is an option to redact body fields in debug dumps.
+	WithLogRedactedFields = config.WithLogRedactedFields
+	// WithLogDumpMaxSize is an option to set the maximum body size in debug dumps.
+	WithLogDumpMaxSize = config.WithLogDumpMaxSize
+	// WithLogDumpSampling is an option to sample the debug dumps of repeated exchanges.
+	WithLogDumpSampling = config.WithLogDumpSampling
+	// WithProfile is an option to select the profile of the configuration file.
+	WithProfile = config.WithProfile
+	// WithConfigFile is an option to select the configuration file.
+	WithConfigFile = config.WithConfigFile
+	// WithRetryPolicy is an option to set the retry policy.
+	WithRetryPolicy = config.WithRetryPolicy
+	// WithRetryBudget is an option to set the retry budget.
+	WithRetryBudget = config.WithRetryBudget
+	// WithRateLimiter is an option to set a client-side rate limiter, which may be shared across clients.
+	WithRateLimiter = config.WithRateLimiter
+	// WithCircuitBreaker is an option to set a circuit breaker, which may be shared across clients.
+	WithCircuitBreaker = config.WithCircuitBreaker
+	// WithInterceptors is an option to add interceptors called for every request attempt.
+	WithInterceptors = config.WithInterceptors
+	// WithTracer is an option to trace every call with a span per method and a child span per attempt.
+	WithTracer = config.WithTracer
+	// WithMetrics is an option to record request counts, latencies, retries and in-flight requests.
+	WithMetrics = config.WithMetrics
 )
 
+var (
+	// WithIdempotencyKey returns a context that attaches an idempotency key
+	// to calls made with it, allowing mutating requests to be retried safely.
+	// If the key is empty, a unique key is generated for each call.
+	WithIdempotencyKey = client.WithIdempotencyKey
+	// WithCallOptions returns a context that applies the given call options
+	// to calls made with it.
+	WithCallOptions = client.WithCallOptions
+	// WithCallHeader is a call option to set an extra HTTP header.
+	WithCallHeader = client.WithCallHeader
+	// WithCallTimeout is a call option to bound the whole call, including retries.
+	WithCallTimeout = client.WithCallTimeout
+	// WithAttemptTimeout is a call option to bound each attempt of the call.
+	WithAttemptTimeout = client.WithAttemptTimeout
+	// WithCallMaxRetries is a call option to override the maximum number of retries.
+	WithCallMaxRetries = client.WithCallMaxRetries
+	// WithCallRetryPolicy is a call option to override the retry policy.
+	WithCallRetryPolicy = client.WithCallRetryPolicy
+	// WithCallRequestID is a call option to set the request ID sent with the call.
+	WithCallRequestID = client.WithCallRequestID
+	// WithCallResponseMeta is a call option to capture the response metadata of the call.
+	WithCallResponseMeta = client.WithCallResponseMeta
+)
+
+// CallOption overrides the client configuration for a single call. Attach
+// call options to a context with WithCallOptions.
+type CallOption = client.CallOption
+
+// ResponseMeta describes how a call went on the wire: status, headers,
+// request IDs, attempts and timing. Capture it with WithCallResponseMeta.
+type ResponseMeta = client.ResponseMeta
+
 // Client is the client for the namespace.
 type Client struct {
 	internalClient *client.Client
@@ -84,22 +168,29 @@ func (c *Client) ValidateAPIKey(ctx context.Context) (bool, error) {
 
 // NewClient returns a new client for this namespace.
 func NewClient(opts ...Option) (*Client, error) {
-	// Prepend User-Agent option if not already set
-	userAgentOpt := config.WithAgent(version.UserAgent(ModuleName))
-	opts = append([]config.Option{userAgentOpt}, opts...)
+	// The module's user agent applies unless a setting of any layer sets one
+	opts = append([]config.Option{config.WithDefaultAgent(version.UserAgent(ModuleName))}, opts...)
 
 	cfg := config.NewConfig(opts...)
 	internalClient, err := client.NewClientFromConfig(cfg)
-	return &Client{internalClient: internalClient}, err
+	return &Client{internalClient: internalClient.ForModule(ModuleName)}, err
 }
 
-// NewClientFromFile creates a new client from a configuration file.
+// NewClientFromCore returns a client for this namespace that shares the given
+// core client, with its configuration and connections. It is used by the
+// root cortex package to build every module client on a single core client.
+func NewClientFromCore(core *client.Client) *Client {
+	return &Client{internalClient: core.ForModule(ModuleName)}
+}
+
+// NewClientFromFile creates a new client from a configuration file, in JSON,
+// YAML or TOML. Environment variables take precedence over the file.
 func NewClientFromFile(filepath string) (*Client, error) {
-	config, err := config.NewConfigFromFile(filepath)
+	c, err := NewClient(WithConfigFile(filepath))
 	if err != nil {
 		return nil, err
 	}
-	return NewClient(config.GetOptions()...)
+	return c, nil
 }
 
 // APIURL returns the API URL for the Cortex.
@@ -134,3 +225,11 @@ func (c *Client) Logger() log.Logger { return c.internalClient.Logger() }
 
 // SkipLoggingTransport returns whether to skip logging transport.
 func (c *Client) SkipLoggingTransport() bool { return c.internalClient.SkipLoggingTransport() }
+
+// Profile returns the name of the configuration file profile the settings
+// were loaded from, or an empty string if no profile was loaded.
+func (c *Client) Profile() string { return c.internalClient.Profile() }
+
+// ConfigSources returns where each setting came from ("default", "profile",
+// "env" or "option"), keyed by configuration file key such as "api_url".
+func (c *Client) ConfigSources() map[string]string { return c.internalClient.ConfigSources() }
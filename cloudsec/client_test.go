@@ -4,9 +4,13 @@
 package cloudsec
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +67,30 @@ func TestNewClientFromFile(t *testing.T) {
 		assert.Nil(t, client)
 	})
 }
+
+func TestClient_Tracing(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get(tracing.TraceparentHeader))
+		w.Write([]byte(`{"id":"policy-1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithCortexAPIURL(server.URL),
+		WithCortexAPIKey(TestAPIKey),
+		WithCortexAPIKeyID(TestAPIKeyID),
+		WithSkipLoggingTransport(true),
+		WithTracer(recorder),
+	)
+	require.NoError(t, err)
+
+	_, err = client.GetPolicy(context.Background(), "policy-1")
+	require.NoError(t, err)
+
+	spans := recorder.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "cloudsec.GetPolicy", spans[1].Name)
+	assert.Equal(t, ModuleName, spans[1].Attributes[tracing.AttrModule])
+	assert.Equal(t, GetPolicyEndpoint, spans[1].Attributes[tracing.AttrEndpoint])
+}
@@ -43,7 +43,7 @@ import (
 //	})
 func (c *Client) CreatePolicy(ctx context.Context, input types.PolicyCreateRequest) (types.PolicyResponse, error) {
 	var ans types.PolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, CreatePolicyEndpoint, nil, nil, input, &ans, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodPost, CreatePolicyEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "CreatePolicy"})
 
 	return ans, err
 }
@@ -55,7 +55,7 @@ func (c *Client) CreatePolicy(ctx context.Context, input types.PolicyCreateReque
 //	policy, err := client.GetPolicy(ctx, "a1b2c3d4-e5f6-7890-abcd-ef1234567890")
 func (c *Client) GetPolicy(ctx context.Context, id string) (types.PolicyResponse, error) {
 	var ans types.PolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, GetPolicyEndpoint, &[]string{id}, nil, nil, &ans, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodGet, GetPolicyEndpoint, &[]string{id}, nil, nil, &ans, &client.DoOptions{Operation: "GetPolicy"})
 
 	return ans, err
 }
@@ -90,7 +90,7 @@ func (c *Client) GetPolicy(ctx context.Context, id string) (types.PolicyResponse
 //	})
 func (c *Client) SearchPolicies(ctx context.Context, input types.SearchPoliciesRequest) (types.SearchPoliciesResponse, error) {
 	var ans types.SearchPoliciesResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, SearchPoliciesEndpoint, nil, nil, input, &ans, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodPost, SearchPoliciesEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "SearchPolicies"})
 
 	return ans, err
 }
@@ -117,7 +117,7 @@ func (c *Client) UpdatePolicy(ctx context.Context, input types.PolicyUpdateReque
 	}
 
 	var ans types.PolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPatch, UpdatePolicyEndpoint, &[]string{input.ID}, nil, input, &ans, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodPatch, UpdatePolicyEndpoint, &[]string{input.ID}, nil, input, &ans, &client.DoOptions{Operation: "UpdatePolicy"})
 
 	return ans, err
 }
@@ -131,7 +131,7 @@ func (c *Client) UpdatePolicy(ctx context.Context, input types.PolicyUpdateReque
 //
 //	err := client.DeletePolicy(ctx, "a1b2c3d4-e5f6-7890-abcd-ef1234567890")
 func (c *Client) DeletePolicy(ctx context.Context, id string) error {
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeletePolicyEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeletePolicyEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{Operation: "DeletePolicy"})
 
 	return err
 }
@@ -40,7 +40,7 @@ import (
 //	})
 func (c *Client) Create(ctx context.Context, input types.CreateRuleRequest) (types.RuleResponse, error) {
 	var ans types.RuleResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateRuleEndpoint, nil, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateRuleEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "Create"})
 
 	return ans, err
 }
@@ -52,7 +52,7 @@ func (c *Client) Create(ctx context.Context, input types.CreateRuleRequest) (typ
 //	rule, err := client.Get(ctx, "a1b2c3d4-e5f6-7890-abcd-ef1234567890")
 func (c *Client) Get(ctx context.Context, id string) (types.RuleResponse, error) {
 	var ans types.RuleResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, GetRuleEndpoint, &[]string{id}, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, GetRuleEndpoint, &[]string{id}, nil, nil, &ans, &client.DoOptions{Operation: "Get"})
 
 	return ans, err
 }
@@ -87,7 +87,7 @@ func (c *Client) Get(ctx context.Context, id string) (types.RuleResponse, error)
 //	})
 func (c *Client) Search(ctx context.Context, input types.SearchRulesRequest) (types.SearchRulesResponse, error) {
 	var ans types.SearchRulesResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, SearchRulesEndpoint, nil, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, SearchRulesEndpoint, nil, nil, input, &ans, &client.DoOptions{Operation: "Search"})
 
 	return ans, err
 }
@@ -108,7 +108,7 @@ func (c *Client) Search(ctx context.Context, input types.SearchRulesRequest) (ty
 //	})
 func (c *Client) Update(ctx context.Context, id string, input types.UpdateRuleRequest) (types.RuleResponse, error) {
 	var ans types.RuleResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPatch, UpdateRuleEndpoint, &[]string{id}, nil, input, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPatch, UpdateRuleEndpoint, &[]string{id}, nil, input, &ans, &client.DoOptions{Operation: "Update"})
 
 	return ans, err
 }
@@ -122,7 +122,7 @@ func (c *Client) Update(ctx context.Context, id string, input types.UpdateRuleRe
 //
 //	err := client.Delete(ctx, "a1b2c3d4-e5f6-7890-abcd-ef1234567890")
 func (c *Client) Delete(ctx context.Context, id string) error {
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeleteRuleEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{})
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeleteRuleEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{Operation: "Delete"})
 
 	return err
 }
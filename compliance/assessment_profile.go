@@ -19,6 +19,7 @@ import (
 func (c *Client) CreateAssessmentProfile(ctx context.Context, req types.CreateAssessmentProfileRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateAssessmentProfileEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateAssessmentProfile",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -29,6 +30,7 @@ func (c *Client) CreateAssessmentProfile(ctx context.Context, req types.CreateAs
 func (c *Client) GetAssessmentProfile(ctx context.Context, req types.GetAssessmentProfileRequest) (*types.AssessmentProfile, error) {
 	var resp types.GetAssessmentProfileResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetAssessmentProfileEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "GetAssessmentProfile",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -113,6 +115,7 @@ func (c *Client) UpdateAssessmentProfile(ctx context.Context, req types.UpdateAs
 
 	var resp commontypes.SuccessResponse
 	_, err = c.internalClient.Do(ctx, http.MethodPost, UpdateAssessmentProfileEndpoint, nil, nil, mergedReq, &resp, &client.DoOptions{
+		Operation:           "UpdateAssessmentProfile",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -123,6 +126,7 @@ func (c *Client) UpdateAssessmentProfile(ctx context.Context, req types.UpdateAs
 func (c *Client) DeleteAssessmentProfile(ctx context.Context, req types.DeleteAssessmentProfileRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, DeleteAssessmentProfileEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "DeleteAssessmentProfile",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -133,6 +137,7 @@ func (c *Client) DeleteAssessmentProfile(ctx context.Context, req types.DeleteAs
 func (c *Client) ListAssessmentProfiles(ctx context.Context, req types.ListAssessmentProfilesRequest) (*types.ListAssessmentProfilesResponse, error) {
 	var resp types.ListAssessmentProfilesResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListAssessmentProfilesEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "ListAssessmentProfiles",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
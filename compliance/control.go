@@ -18,6 +18,7 @@ import (
 func (c *Client) CreateControl(ctx context.Context, req types.CreateControlRequest) (*types.CreateControlResponse, error) {
 	var resp types.CreateControlResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateControlEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateControl",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -32,6 +33,7 @@ func (c *Client) CreateControl(ctx context.Context, req types.CreateControlReque
 func (c *Client) GetControl(ctx context.Context, req types.GetControlRequest) (*types.Control, error) {
 	var resp types.GetControlResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetControlEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "GetControl",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -48,6 +50,7 @@ func (c *Client) GetControl(ctx context.Context, req types.GetControlRequest) (*
 func (c *Client) UpdateControl(ctx context.Context, req types.UpdateControlRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, UpdateControlEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "UpdateControl",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -58,6 +61,7 @@ func (c *Client) UpdateControl(ctx context.Context, req types.UpdateControlReque
 func (c *Client) DeleteControl(ctx context.Context, req types.DeleteControlRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, DeleteControlEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "DeleteControl",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -68,6 +72,7 @@ func (c *Client) DeleteControl(ctx context.Context, req types.DeleteControlReque
 func (c *Client) ListControls(ctx context.Context, req types.ListControlsRequest) (*types.ListControlsResponse, error) {
 	var resp types.ListControlsResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListControlsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "ListControls",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -17,6 +17,7 @@ import (
 func (c *Client) CreateStandard(ctx context.Context, req types.CreateStandardRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateStandardEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateStandard",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -27,6 +28,7 @@ func (c *Client) CreateStandard(ctx context.Context, req types.CreateStandardReq
 func (c *Client) GetStandard(ctx context.Context, req types.GetStandardRequest) (*types.Standard, error) {
 	var resp types.GetStandardResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetStandardEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "GetStandard",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -82,6 +84,7 @@ func (c *Client) UpdateStandard(ctx context.Context, req types.UpdateStandardReq
 
 	var resp commontypes.SuccessResponse
 	_, err = c.internalClient.Do(ctx, http.MethodPost, UpdateStandardEndpoint, nil, nil, mergedReq, &resp, &client.DoOptions{
+		Operation:           "UpdateStandard",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -92,6 +95,7 @@ func (c *Client) UpdateStandard(ctx context.Context, req types.UpdateStandardReq
 func (c *Client) DeleteStandard(ctx context.Context, req types.DeleteStandardRequest) (bool, error) {
 	var resp commontypes.SuccessResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, DeleteStandardEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "DeleteStandard",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -102,6 +106,7 @@ func (c *Client) DeleteStandard(ctx context.Context, req types.DeleteStandardReq
 func (c *Client) ListStandards(ctx context.Context, req types.ListStandardsRequest) (*types.ListStandardsResponse, error) {
 	var resp types.ListStandardsResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListStandardsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "ListStandards",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
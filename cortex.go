@@ -0,0 +1,252 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cortex provides a single client for every module of the Cortex
+// Cloud API.
+//
+// The client returned by NewClient builds one core client, with one
+// configuration, transport, logger and rate limiter, and shares it across
+// the module clients returned by its accessors:
+//
+//	client, err := cortex.NewClient(cortex.WithCortexAPIURL(url), ...)
+//	rules, err := client.CloudSec().Search(ctx, types.SearchRulesRequest{})
+//	roles, err := client.Platform().ListAllRoles(ctx)
+//
+// Derived clients with overridden options, e.g. a longer timeout for a
+// batch job, are built with With and keep sharing the connections.
+package cortex
+
+import (
+	"context"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/appsec"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cloudonboarding"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cloudsec"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/compliance"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cwp"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/platform"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/version"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/vulnerability"
+)
+
+// Option is a functional option for configuring the client.
+type Option = config.Option
+
+var (
+	// WithCortexAPIURL is an option to set the Cortex API URL.
+	WithCortexAPIURL = config.WithCortexAPIURL
+	// WithCortexAPIKey is an option to set the Cortex API key.
+	WithCortexAPIKey = config.WithCortexAPIKey
+	// WithCortexAPIKeyID is an option to set the Cortex API key ID.
+	WithCortexAPIKeyID = config.WithCortexAPIKeyID
+	// WithCortexAPIKeyType is an option to set the Cortex API key type.
+	WithCortexAPIKeyType = config.WithCortexAPIKeyType
+	// WithCredentialsProvider is an option to load the API key and key ID from a credentials provider on every request.
+	WithCredentialsProvider = config.WithCredentialsProvider
+	// WithAuthenticator is an option to sign requests with a custom authenticator, such as a bearer token.
+	WithAuthenticator = config.WithAuthenticator
+	// WithHeaders is an option to set extra HTTP headers.
+	WithHeaders = config.WithHeaders
+	// WithAgent is an option to set the user agent.
+	WithAgent = config.WithAgent
+	// WithSkipSSLVerify is an option to skip TLS certificate verification.
+	WithSkipSSLVerify = config.WithSkipSSLVerify
+	// WithCABundle is an option to trust additional PEM-encoded CA certificates.
+	WithCABundle = config.WithCABundle
+	// WithCABundleFile is an option to trust the CA certificates of a PEM file.
+	WithCABundleFile = config.WithCABundleFile
+	// WithClientCertificate is an option to set the client certificate for mutual TLS.
+	WithClientCertificate = config.WithClientCertificate
+	// WithClientCertificateFile is an option to load the client certificate for mutual TLS from PEM files.
+	WithClientCertificateFile = config.WithClientCertificateFile
+	// WithMinTLSVersion is an option to set the minimum TLS version.
+	WithMinTLSVersion = config.WithMinTLSVersion
+	// WithProxy is an option to set the HTTP proxy URL.
+	WithProxy = config.WithProxy
+	// WithProxyAuth is an option to set the proxy credentials.
+	WithProxyAuth = config.WithProxyAuth
+	// WithPinnedPublicKeys is an option to pin the public keys of the tenant certificate chain.
+	WithPinnedPublicKeys = config.WithPinnedPublicKeys
+	// WithTransport is an option to set the HTTP transport.
+	WithTransport = config.WithTransport
+	// WithTimeout is an option to set the HTTP timeout.
+	WithTimeout = config.WithTimeout
+	// WithMaxRetries is an option to set the maximum number of retries.
+	WithMaxRetries = config.WithMaxRetries
+	// WithRetryMaxDelay is an option to set the maximum retry delay.
+	WithRetryMaxDelay = config.WithRetryMaxDelay
+	// WithMaxResponseSize is an option to set the maximum response size in bytes.
+	WithMaxResponseSize = config.WithMaxResponseSize
+	// WithCompression is an option to gzip request bodies and negotiate gzip-encoded responses.
+	WithCompression = config.WithCompression
+	// WithUncompressedEndpoints is an option to exclude endpoints from request body compression.
+	WithUncompressedEndpoints = config.WithUncompressedEndpoints
+	// WithCrashStackDir is an option to set the crash stack directory.
+	WithCrashStackDir = config.WithCrashStackDir
+	// WithSkipCrashBundle is an option to skip writing the diagnostic bundle of a recovered panic.
+	WithSkipCrashBundle = config.WithSkipCrashBundle
+	// WithLogLevel is an option to set the log level.
+	WithLogLevel = config.WithLogLevel
+	// WithLogger is an option to set the logger.
+	WithLogger = config.WithLogger
+	// WithSkipLoggingTransport is an option to skip logging transport.
+	WithSkipLoggingTransport = config.WithSkipLoggingTransport
+	// WithLogRedactedFields is an option to redact body fields in debug dumps.
+	WithLogRedactedFields = config.WithLogRedactedFields
+	// WithLogDumpMaxSize is an option to set the maximum body size in debug dumps.
+	WithLogDumpMaxSize = config.WithLogDumpMaxSize
+	// WithLogDumpSampling is an option to sample the debug dumps of repeated exchanges.
+	WithLogDumpSampling = config.WithLogDumpSampling
+	// WithProfile is an option to select the profile of the configuration file.
+	WithProfile = config.WithProfile
+	// WithConfigFile is an option to select the configuration file.
+	WithConfigFile = config.WithConfigFile
+	// WithRetryPolicy is an option to set the retry policy.
+	WithRetryPolicy = config.WithRetryPolicy
+	// WithRetryBudget is an option to set the retry budget.
+	WithRetryBudget = config.WithRetryBudget
+	// WithRateLimiter is an option to set a client-side rate limiter, which may be shared across clients.
+	WithRateLimiter = config.WithRateLimiter
+	// WithCircuitBreaker is an option to set a circuit breaker, which may be shared across clients.
+	WithCircuitBreaker = config.WithCircuitBreaker
+	// WithInterceptors is an option to add interceptors called for every request attempt.
+	WithInterceptors = config.WithInterceptors
+	// WithTracer is an option to trace every call with a span per method and a child span per attempt.
+	WithTracer = config.WithTracer
+	// WithMetrics is an option to record request counts, latencies, retries and in-flight requests.
+	WithMetrics = config.WithMetrics
+)
+
+var (
+	// WithIdempotencyKey returns a context that attaches an idempotency key
+	// to calls made with it, allowing mutating requests to be retried safely.
+	// If the key is empty, a unique key is generated for each call.
+	WithIdempotencyKey = client.WithIdempotencyKey
+	// WithCallOptions returns a context that applies the given call options
+	// to calls made with it.
+	WithCallOptions = client.WithCallOptions
+	// WithCallHeader is a call option to set an extra HTTP header.
+	WithCallHeader = client.WithCallHeader
+	// WithCallTimeout is a call option to bound the whole call, including retries.
+	WithCallTimeout = client.WithCallTimeout
+	// WithAttemptTimeout is a call option to bound each attempt of the call.
+	WithAttemptTimeout = client.WithAttemptTimeout
+	// WithCallMaxRetries is a call option to override the maximum number of retries.
+	WithCallMaxRetries = client.WithCallMaxRetries
+	// WithCallRetryPolicy is a call option to override the retry policy.
+	WithCallRetryPolicy = client.WithCallRetryPolicy
+	// WithCallRequestID is a call option to set the request ID sent with the call.
+	WithCallRequestID = client.WithCallRequestID
+	// WithCallResponseMeta is a call option to capture the response metadata of the call.
+	WithCallResponseMeta = client.WithCallResponseMeta
+)
+
+// CallOption overrides the client configuration for a single call. Attach
+// call options to a context with WithCallOptions.
+type CallOption = client.CallOption
+
+// ResponseMeta describes how a call went on the wire: status, headers,
+// request IDs, attempts and timing. Capture it with WithCallResponseMeta.
+type ResponseMeta = client.ResponseMeta
+
+// Client is the client for every module of the API. Its module clients
+// share one core client and are safe for concurrent use.
+type Client struct {
+	internalClient *client.Client
+
+	platform        *platform.Client
+	cloudSec        *cloudsec.Client
+	appSec          *appsec.Client
+	cwp             *cwp.Client
+	vulnerability   *vulnerability.Client
+	compliance      *compliance.Client
+	cloudOnboarding *cloudonboarding.Client
+}
+
+// NewClient returns a new client for every module of the API. The
+// configuration is read once, from the environment and the given options,
+// and shared by all the module clients.
+func NewClient(opts ...Option) (*Client, error) {
+	// The module's user agent applies unless a setting of any layer sets one
+	opts = append([]config.Option{config.WithDefaultAgent(version.UserAgent(ModuleName))}, opts...)
+
+	internalClient, err := client.NewClientFromConfig(config.NewConfig(opts...))
+	if err != nil {
+		return nil, err
+	}
+	return newClient(internalClient), nil
+}
+
+// NewClientFromFile creates a new client from a configuration file, in JSON,
+// YAML or TOML. Environment variables take precedence over the file.
+func NewClientFromFile(filepath string) (*Client, error) {
+	c, err := NewClient(WithConfigFile(filepath))
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newClient returns a client whose module clients share internalClient.
+func newClient(internalClient *client.Client) *Client {
+	return &Client{
+		internalClient:  internalClient,
+		platform:        platform.NewClientFromCore(internalClient),
+		cloudSec:        cloudsec.NewClientFromCore(internalClient),
+		appSec:          appsec.NewClientFromCore(internalClient),
+		cwp:             cwp.NewClientFromCore(internalClient),
+		vulnerability:   vulnerability.NewClientFromCore(internalClient),
+		compliance:      compliance.NewClientFromCore(internalClient),
+		cloudOnboarding: cloudonboarding.NewClientFromCore(internalClient),
+	}
+}
+
+// With returns a client whose configuration is that of c with the given
+// options applied. The environment is not read again. Unless the options
+// set another transport or TLS and proxy settings, the derived client shares
+// the connections of c. It also shares its logger, rate limiter, circuit
+// breaker and retry budget unless they are overridden.
+func (c *Client) With(opts ...Option) (*Client, error) {
+	internalClient, err := c.internalClient.Derive(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(internalClient), nil
+}
+
+// ValidateAPIKey validates the configured API Key against the target
+// Cortex tenant.
+func (c *Client) ValidateAPIKey(ctx context.Context) (bool, error) {
+	return c.internalClient.ValidateAPIKey(ctx)
+}
+
+// Profile returns the name of the configuration file profile the settings
+// were loaded from, or an empty string if no profile was loaded.
+func (c *Client) Profile() string { return c.internalClient.Profile() }
+
+// ConfigSources returns where each setting came from ("default", "profile",
+// "env" or "option"), keyed by configuration file key such as "api_url".
+func (c *Client) ConfigSources() map[string]string { return c.internalClient.ConfigSources() }
+
+// Platform returns the client for the platform module.
+func (c *Client) Platform() *platform.Client { return c.platform }
+
+// CloudSec returns the client for the CloudSec module.
+func (c *Client) CloudSec() *cloudsec.Client { return c.cloudSec }
+
+// AppSec returns the client for the AppSec module.
+func (c *Client) AppSec() *appsec.Client { return c.appSec }
+
+// CWP returns the client for the CWP module.
+func (c *Client) CWP() *cwp.Client { return c.cwp }
+
+// Vulnerability returns the client for the vulnerability module.
+func (c *Client) Vulnerability() *vulnerability.Client { return c.vulnerability }
+
+// Compliance returns the client for the compliance module.
+func (c *Client) Compliance() *compliance.Client { return c.compliance }
+
+// CloudOnboarding returns the client for the cloud onboarding module.
+func (c *Client) CloudOnboarding() *cloudonboarding.Client { return c.cloudOnboarding }
@@ -0,0 +1,103 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortex
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cortextest"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/ratelimit"
+	cloudsecTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/cloudsec"
+	complianceTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/compliance"
+)
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	next     http.RoundTripper
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return t.next.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, opts ...Option) (*Client, *countingTransport) {
+	t.Helper()
+	srv := cortextest.NewServer()
+	t.Cleanup(srv.Close)
+	transport := &countingTransport{next: srv.Client().Transport}
+	opts = append(append(srv.ClientOptions(), WithTransport(transport)), opts...)
+	client, err := NewClient(opts...)
+	require.NoError(t, err)
+	return client, transport
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should share one core client across modules", func(t *testing.T) {
+		ctx := context.Background()
+		client, transport := newTestClient(t)
+
+		_, err := client.CloudSec().Search(ctx, cloudsecTypes.SearchRulesRequest{})
+		require.NoError(t, err)
+		_, err = client.Platform().ListAllRoles(ctx)
+		require.NoError(t, err)
+		_, err = client.Compliance().ListStandards(ctx, complianceTypes.ListStandardsRequest{})
+		require.NoError(t, err)
+
+		assert.EqualValues(t, 3, transport.requests.Load())
+		for _, module := range []interface{ APIURL() string }{
+			client.Platform(), client.CloudSec(), client.AppSec(), client.CWP(),
+			client.Vulnerability(), client.Compliance(), client.CloudOnboarding(),
+		} {
+			assert.Equal(t, client.CloudSec().APIURL(), module.APIURL())
+		}
+	})
+
+	t.Run("should share the rate limiter across modules", func(t *testing.T) {
+		ctx := context.Background()
+		limiter := ratelimit.New(ratelimit.WithRate(1, 1))
+		client, _ := newTestClient(t, WithRateLimiter(limiter))
+
+		_, err := client.CloudSec().Search(ctx, cloudsecTypes.SearchRulesRequest{})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = client.Platform().ListAllRoles(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		client, err := NewClient(WithCortexAPIURL("https://api.example.com"))
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+}
+
+func TestClient_With(t *testing.T) {
+	ctx := context.Background()
+	client, transport := newTestClient(t)
+
+	derived, err := client.With(WithTimeout(120), WithMaxRetries(5))
+	require.NoError(t, err)
+
+	assert.Equal(t, 120*time.Second, derived.CloudSec().Timeout())
+	assert.Equal(t, 5, derived.Platform().MaxRetries())
+	assert.Equal(t, 0, client.Platform().MaxRetries())
+
+	_, err = derived.CloudSec().Search(ctx, cloudsecTypes.SearchRulesRequest{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, transport.requests.Load())
+
+	_, err = client.With(WithCortexAPIKey(""))
+	assert.Error(t, err)
+}
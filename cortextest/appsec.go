@@ -0,0 +1,433 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/enums"
+	appsecTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/appsec"
+)
+
+// appSecState holds the resources of the AppSec module.
+type appSecState struct {
+	appSecRules    *table[appsecTypes.Rule]
+	appSecPolicies *table[appsecTypes.Policy]
+}
+
+func newAppSecState() appSecState {
+	return appSecState{
+		appSecRules:    newTable[appsecTypes.Rule](),
+		appSecPolicies: newTable[appsecTypes.Policy](),
+	}
+}
+
+func (s *Server) registerAppSec() {
+	s.handle("POST public_api/appsec/v1/rules", s.createAppSecRule)
+	s.handle("GET public_api/appsec/v1/rules", s.listAppSecRules)
+	s.handle("GET public_api/appsec/v1/rules/{id}", s.getAppSecRule)
+	s.handle("PATCH public_api/appsec/v1/rules/{id}", s.updateAppSecRule)
+	s.handle("DELETE public_api/appsec/v1/rules/{id}", s.deleteAppSecRule)
+	s.handle("GET public_api/appsec/v1/rules/rule-labels", s.listAppSecRuleLabels)
+	s.handle("POST public_api/appsec/v1/rules/validate", s.validateAppSecRules)
+	s.handle("GET public_api/appsec/v1/rules/rule_actions", s.listAppSecRuleActions)
+
+	s.handle("POST public_api/appsec/v1/policies", s.createAppSecPolicy)
+	s.handle("GET public_api/appsec/v1/policies", s.listAppSecPolicies)
+	s.handle("GET public_api/appsec/v1/policies/{id}", s.getAppSecPolicy)
+	s.handle("PUT public_api/appsec/v1/policies/{id}", s.updateAppSecPolicy)
+	s.handle("DELETE public_api/appsec/v1/policies/{id}", s.deleteAppSecPolicy)
+}
+
+// timestamp returns the current time in the RFC 3339 format of the AppSec
+// endpoints.
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (s *Server) createAppSecRule(w http.ResponseWriter, r *http.Request) {
+	var req appsecTypes.CreateOrCloneRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" || req.Scanner == "" || req.Severity == "" {
+		badRequest(w, r, "name, scanner and severity are required")
+		return
+	}
+	for _, rule := range s.appSecRules.all() {
+		if rule.Name == req.Name {
+			conflict(w, r, "rule with name "+req.Name+" already exists")
+			return
+		}
+	}
+	ts := timestamp()
+	labels := nonNil(req.Labels)
+	rule := appsecTypes.Rule{
+		Category:    req.Category,
+		CreatedAt:   appsecTypes.CreatedUpdatedAt{Value: ts},
+		Description: req.Description,
+		Frameworks:  nonNil(req.Frameworks),
+		Id:          s.newUUID(),
+		IsCustom:    true,
+		IsEnabled:   true,
+		Labels:      &labels,
+		Name:        req.Name,
+		Owner:       Actor,
+		Scanner:     req.Scanner,
+		Severity:    req.Severity,
+		Source:      "CUSTOM",
+		SubCategory: req.SubCategory,
+		UpdatedAt:   appsecTypes.CreatedUpdatedAt{Value: ts},
+	}
+	s.appSecRules.put(rule.Id, rule)
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) getAppSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.appSecRules.get(id)
+	if !ok {
+		notFound(w, r, "rule", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *rule)
+}
+
+// queryFlag reports whether the boolean query parameter is set to true. The
+// SDK always sends the enabled and isCustom parameters, so false means that
+// the list is not filtered on them.
+func queryFlag(query url.Values, key string) bool {
+	v, _ := strconv.ParseBool(query.Get(key))
+	return v
+}
+
+// matchQueryValues reports whether value is one of the values of the query
+// parameter, or whether the parameter is absent.
+func matchQueryValues(query url.Values, key string, value ...string) bool {
+	want := query[key]
+	if len(want) == 0 {
+		return true
+	}
+	for _, v := range value {
+		if slices.ContainsFunc(want, func(w string) bool { return strings.EqualFold(w, v) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listAppSecRules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var rules []appsecTypes.Rule
+	for _, rule := range s.appSecRules.all() {
+		var labels, frameworks []string
+		if rule.Labels != nil {
+			labels = *rule.Labels
+		}
+		for _, framework := range rule.Frameworks {
+			frameworks = append(frameworks, framework.Name)
+		}
+		if (queryFlag(query, "enabled") && !rule.IsEnabled) ||
+			(queryFlag(query, "isCustom") && !rule.IsCustom) ||
+			!matchQueryValues(query, "labels", labels...) ||
+			!matchQueryValues(query, "frameworks", frameworks...) ||
+			!matchQueryValues(query, "scanners", rule.Scanner) ||
+			!matchQueryValues(query, "severities", rule.Severity) ||
+			!matchQueryValues(query, "categories", rule.Category) ||
+			!matchQueryValues(query, "cloudProviders", rule.CloudProvider) ||
+			!matchQueryValues(query, "subCategories", rule.SubCategory) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	q := listQuery{from: offset}
+	if limit > 0 {
+		q.to = offset + limit
+	}
+	if sortBy := query.Get("sortBy"); sortBy != "" {
+		q.sort = []sortKey{{field: sortBy, desc: isDescending(query.Get("sortOrder"))}}
+	}
+	page, count, err := run(rules, q)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	resp := appsecTypes.ListResponse{Offset: float64(offset), Rules: page}
+	if q.to > 0 && q.to < count {
+		resp.NextOffset = &q.to
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) updateAppSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.appSecRules.get(id)
+	if !ok {
+		notFound(w, r, "rule", id)
+		return
+	}
+	if !rule.IsCustom {
+		badRequest(w, r, "system rules cannot be modified")
+		return
+	}
+	var req appsecTypes.UpdateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Severity != "" {
+		rule.Severity = req.Severity
+	}
+	if req.Scanner != "" {
+		rule.Scanner = req.Scanner
+	}
+	if req.Category != "" {
+		rule.Category = req.Category
+	}
+	if req.SubCategory != "" {
+		rule.SubCategory = req.SubCategory
+	}
+	if req.Description != "" {
+		rule.Description = req.Description
+	}
+	if req.Frameworks != nil {
+		rule.Frameworks = req.Frameworks
+	}
+	if req.Labels != nil {
+		labels := req.Labels
+		rule.Labels = &labels
+	}
+	rule.UpdatedAt = appsecTypes.CreatedUpdatedAt{Value: timestamp()}
+	writeJSON(w, http.StatusOK, appsecTypes.UpdateResponse{Rule: *rule})
+}
+
+func (s *Server) deleteAppSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.appSecRules.remove(id) {
+		notFound(w, r, "rule", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAppSecRuleLabels(w http.ResponseWriter, _ *http.Request) {
+	labels := []string{}
+	for _, rule := range s.appSecRules.all() {
+		if rule.Labels == nil {
+			continue
+		}
+		for _, label := range *rule.Labels {
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	sort.Strings(labels)
+	writeJSON(w, http.StatusOK, appsecTypes.GetLabelsResponse{Labels: labels})
+}
+
+func (s *Server) validateAppSecRules(w http.ResponseWriter, r *http.Request) {
+	var req []appsecTypes.ValidateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	valid := true
+	resp := appsecTypes.ValidateResponse{FrameworksErrors: []appsecTypes.ValidateResponseFrameworkError{}}
+	for _, framework := range req {
+		if strings.TrimSpace(framework.Definition) != "" {
+			continue
+		}
+		valid = false
+		resp.FrameworksErrors = append(resp.FrameworksErrors, appsecTypes.ValidateResponseFrameworkError{
+			Framework: enums.FrameworkName(framework.Framework),
+			Errors:    []string{"definition is required"},
+		})
+	}
+	resp.IsValid = &valid
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) listAppSecRuleActions(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []string{})
+}
+
+func (s *Server) createAppSecPolicy(w http.ResponseWriter, r *http.Request) {
+	var req appsecTypes.CreatePolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		badRequest(w, r, "name is required")
+		return
+	}
+	for _, policy := range s.appSecPolicies.all() {
+		if policy.Name == req.Name {
+			conflict(w, r, "policy with name "+req.Name+" already exists")
+			return
+		}
+	}
+	ts := timestamp()
+	policy := appsecTypes.Policy{
+		ID:                    s.newUUID(),
+		Name:                  req.Name,
+		Description:           req.Description,
+		Status:                "enabled",
+		IsCustom:              true,
+		Conditions:            req.Conditions,
+		Scope:                 req.Scope,
+		AssetGroupIds:         req.AssetGroupIds,
+		Triggers:              req.Triggers,
+		Actions:               policyActions(req.Triggers),
+		RelatedDetectionRules: []string{},
+		CreatedBy:             Actor,
+		DateCreated:           ts,
+		ModifiedBy:            Actor,
+		DateModified:          ts,
+		Version:               1,
+	}
+	s.appSecPolicies.put(policy.ID, policy)
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// policyActions returns the actions taken by any of the triggers.
+func policyActions(triggers appsecTypes.PolicyTriggers) appsecTypes.PolicyActions {
+	var actions appsecTypes.PolicyActions
+	for _, trigger := range []appsecTypes.PolicyTriggerConfig{
+		triggers.Periodic, triggers.PR, triggers.CICD, triggers.CIImage, triggers.ImageRegistry,
+	} {
+		if !trigger.IsEnabled {
+			continue
+		}
+		actions.ReportIssue = actions.ReportIssue || trigger.Actions.ReportIssue
+		actions.BlockPR = actions.BlockPR || trigger.Actions.BlockPR
+		actions.BlockCICD = actions.BlockCICD || trigger.Actions.BlockCICD
+		actions.ReportPRComment = actions.ReportPRComment || trigger.Actions.ReportPRComment
+		actions.ReportCICD = actions.ReportCICD || trigger.Actions.ReportCICD
+		actions.IngestedData = actions.IngestedData || trigger.Actions.IngestedData
+	}
+	return actions
+}
+
+func (s *Server) getAppSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.appSecPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) listAppSecPolicies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	policies := []appsecTypes.Policy{}
+	for _, policy := range s.appSecPolicies.all() {
+		if (query.Get("status") != "" && !strings.EqualFold(query.Get("status"), policy.Status)) ||
+			(queryFlag(query, "isCustom") && !policy.IsCustom) ||
+			(queryFlag(query, "developerSuppressionAffects") && !policy.DeveloperSuppressionAffects) ||
+			!matchQueryValues(query, "findingTypes", enabledKeys(policy.FindingTypes)...) ||
+			!matchQueryValues(query, "actions", enabledKeys(policy.Actions)...) ||
+			!matchQueryValues(query, "triggers", enabledTriggers(policy.Triggers)...) {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	writeJSON(w, http.StatusOK, policies)
+}
+
+// enabledKeys returns the keys of the true fields of a struct of flags.
+func enabledKeys(flags any) []string {
+	var keys []string
+	for key, v := range document(flags) {
+		if v == true {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// enabledTriggers returns the keys of the enabled triggers.
+func enabledTriggers(triggers appsecTypes.PolicyTriggers) []string {
+	var keys []string
+	for key, v := range document(triggers) {
+		if trigger, ok := v.(map[string]any); ok && trigger["isEnabled"] == true {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (s *Server) updateAppSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.appSecPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	var req appsecTypes.UpdatePolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name != nil {
+		policy.Name = *req.Name
+	}
+	if req.Description != nil {
+		policy.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		policy.Status = "disabled"
+		if *req.Enabled {
+			policy.Status = "enabled"
+		}
+	}
+	if req.Triggers != nil {
+		policy.Triggers = *req.Triggers
+		policy.Actions = policyActions(*req.Triggers)
+	}
+	if req.Conditions != nil {
+		policy.Conditions = *req.Conditions
+	}
+	if req.RelatedDetectionRules != nil {
+		policy.RelatedDetectionRules = req.RelatedDetectionRules
+	}
+	if req.Scope != nil {
+		policy.Scope = req.Scope
+	}
+	if req.Actions != nil {
+		policy.Actions = *req.Actions
+	}
+	if req.DeveloperSuppressionAffects != nil {
+		policy.DeveloperSuppressionAffects = *req.DeveloperSuppressionAffects
+	}
+	if req.OverrideIssueSeverity != nil {
+		policy.OverrideIssueSeverity = req.OverrideIssueSeverity
+	}
+	if req.AssetGroupIds != nil {
+		policy.AssetGroupIds = req.AssetGroupIds
+	}
+	policy.ModifiedBy = Actor
+	policy.DateModified = timestamp()
+	policy.Version++
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) deleteAppSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.appSecPolicies.remove(id) {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, appsecTypes.DeletePolicyResponse{Message: "policy " + id + " deleted successfully"})
+}
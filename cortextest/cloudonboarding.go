@@ -0,0 +1,390 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	cloudOnboardingTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/cloudonboarding"
+)
+
+// cloudOnboardingState holds the resources of the cloud onboarding module.
+type cloudOnboardingState struct {
+	instances     *table[cloudOnboardingTypes.ListIntegrationInstancesResponse]
+	cloudAccounts map[string]*table[cloudOnboardingTypes.CloudAccount] // by instance ID
+	outposts      *table[cloudOnboardingTypes.Outpost]
+}
+
+func newCloudOnboardingState() cloudOnboardingState {
+	return cloudOnboardingState{
+		instances:     newTable[cloudOnboardingTypes.ListIntegrationInstancesResponse](),
+		cloudAccounts: make(map[string]*table[cloudOnboardingTypes.CloudAccount]),
+		outposts:      newTable[cloudOnboardingTypes.Outpost](),
+	}
+}
+
+// AddCloudAccounts adds cloud accounts to the integration instance with the
+// given ID, as returned by the cloud account endpoints. Accounts are
+// identified by their account ID.
+func (s *Server) AddCloudAccounts(instanceID string, accounts ...cloudOnboardingTypes.CloudAccount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.cloudAccounts[instanceID]
+	if !ok {
+		t = newTable[cloudOnboardingTypes.CloudAccount]()
+		s.cloudAccounts[instanceID] = t
+	}
+	for _, account := range accounts {
+		t.put(account.AccountId, account)
+	}
+	if instance, ok := s.instances.get(instanceID); ok {
+		instance.Accounts = t.len()
+	}
+}
+
+func (s *Server) registerCloudOnboarding() {
+	const prefix = "POST public_api/v1/cloud_onboarding/"
+	s.handle(prefix+"get_accounts", s.listCloudAccounts)
+	s.handle(prefix+"enable_disable_account", s.toggleCloudAccounts)
+
+	s.handle(prefix+"create_instance_template", s.createIntegrationTemplate)
+	s.handle(prefix+"get_instance_details", s.getIntegrationInstance)
+	s.handle(prefix+"get_instances", s.listIntegrationInstances)
+	s.handle(prefix+"edit_instance", s.editIntegrationInstance)
+	s.handle(prefix+"enable_disable_instance", s.toggleIntegrationInstances)
+	s.handle(prefix+"delete_instance", s.deleteIntegrationInstances)
+	s.handle(prefix+"get_azure_approved_tenants", s.listAzureApprovedTenants)
+
+	s.handle(prefix+"create_outpost_template", s.createOutpostTemplate)
+	s.handle(prefix+"edit_outpost", s.editOutpost)
+	s.handle(prefix+"get_outposts", s.listOutposts)
+}
+
+func (s *Server) listCloudAccounts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		InstanceID string     `json:"instance_id"`
+		FilterData filterData `json:"filter_data"`
+	}
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	var rows []cloudOnboardingTypes.CloudAccount
+	if t, ok := s.cloudAccounts[req.InstanceID]; ok {
+		rows = t.all()
+	}
+	accounts, count, err := run(rows, req.FilterData.query(nil))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cloudOnboardingTypes.ListAccountsByInstanceResponseReply{
+		Data:        accounts,
+		FilterCount: count,
+		TotalCount:  len(rows),
+	}, "reply")
+}
+
+func (s *Server) toggleCloudAccounts(w http.ResponseWriter, r *http.Request) {
+	var req cloudOnboardingTypes.EnableDisableAccountsInInstancesRequestData
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	t, ok := s.cloudAccounts[req.InstanceId]
+	if !ok {
+		notFound(w, r, "integration instance", req.InstanceId)
+		return
+	}
+	for _, id := range req.Ids {
+		if _, ok := t.get(id); !ok {
+			notFound(w, r, "cloud account", id)
+			return
+		}
+	}
+	for _, id := range req.Ids {
+		account, _ := t.get(id)
+		account.Status = enabledStatus(req.Enable)
+	}
+	writeJSON(w, http.StatusOK, cloudOnboardingTypes.EnableDisableAccountsInInstancesResponseReply{}, "reply")
+}
+
+// enabledStatus returns the status of an enabled or disabled resource.
+func enabledStatus(enable bool) string {
+	if enable {
+		return "ENABLED"
+	}
+	return "DISABLED"
+}
+
+// integrationTemplateRequest is the request of the integration template
+// endpoints.
+type integrationTemplateRequest struct {
+	ID                      string                                       `json:"id"`
+	AccountDetails          *cloudOnboardingTypes.AccountDetails         `json:"account_details"`
+	AdditionalCapabilities  cloudOnboardingTypes.AdditionalCapabilities  `json:"additional_capabilities"`
+	CloudProvider           string                                       `json:"cloud_provider"`
+	CollectionConfiguration cloudOnboardingTypes.CollectionConfiguration `json:"collection_configuration"`
+	CustomResourcesTags     []cloudOnboardingTypes.Tag                   `json:"custom_resources_tags"`
+	InstanceName            *string                                      `json:"instance_name"`
+	OutpostID               *string                                      `json:"scan_env_id"`
+	ScanMode                string                                       `json:"scan_mode"`
+	Scope                   string                                       `json:"scope"`
+}
+
+// createIntegrationTemplate creates an integration instance in the PENDING
+// status, identified by the tracking GUID of its template, as if the
+// template had been deployed.
+func (s *Server) createIntegrationTemplate(w http.ResponseWriter, r *http.Request) {
+	var req integrationTemplateRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.CloudProvider == "" || req.Scope == "" || req.ScanMode == "" {
+		badRequest(w, r, "cloud_provider, scope and scan_mode are required")
+		return
+	}
+	guid := fmt.Sprintf("%032x", s.nextSeq())
+	instance := cloudOnboardingTypes.ListIntegrationInstancesResponse{
+		CloudProvider:      strings.ToUpper(req.CloudProvider),
+		Scope:              req.Scope,
+		ScanMode:           req.ScanMode,
+		ProvisioningMethod: "TEMPLATE",
+		InstanceID:         guid,
+		Status:             "PENDING",
+		CreationTime:       now(),
+	}
+	applyIntegrationTemplate(&instance, req)
+	if req.AccountDetails != nil {
+		instance.AccountName = req.AccountDetails.OrganizationID
+	}
+	s.instances.put(guid, instance)
+	writeJSON(w, http.StatusOK, templateResponse(instance.CloudProvider, guid), "reply")
+}
+
+// applyIntegrationTemplate sets the configurable fields of instance from req.
+func applyIntegrationTemplate(instance *cloudOnboardingTypes.ListIntegrationInstancesResponse, req integrationTemplateRequest) {
+	if req.InstanceName != nil {
+		instance.InstanceName = *req.InstanceName
+	}
+	if req.OutpostID != nil {
+		instance.OutpostID = *req.OutpostID
+	}
+	instance.CustomResourcesTags = rawJSON(nonNil(req.CustomResourcesTags))
+	instance.CollectionConfiguration = rawJSON(req.CollectionConfiguration)
+	instance.AdditionalCapabilities = rawJSON(req.AdditionalCapabilities)
+}
+
+// templateResponse returns the deployment links of the template with the
+// given tracking GUID, in the formats parsed by the SDK.
+func templateResponse(cloudProvider, guid string) cloudOnboardingTypes.CreateTemplateOrEditIntegrationInstanceResponse {
+	base := tenantURL + "/templates/"
+	tf := base + "tf-" + guid + "-template.zip"
+	resp := cloudOnboardingTypes.CreateTemplateOrEditIntegrationInstanceResponse{
+		Automated: cloudOnboardingTypes.Automated{TrackingGUID: &guid},
+		Manual:    cloudOnboardingTypes.Manual{TF: &tf},
+	}
+	switch cloudProvider {
+	case "AWS":
+		cf := base + "cf-" + guid + "-template.json"
+		link := "https://console.aws.amazon.com/cloudformation/home#/stacks/quickcreate?templateURL=" +
+			url.QueryEscape(cf) + "&stackName=cortex-" + guid
+		resp.Automated.Link = &link
+		resp.Manual.CF = &cf
+	case "AZURE":
+		arm := base + "arm-" + guid + "-template.json"
+		link := "https://portal.azure.com/#create/Microsoft.Template/uri/" + url.QueryEscape(arm)
+		resp.Automated.Link = &link
+		resp.Manual.ARM = &arm
+	default:
+		link := base + "setup-" + guid + ".sh"
+		resp.Automated.Link = &link
+	}
+	return resp
+}
+
+func (s *Server) getIntegrationInstance(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	instance, ok := s.instances.get(req.ID)
+	if !ok {
+		notFound(w, r, "integration instance", req.ID)
+		return
+	}
+	var tags []cloudOnboardingTypes.Tag
+	_ = json.Unmarshal([]byte(instance.CustomResourcesTags), &tags)
+	writeJSON(w, http.StatusOK, cloudOnboardingTypes.GetIntegrationInstanceResponse{
+		ID:                      instance.InstanceID,
+		Collector:               "cortextest",
+		InstanceName:            instance.InstanceName,
+		Scope:                   instance.Scope,
+		Tags:                    tags,
+		Scan:                    cloudOnboardingTypes.Scan{OutpostID: instance.OutpostID, ScanMethod: instance.ScanMode},
+		Status:                  instance.Status,
+		CloudProvider:           instance.CloudProvider,
+		SecurityCapabilities:    []cloudOnboardingTypes.SecurityCapability{},
+		CollectionConfiguration: instance.CollectionConfiguration,
+		AdditionalCapabilities:  instance.AdditionalCapabilities,
+	}, "reply")
+}
+
+func (s *Server) listIntegrationInstances(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilterData filterData `json:"filter_data"`
+	}
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	instances, count, err := run(s.instances.all(), req.FilterData.query(nil))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"DATA":         instances,
+		"FILTER_COUNT": count,
+		"TOTAL_COUNT":  s.instances.len(),
+	}, "reply")
+}
+
+func (s *Server) editIntegrationInstance(w http.ResponseWriter, r *http.Request) {
+	var req integrationTemplateRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	instance, ok := s.instances.get(req.ID)
+	if !ok {
+		notFound(w, r, "integration instance", req.ID)
+		return
+	}
+	if req.CloudProvider != "" && !strings.EqualFold(req.CloudProvider, instance.CloudProvider) {
+		badRequest(w, r, "cloud_provider cannot be changed")
+		return
+	}
+	applyIntegrationTemplate(instance, req)
+	instance.UpdateStatus = "PENDING"
+	writeJSON(w, http.StatusOK, templateResponse(instance.CloudProvider, instance.InstanceID), "reply")
+}
+
+// idsRequest is the request of the endpoints that act on several resources.
+type idsRequest struct {
+	IDs    []string `json:"ids"`
+	Enable bool     `json:"enable"`
+}
+
+// missingID returns the first ID that is not in t, if any.
+func missingID[T any](t *table[T], ids []string) (string, bool) {
+	i := slices.IndexFunc(ids, func(id string) bool {
+		_, ok := t.get(id)
+		return !ok
+	})
+	if i < 0 {
+		return "", false
+	}
+	return ids[i], true
+}
+
+func (s *Server) toggleIntegrationInstances(w http.ResponseWriter, r *http.Request) {
+	var req idsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if id, ok := missingID(s.instances, req.IDs); ok {
+		notFound(w, r, "integration instance", id)
+		return
+	}
+	for _, id := range req.IDs {
+		instance, _ := s.instances.get(id)
+		instance.Status = enabledStatus(req.Enable)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteIntegrationInstances(w http.ResponseWriter, r *http.Request) {
+	var req idsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if id, ok := missingID(s.instances, req.IDs); ok {
+		notFound(w, r, "integration instance", id)
+		return
+	}
+	for _, id := range req.IDs {
+		s.instances.remove(id)
+		delete(s.cloudAccounts, id)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) listAzureApprovedTenants(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []any{}, "reply")
+}
+
+// outpostRequest is the request of the outpost endpoints.
+type outpostRequest struct {
+	OutpostID           string                     `json:"outpost_id"`
+	CloudProvider       string                     `json:"cloud_provider"`
+	CustomResourcesTags []cloudOnboardingTypes.Tag `json:"custom_resources_tags"`
+}
+
+func (s *Server) createOutpostTemplate(w http.ResponseWriter, r *http.Request) {
+	var req outpostRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.CloudProvider == "" {
+		badRequest(w, r, "cloud_provider is required")
+		return
+	}
+	guid := fmt.Sprintf("%032x", s.nextSeq())
+	outpost := cloudOnboardingTypes.Outpost{
+		CloudProvider: strings.ToUpper(req.CloudProvider),
+		OutpostID:     guid,
+		CreatedAt:     now(),
+		Type:          "CUSTOMER",
+	}
+	s.outposts.put(guid, outpost)
+	writeJSON(w, http.StatusOK, templateResponse(outpost.CloudProvider, guid), "reply")
+}
+
+func (s *Server) editOutpost(w http.ResponseWriter, r *http.Request) {
+	var req outpostRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	outpost, ok := s.outposts.get(req.OutpostID)
+	if !ok {
+		notFound(w, r, "outpost", req.OutpostID)
+		return
+	}
+	if req.CloudProvider != "" && !strings.EqualFold(req.CloudProvider, outpost.CloudProvider) {
+		badRequest(w, r, "cloud_provider cannot be changed")
+		return
+	}
+	writeJSON(w, http.StatusOK, templateResponse(outpost.CloudProvider, outpost.OutpostID), "reply")
+}
+
+func (s *Server) listOutposts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilterData filterData `json:"filter_data"`
+	}
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	outposts, count, err := run(s.outposts.all(), req.FilterData.query(nil))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cloudOnboardingTypes.ListOutpostsResponse{
+		Data:        outposts,
+		FilterCount: count,
+		TotalCount:  s.outposts.len(),
+	}, "reply")
+}
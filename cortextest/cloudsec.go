@@ -0,0 +1,331 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"net/http"
+
+	cloudsecTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/cloudsec"
+)
+
+// cloudSecState holds the resources of the CloudSec module.
+type cloudSecState struct {
+	rules    *table[cloudsecTypes.RuleResponse]
+	policies *table[cloudsecTypes.PolicyResponse]
+}
+
+func newCloudSecState() cloudSecState {
+	return cloudSecState{
+		rules:    newTable[cloudsecTypes.RuleResponse](),
+		policies: newTable[cloudsecTypes.PolicyResponse](),
+	}
+}
+
+func (s *Server) registerCloudSec() {
+	s.handle("POST public_api/v1/rule", s.createCloudSecRule)
+	s.handle("POST public_api/v1/rule/search", s.searchCloudSecRules)
+	s.handle("GET public_api/v1/rule/{id}", s.getCloudSecRule)
+	s.handle("PATCH public_api/v1/rule/{id}", s.updateCloudSecRule)
+	s.handle("DELETE public_api/v1/rule/{id}", s.deleteCloudSecRule)
+
+	s.handle("POST public_api/v1/policy", s.createCloudSecPolicy)
+	s.handle("POST public_api/v1/policy/search", s.searchCloudSecPolicies)
+	s.handle("GET public_api/v1/policy/{id}", s.getCloudSecPolicy)
+	s.handle("PATCH public_api/v1/policy/{id}", s.updateCloudSecPolicy)
+	s.handle("DELETE public_api/v1/policy/{id}", s.deleteCloudSecPolicy)
+}
+
+// searchRequest is the request of the CloudSec search endpoints.
+type searchRequest struct {
+	Filter     *criteria  `json:"filter"`
+	SearchFrom int        `json:"search_from"`
+	SearchTo   int        `json:"search_to"`
+	Sort       []sortSpec `json:"sort"`
+}
+
+// query returns the list query of the request.
+func (req searchRequest) query() listQuery {
+	return listQuery{
+		match: func(doc map[string]any) (bool, error) { return req.Filter.match(doc, nil) },
+		sort:  sortKeys(req.Sort),
+		from:  req.SearchFrom,
+		to:    req.SearchTo,
+	}
+}
+
+func (s *Server) createCloudSecRule(w http.ResponseWriter, r *http.Request) {
+	var req cloudsecTypes.CreateRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" || req.Class == "" || req.Severity == "" || len(req.AssetTypes) == 0 {
+		badRequest(w, r, "name, rule_class, severity and asset_types are required")
+		return
+	}
+	for _, rule := range s.rules.all() {
+		if rule.Name == req.Name {
+			conflict(w, r, "rule with name "+req.Name+" already exists")
+			return
+		}
+	}
+	ts := now()
+	rule := cloudsecTypes.RuleResponse{
+		ID:             s.newUUID(),
+		Name:           req.Name,
+		Description:    req.Description,
+		Class:          req.Class,
+		Type:           req.Type,
+		Providers:      []string{},
+		AssetTypes:     req.AssetTypes,
+		Severity:       req.Severity,
+		Query:          &cloudsecTypes.QueryResponse{XQL: req.Query.XQL},
+		Labels:         nonNil(req.Labels),
+		Enabled:        req.Enabled == nil || *req.Enabled,
+		CreatedBy:      Actor,
+		CreatedOn:      ts,
+		LastModifiedBy: Actor,
+		LastModifiedOn: ts,
+	}
+	if rule.Type == "" {
+		rule.Type = "CUSTOM"
+	}
+	rule.Metadata = ruleMetadata(req.Metadata)
+	rule.ComplianceMetadata = complianceMetadata(req.ComplianceMetadata)
+	s.rules.put(rule.ID, rule)
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) getCloudSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.rules.get(id)
+	if !ok {
+		notFound(w, r, "rule", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *rule)
+}
+
+func (s *Server) searchCloudSecRules(w http.ResponseWriter, r *http.Request) {
+	var req searchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	var rows []cloudsecTypes.RuleData
+	convert(s.rules.all(), &rows)
+	rules, count, err := run(rows, req.query())
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cloudsecTypes.SearchRulesResponse{
+		Data: rules,
+		Metadata: cloudsecTypes.SearchMetadata{
+			FilterCount: int64(count),
+			TotalCount:  int64(s.rules.len()),
+		},
+	})
+}
+
+func (s *Server) updateCloudSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.rules.get(id)
+	if !ok {
+		notFound(w, r, "rule", id)
+		return
+	}
+	var req cloudsecTypes.UpdateRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Description != "" {
+		rule.Description = req.Description
+	}
+	if req.Class != "" {
+		rule.Class = req.Class
+	}
+	if req.Type != "" {
+		rule.Type = req.Type
+	}
+	if req.AssetTypes != nil {
+		rule.AssetTypes = req.AssetTypes
+	}
+	if req.Severity != "" {
+		rule.Severity = req.Severity
+	}
+	if req.Query != nil {
+		rule.Query = &cloudsecTypes.QueryResponse{XQL: req.Query.XQL}
+	}
+	if req.Metadata != nil {
+		rule.Metadata = ruleMetadata(req.Metadata)
+	}
+	if req.ComplianceMetadata != nil {
+		rule.ComplianceMetadata = complianceMetadata(req.ComplianceMetadata)
+	}
+	if req.Labels != nil {
+		rule.Labels = req.Labels
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	rule.LastModifiedBy = Actor
+	rule.LastModifiedOn = now()
+	writeJSON(w, http.StatusOK, *rule)
+}
+
+func (s *Server) deleteCloudSecRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.rules.remove(id) {
+		notFound(w, r, "rule", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ruleMetadata returns the metadata of a rule created or updated with the
+// given request metadata.
+func ruleMetadata(req *cloudsecTypes.MetadataRequest) *cloudsecTypes.MetadataResponse {
+	metadata := &cloudsecTypes.MetadataResponse{}
+	if req != nil && req.Issue != nil {
+		metadata.Issue = &cloudsecTypes.IssueResponse{Recommendation: req.Issue.Recommendation}
+	}
+	return metadata
+}
+
+// complianceMetadata returns the compliance metadata of a rule created or
+// updated with the given control references.
+func complianceMetadata(inputs []cloudsecTypes.ComplianceMetadataInput) []cloudsecTypes.ComplianceMetadata {
+	metadata := []cloudsecTypes.ComplianceMetadata{}
+	for _, input := range inputs {
+		metadata = append(metadata, cloudsecTypes.ComplianceMetadata{
+			StandardID: input.StandardID,
+			ControlID:  input.ControlID,
+		})
+	}
+	return metadata
+}
+
+func (s *Server) createCloudSecPolicy(w http.ResponseWriter, r *http.Request) {
+	var req cloudsecTypes.PolicyCreateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" || req.RuleMatchingType == "" || req.AssetMatchingType == "" {
+		badRequest(w, r, "name, rule_matching_type and asset_matching_type are required")
+		return
+	}
+	for _, policy := range s.policies.all() {
+		if policy.Name == req.Name {
+			conflict(w, r, "policy with name "+req.Name+" already exists")
+			return
+		}
+	}
+	ts := now()
+	policy := cloudsecTypes.PolicyResponse{
+		ID:                        s.newUUID(),
+		Name:                      req.Name,
+		Description:               req.Description,
+		Labels:                    nonNil(req.Labels),
+		RuleMatchingType:          req.RuleMatchingType,
+		AssociatedRuleFilter:      req.AssociatedRuleFilter,
+		AssociatedRuleIDs:         nonNil(req.AssociatedRuleIDs),
+		AssetMatchingType:         req.AssetMatchingType,
+		AssociatedAssetGroupIDs:   nonNil(req.AssociatedAssetGroupIDs),
+		AssociatedCloudAccountIDs: nonNil(req.AssociatedCloudAccountIDs),
+		Enabled:                   req.Enabled == nil || *req.Enabled,
+		Mode:                      "CUSTOM",
+		CreationTime:              ts,
+		CreatedBy:                 Actor,
+		ModificationTime:          ts,
+		ModifiedBy:                Actor,
+	}
+	s.policies.put(policy.ID, policy)
+	writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) getCloudSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.policies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) searchCloudSecPolicies(w http.ResponseWriter, r *http.Request) {
+	var req searchRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	policies, count, err := run(s.policies.all(), req.query())
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, cloudsecTypes.SearchPoliciesResponse{
+		Data: policies,
+		Metadata: cloudsecTypes.SearchMetadata{
+			FilterCount: int64(count),
+			TotalCount:  int64(s.policies.len()),
+		},
+	})
+}
+
+func (s *Server) updateCloudSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.policies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	var req cloudsecTypes.PolicyUpdateRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name != "" {
+		policy.Name = req.Name
+	}
+	if req.Description != "" {
+		policy.Description = req.Description
+	}
+	if req.Labels != nil {
+		policy.Labels = req.Labels
+	}
+	if req.RuleMatchingType != "" {
+		policy.RuleMatchingType = req.RuleMatchingType
+	}
+	if req.AssociatedRuleFilter != nil {
+		policy.AssociatedRuleFilter = req.AssociatedRuleFilter
+	}
+	if req.AssociatedRuleIDs != nil {
+		policy.AssociatedRuleIDs = req.AssociatedRuleIDs
+	}
+	if req.AssetMatchingType != "" {
+		policy.AssetMatchingType = req.AssetMatchingType
+	}
+	if req.AssociatedAssetGroupIDs != nil {
+		policy.AssociatedAssetGroupIDs = req.AssociatedAssetGroupIDs
+	}
+	if req.AssociatedCloudAccountIDs != nil {
+		policy.AssociatedCloudAccountIDs = req.AssociatedCloudAccountIDs
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	policy.ModificationTime = now()
+	policy.ModifiedBy = Actor
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) deleteCloudSecPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.policies.remove(id) {
+		notFound(w, r, "policy", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,525 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	commonTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types"
+	complianceTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/compliance"
+)
+
+// complianceState holds the resources of the compliance module.
+type complianceState struct {
+	standards          *table[complianceTypes.Standard]
+	controls           *table[complianceTypes.Control]
+	assessmentProfiles *table[complianceTypes.AssessmentProfile]
+}
+
+func newComplianceState() complianceState {
+	return complianceState{
+		standards:          newTable[complianceTypes.Standard](),
+		controls:           newTable[complianceTypes.Control](),
+		assessmentProfiles: newTable[complianceTypes.AssessmentProfile](),
+	}
+}
+
+// Filter aliases map the fields of the compliance list filters to the keys
+// of the listed resources.
+var (
+	standardFilterAliases = map[string]string{
+		"creation_time": "insert_ts",
+	}
+	controlFilterAliases = map[string]string{
+		"id":            "CONTROL_ID",
+		"name":          "CONTROL_NAME",
+		"creation_time": "INSERTION_TIME",
+	}
+	assessmentProfileFilterAliases = map[string]string{
+		"creation_time": "INSERT_TS",
+	}
+)
+
+func (s *Server) registerCompliance() {
+	const prefix = "POST public_api/v1/compliance/"
+	s.handle(prefix+"add_standard", s.createStandard)
+	s.handle(prefix+"get_standard", s.getStandard)
+	s.handle(prefix+"edit_standard", s.updateStandard)
+	s.handle(prefix+"delete_standard", s.deleteStandard)
+	s.handle(prefix+"get_standards", s.listStandards)
+
+	s.handle(prefix+"add_control", s.createControl)
+	s.handle(prefix+"get_control", s.getControl)
+	s.handle(prefix+"edit_control", s.updateControl)
+	s.handle(prefix+"delete_control", s.deleteControl)
+	s.handle(prefix+"get_controls", s.listControls)
+
+	s.handle(prefix+"add_assessment_profile", s.createAssessmentProfile)
+	s.handle(prefix+"get_assessment_profile", s.getAssessmentProfile)
+	s.handle(prefix+"edit_assessment_profile", s.updateAssessmentProfile)
+	s.handle(prefix+"delete_assessment_profile", s.deleteAssessmentProfile)
+	s.handle(prefix+"get_assessment_profiles", s.listAssessmentProfiles)
+}
+
+// complianceListRequest is the request of the compliance list endpoints.
+type complianceListRequest struct {
+	Filters []fieldFilter `json:"filters"`
+	Sort    *struct {
+		Field   string `json:"field"`
+		Keyword string `json:"keyword"`
+	} `json:"sort"`
+	SearchFrom int `json:"search_from"`
+	SearchTo   int `json:"search_to"`
+}
+
+// query returns the list query of the request.
+func (req complianceListRequest) query(aliases map[string]string) listQuery {
+	q := listQuery{
+		match: func(doc map[string]any) (bool, error) {
+			return matchFieldFilters(doc, req.Filters, aliases)
+		},
+		from:    req.SearchFrom,
+		to:      req.SearchTo,
+		aliases: aliases,
+	}
+	if req.Sort != nil && req.Sort.Field != "" {
+		q.sort = []sortKey{{field: req.Sort.Field, desc: isDescending(req.Sort.Keyword)}}
+	}
+	return q
+}
+
+// idRequest is the request of the endpoints that act on a single resource.
+type idRequest struct {
+	ID string `json:"id"`
+}
+
+func success(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, commonTypes.SuccessResponse{Success: true}, "reply")
+}
+
+func (s *Server) createStandard(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.CreateStandardRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.StandardName == "" {
+		badRequest(w, r, "standard_name is required")
+		return
+	}
+	for _, standard := range s.standards.all() {
+		if standard.Name == req.StandardName {
+			conflict(w, r, fmt.Sprintf("standard %q already exists", req.StandardName))
+			return
+		}
+	}
+	ts := now()
+	standard := complianceTypes.Standard{
+		ID:          s.newUUID(),
+		Name:        req.StandardName,
+		Description: req.Description,
+		Version:     "1",
+		ControlsIDs: nonNil(req.ControlsIDs),
+		Labels:      nonNil(req.Labels),
+		Revision:    1,
+		Publisher:   "Custom",
+		CreatedDate: time.UnixMilli(ts).UTC().Format(time.RFC3339),
+		CreatedBy:   Actor,
+		InsertTS:    ts,
+		ModifyTS:    ts,
+		IsCustom:    true,
+	}
+	s.standards.put(standard.ID, standard)
+	s.linkControls(standard.Name, nil, standard.ControlsIDs)
+	success(w)
+}
+
+// linkControls updates the standards of the controls when the controls of a
+// standard change.
+func (s *Server) linkControls(standardName string, previous, current []string) {
+	for _, id := range previous {
+		if control, ok := s.controls.get(id); ok {
+			control.Standards = removeString(control.Standards, standardName)
+		}
+	}
+	for _, id := range current {
+		if control, ok := s.controls.get(id); ok {
+			control.Standards = append(removeString(control.Standards, standardName), standardName)
+		}
+	}
+}
+
+// removeString returns values without v.
+func removeString(values []string, v string) []string {
+	out := []string{}
+	for _, value := range values {
+		if value != v {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func (s *Server) getStandard(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	standard, ok := s.standards.get(req.ID)
+	if !ok {
+		notFound(w, r, "standard", req.ID)
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.GetStandardResponse{
+		Standards: []complianceTypes.Standard{*standard},
+	}, "reply")
+}
+
+func (s *Server) updateStandard(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.UpdateStandardRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	standard, ok := s.standards.get(req.ID)
+	if !ok {
+		notFound(w, r, "standard", req.ID)
+		return
+	}
+	if !standard.IsCustom {
+		badRequest(w, r, "built-in standards cannot be modified")
+		return
+	}
+	if req.Labels == nil || req.ControlsIDs == nil {
+		badRequest(w, r, "labels and controls_ids are required")
+		return
+	}
+	s.linkControls(standard.Name, standard.ControlsIDs, nil)
+	if req.StandardName != "" {
+		standard.Name = req.StandardName
+	}
+	if req.Description != "" {
+		standard.Description = req.Description
+	}
+	standard.Labels = req.Labels
+	standard.ControlsIDs = req.ControlsIDs
+	standard.Revision++
+	standard.ModifyTS = now()
+	s.linkControls(standard.Name, nil, standard.ControlsIDs)
+	success(w)
+}
+
+func (s *Server) deleteStandard(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	standard, ok := s.standards.get(req.ID)
+	if !ok {
+		notFound(w, r, "standard", req.ID)
+		return
+	}
+	s.linkControls(standard.Name, standard.ControlsIDs, nil)
+	s.standards.remove(req.ID)
+	success(w)
+}
+
+func (s *Server) listStandards(w http.ResponseWriter, r *http.Request) {
+	var req complianceListRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	standards, count, err := run(s.standards.all(), req.query(standardFilterAliases))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.ListStandardsResponse{
+		TotalCount:  count,
+		ResultCount: len(standards),
+		Standards:   standards,
+	}, "reply")
+}
+
+func (s *Server) createControl(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.CreateControlRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.ControlName == "" || req.Category == "" || req.Subcategory == "" {
+		badRequest(w, r, "control_name, category and subcategory are required")
+		return
+	}
+	ts := now()
+	control := complianceTypes.Control{
+		ID:               s.newUUID(),
+		Name:             req.ControlName,
+		Description:      req.Description,
+		Category:         req.Category,
+		Subcategory:      req.Subcategory,
+		Standards:        []string{},
+		Supported:        true,
+		InsertionTime:    ts,
+		ModificationTime: ts,
+		CreatedBy:        Actor,
+		AdditionalData:   []any{},
+		ComplianceRules:  []any{},
+		Revision:         "1",
+		AutomationStatus: "MANUAL",
+		Enabled:          true,
+		IsCustom:         true,
+		Status:           "ACTIVE",
+	}
+	s.controls.put(control.ID, control)
+	writeJSON(w, http.StatusOK, complianceTypes.CreateControlResponse{Success: true, ControlID: control.ID}, "reply")
+}
+
+func (s *Server) getControl(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	control, ok := s.controls.get(req.ID)
+	if !ok {
+		notFound(w, r, "control", req.ID)
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.GetControlResponse{
+		Control: []complianceTypes.Control{*control},
+	}, "reply")
+}
+
+func (s *Server) updateControl(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.UpdateControlRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	control, ok := s.controls.get(req.ID)
+	if !ok {
+		notFound(w, r, "control", req.ID)
+		return
+	}
+	if !control.IsCustom {
+		badRequest(w, r, "built-in controls cannot be modified")
+		return
+	}
+	if req.ControlName != "" {
+		control.Name = req.ControlName
+	}
+	if req.Description != "" {
+		control.Description = req.Description
+	}
+	if req.Category != "" {
+		control.Category = req.Category
+	}
+	if req.Subcategory != "" {
+		control.Subcategory = req.Subcategory
+	}
+	modifiedBy := Actor
+	control.ModifiedBy = &modifiedBy
+	control.ModificationTime = now()
+	revision, _ := strconv.Atoi(control.Revision)
+	control.Revision = strconv.Itoa(revision + 1)
+	success(w)
+}
+
+func (s *Server) deleteControl(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if !s.controls.remove(req.ID) {
+		notFound(w, r, "control", req.ID)
+		return
+	}
+	for _, standard := range s.standards.all() {
+		if ids := removeString(standard.ControlsIDs, req.ID); len(ids) != len(standard.ControlsIDs) {
+			stored, _ := s.standards.get(standard.ID)
+			stored.ControlsIDs = ids
+		}
+	}
+	success(w)
+}
+
+func (s *Server) listControls(w http.ResponseWriter, r *http.Request) {
+	var req complianceListRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	controls, count, err := run(s.controls.all(), req.query(controlFilterAliases))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.ListControlsResponse{
+		TotalCount:  count,
+		ResultCount: len(controls),
+		Controls:    controls,
+	}, "reply")
+}
+
+func (s *Server) createAssessmentProfile(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.CreateAssessmentProfileRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.ProfileName == "" || req.StandardID == "" || req.AssetGroupID == "" {
+		badRequest(w, r, "profile_name, standard_id and asset_group_id are required")
+		return
+	}
+	ts := now()
+	profile := complianceTypes.AssessmentProfile{
+		ID:         s.newUUID(),
+		Enabled:    true,
+		InsertTS:   ts,
+		ModifyTS:   ts,
+		CreatedBy:  Actor,
+		ModifiedBy: Actor,
+	}
+	if !s.applyAssessmentProfile(w, r, &profile, complianceTypes.UpdateAssessmentProfileRequest{
+		ProfileName:         req.ProfileName,
+		AssetGroupID:        req.AssetGroupID,
+		StandardID:          req.StandardID,
+		Description:         req.Description,
+		ReportTargets:       req.ReportTargets,
+		ReportType:          req.ReportType,
+		EvaluationFrequency: req.EvaluationFrequency,
+	}) {
+		return
+	}
+	s.assessmentProfiles.put(profile.ID, profile)
+	s.countAssessmentProfiles()
+	success(w)
+}
+
+// applyAssessmentProfile sets the fields of profile from req. It writes a 400
+// response and returns false if req refers to unknown resources.
+func (s *Server) applyAssessmentProfile(w http.ResponseWriter, r *http.Request, profile *complianceTypes.AssessmentProfile, req complianceTypes.UpdateAssessmentProfileRequest) bool {
+	if req.StandardID != "" {
+		standard, ok := s.standards.get(req.StandardID)
+		if !ok {
+			badRequest(w, r, fmt.Sprintf("standard %s does not exist", req.StandardID))
+			return false
+		}
+		profile.StandardID = standard.ID
+		profile.StandardName = standard.Name
+	}
+	if req.AssetGroupID != "" {
+		group, ok := s.assetGroups.get(req.AssetGroupID)
+		if !ok {
+			badRequest(w, r, fmt.Sprintf("asset group %s does not exist", req.AssetGroupID))
+			return false
+		}
+		profile.AssetGroupID = group.ID
+		profile.AssetGroupName = group.Name
+	}
+	switch strings.ToLower(req.Enabled) {
+	case "":
+	case "yes":
+		profile.Enabled = true
+	case "no":
+		profile.Enabled = false
+	default:
+		badRequest(w, r, fmt.Sprintf("invalid enabled value %q", req.Enabled))
+		return false
+	}
+	if req.ProfileName != "" {
+		profile.Name = req.ProfileName
+	}
+	if req.Description != "" {
+		profile.Description = req.Description
+	}
+	profile.ReportType = "NONE"
+	if req.ReportType != "" {
+		profile.ReportType = strings.ToUpper(req.ReportType)
+	}
+	profile.ReportTargets = nonNil(req.ReportTargets)
+	profile.ReportFrequency = nil
+	if req.EvaluationFrequency != "" {
+		frequency := req.EvaluationFrequency
+		profile.ReportFrequency = &frequency
+	}
+	return true
+}
+
+// countAssessmentProfiles updates the number of assessment profiles of the
+// standards.
+func (s *Server) countAssessmentProfiles() {
+	counts := make(map[string]int)
+	for _, profile := range s.assessmentProfiles.all() {
+		counts[profile.StandardID]++
+	}
+	for _, standard := range s.standards.all() {
+		stored, _ := s.standards.get(standard.ID)
+		stored.AssessmentsProfilesCount = counts[standard.ID]
+	}
+}
+
+func (s *Server) getAssessmentProfile(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	profile, ok := s.assessmentProfiles.get(req.ID)
+	if !ok {
+		notFound(w, r, "assessment profile", req.ID)
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.GetAssessmentProfileResponse{
+		AssessmentProfiles: []complianceTypes.AssessmentProfile{*profile},
+	}, "reply")
+}
+
+func (s *Server) updateAssessmentProfile(w http.ResponseWriter, r *http.Request) {
+	var req complianceTypes.UpdateAssessmentProfileRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	current, ok := s.assessmentProfiles.get(req.ID)
+	if !ok {
+		notFound(w, r, "assessment profile", req.ID)
+		return
+	}
+	profile := *current
+	if !s.applyAssessmentProfile(w, r, &profile, req) {
+		return
+	}
+	profile.ModifyTS = now()
+	profile.ModifiedBy = Actor
+	s.assessmentProfiles.put(req.ID, profile)
+	s.countAssessmentProfiles()
+	success(w)
+}
+
+func (s *Server) deleteAssessmentProfile(w http.ResponseWriter, r *http.Request) {
+	var req idRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if !s.assessmentProfiles.remove(req.ID) {
+		notFound(w, r, "assessment profile", req.ID)
+		return
+	}
+	s.countAssessmentProfiles()
+	success(w)
+}
+
+func (s *Server) listAssessmentProfiles(w http.ResponseWriter, r *http.Request) {
+	var req complianceListRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	profiles, count, err := run(s.assessmentProfiles.all(), req.query(assessmentProfileFilterAliases))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, complianceTypes.ListAssessmentProfilesResponse{
+		TotalCount:         count,
+		ResultCount:        len(profiles),
+		AssessmentProfiles: profiles,
+	}, "reply")
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	cwpTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/cwp"
+)
+
+// cwpState holds the resources of the CWP module.
+type cwpState struct {
+	cwpPolicies *table[cwpTypes.Policy]
+}
+
+func newCWPState() cwpState {
+	return cwpState{
+		cwpPolicies: newTable[cwpTypes.Policy](),
+	}
+}
+
+func (s *Server) registerCWP() {
+	s.handle("POST public_api/v2/cwp/policies", s.createCWPPolicy)
+	s.handle("GET public_api/v2/cwp/policies", s.listCWPPolicies)
+	s.handle("GET public_api/v2/cwp/policies/{id}", s.getCWPPolicy)
+	s.handle("PUT public_api/v2/cwp/policies/{id}", s.updateCWPPolicy)
+	s.handle("DELETE public_api/v1/cwp/policies/{id}", s.deleteCWPPolicy)
+	s.handle("GET public_api/v1/assets", s.listAssets)
+}
+
+func (s *Server) createCWPPolicy(w http.ResponseWriter, r *http.Request) {
+	var req cwpTypes.CreateOrUpdatePolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Type == "" || req.Name == "" || req.EvaluationStage == "" {
+		badRequest(w, r, "type, name and evaluationStage are required")
+		return
+	}
+	ts := timestamp()
+	policy := cwpTypes.Policy{
+		ID:         s.newUUID(),
+		CreatedAt:  ts,
+		ModifiedAt: ts,
+		CreatedBy:  Actor,
+	}
+	if !s.applyCWPPolicy(w, r, &policy, req) {
+		return
+	}
+	s.cwpPolicies.put(policy.ID, policy)
+	writeJSON(w, http.StatusOK, cwpTypes.CreateOrUpdatePolicyResponse{PolicyID: policy.ID})
+}
+
+// applyCWPPolicy sets the configurable fields of policy from req and bumps
+// its revision. It writes a 400 response and returns false if req refers to
+// unknown asset groups.
+func (s *Server) applyCWPPolicy(w http.ResponseWriter, r *http.Request, policy *cwpTypes.Policy, req cwpTypes.CreateOrUpdatePolicyRequest) bool {
+	assetGroups := []string{}
+	for _, id := range req.AssetGroupIDs {
+		group, ok := s.assetGroups.get(strconv.Itoa(id))
+		if !ok {
+			badRequest(w, r, fmt.Sprintf("asset group %d does not exist", id))
+			return false
+		}
+		assetGroups = append(assetGroups, group.Name)
+	}
+	policy.Revision++
+	policy.Type = req.Type
+	policy.Name = req.Name
+	policy.Description = req.Description
+	policy.Disabled = req.Disabled
+	policy.EvaluationModes = nonNil(req.EvaluationModes)
+	policy.EvaluationStage = req.EvaluationStage
+	policy.Condition = req.Condition
+	policy.Exception = req.Exception
+	policy.AssetScope = req.AssetScope
+	policy.AssetGroupIDs = nonNil(req.AssetGroupIDs)
+	policy.AssetGroups = assetGroups
+	policy.PolicyAction = req.PolicyAction
+	policy.PolicySeverity = req.PolicySeverity
+	policy.RemediationGuidance = req.RemediationGuidance
+	policy.PolicyRules = []cwpTypes.PolicyRule{}
+	for _, rule := range req.PolicyRules {
+		id := fmt.Sprintf("%s-%s", policy.ID, rule.RuleID)
+		rule.ID = &id
+		rule.PolicyID = &policy.ID
+		rule.PolicyRevision = &policy.Revision
+		policy.PolicyRules = append(policy.PolicyRules, rule)
+	}
+	return true
+}
+
+func (s *Server) getCWPPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.cwpPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) listCWPPolicies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	policies := []cwpTypes.Policy{}
+	for _, policy := range s.cwpPolicies.all() {
+		if matchQueryValues(query, "types", policy.Type) {
+			policies = append(policies, policy)
+		}
+	}
+	writeJSON(w, http.StatusOK, policies)
+}
+
+func (s *Server) updateCWPPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	current, ok := s.cwpPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	var req cwpTypes.CreateOrUpdatePolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	policy := *current
+	if !s.applyCWPPolicy(w, r, &policy, req) {
+		return
+	}
+	policy.ModifiedAt = timestamp()
+	s.cwpPolicies.put(id, policy)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteCWPPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if closeIssues := r.URL.Query().Get("closeIssues"); closeIssues != "" {
+		if _, err := strconv.ParseBool(closeIssues); err != nil {
+			badRequest(w, r, fmt.Sprintf("invalid closeIssues value %q", closeIssues))
+			return
+		}
+	}
+	if !s.cwpPolicies.remove(id) {
+		notFound(w, r, "policy", id)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAssets(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []any{}, "reply")
+}
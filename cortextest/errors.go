@@ -0,0 +1,83 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// errorStyle is the shape of the error responses of an endpoint.
+type errorStyle int
+
+const (
+	// errorStyleReply is the shape of the public_api endpoints:
+	// {"reply": {"err_code": 404, "err_msg": "...", "err_extra": "..."}}.
+	errorStyleReply errorStyle = iota
+	// errorStyleMessage is the shape of the platform, AppSec and CWP
+	// endpoints: {"errorCode": "NOT_FOUND", "message": "..."}.
+	errorStyleMessage
+)
+
+// errorStyleFor returns the error shape of the endpoint at path.
+func errorStyleFor(path string) errorStyle {
+	path = strings.TrimPrefix(path, "/")
+	switch {
+	case strings.HasPrefix(path, "platform/"),
+		strings.HasPrefix(path, "public_api/appsec/"),
+		strings.HasPrefix(path, "public_api/v1/cwp/"),
+		strings.HasPrefix(path, "public_api/v2/cwp/"):
+		return errorStyleMessage
+	default:
+		return errorStyleReply
+	}
+}
+
+// errorCodes are the errorCode values of errorStyleMessage responses.
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "BAD_REQUEST",
+	http.StatusUnauthorized:        "UNAUTHORIZED",
+	http.StatusForbidden:           "FORBIDDEN",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusConflict:            "CONFLICT",
+	http.StatusTooManyRequests:     "TOO_MANY_REQUESTS",
+	http.StatusInternalServerError: "INTERNAL_SERVER_ERROR",
+	http.StatusServiceUnavailable:  "SERVICE_UNAVAILABLE",
+}
+
+// writeError writes an error response in the given shape.
+func writeError(w http.ResponseWriter, style errorStyle, statusCode int, message string) {
+	switch style {
+	case errorStyleMessage:
+		code, ok := errorCodes[statusCode]
+		if !ok {
+			code = strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+		}
+		writeJSON(w, statusCode, map[string]any{
+			"errorCode": code,
+			"message":   message,
+		})
+	default:
+		writeJSON(w, statusCode, map[string]any{
+			"err_code":  statusCode,
+			"err_msg":   message,
+			"err_extra": "",
+		}, "reply")
+	}
+}
+
+// notFound writes a 404 response for the resource of the given kind.
+func notFound(w http.ResponseWriter, r *http.Request, kind, id string) {
+	writeError(w, errorStyleFor(r.URL.Path), http.StatusNotFound, kind+" "+id+" not found")
+}
+
+// badRequest writes a 400 response.
+func badRequest(w http.ResponseWriter, r *http.Request, message string) {
+	writeError(w, errorStyleFor(r.URL.Path), http.StatusBadRequest, message)
+}
+
+// conflict writes a 409 response.
+func conflict(w http.ResponseWriter, r *http.Request, message string) {
+	writeError(w, errorStyleFor(r.URL.Path), http.StatusConflict, message)
+}
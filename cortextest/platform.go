@@ -0,0 +1,857 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	platformTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/platform"
+)
+
+// tenantURL is the URL of the fake tenant in the service provider settings
+// returned by the authentication settings endpoints.
+const tenantURL = "https://cortextest.example.com"
+
+// platformState holds the resources of the platform module.
+type platformState struct {
+	users              *table[platformTypes.User]    // by email
+	iamUsers           *table[platformTypes.IamUser] // by email
+	tenants            []platformTypes.TenantInfo
+	riskyUsers         []platformTypes.ListRiskyUsersResponse
+	riskyHosts         []platformTypes.ListRiskyHostsResponse
+	userGroups         *table[platformTypes.UserGroup]
+	roles              *table[platformTypes.RoleListItem]
+	scopes             map[string]platformTypes.Scope // by entity type and ID
+	assetGroups        *table[platformTypes.AssetGroup]
+	authSettings       *table[platformTypes.AuthSettings] // by domain
+	notificationRules  *table[platformTypes.NotificationForwardingConfigurationAPI]
+	syslogIntegrations *table[platformTypes.SyslogIntegration]
+}
+
+func newPlatformState() platformState {
+	return platformState{
+		users:              newTable[platformTypes.User](),
+		iamUsers:           newTable[platformTypes.IamUser](),
+		userGroups:         newTable[platformTypes.UserGroup](),
+		roles:              newTable[platformTypes.RoleListItem](),
+		scopes:             make(map[string]platformTypes.Scope),
+		assetGroups:        newTable[platformTypes.AssetGroup](),
+		authSettings:       newTable[platformTypes.AuthSettings](),
+		notificationRules:  newTable[platformTypes.NotificationForwardingConfigurationAPI](),
+		syslogIntegrations: newTable[platformTypes.SyslogIntegration](),
+	}
+}
+
+// AddUsers adds users to the tenant, as returned by the RBAC user
+// endpoints. Users are identified by their email.
+func (s *Server) AddUsers(users ...platformTypes.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range users {
+		s.users.put(user.Email, user)
+	}
+}
+
+// AddIAMUsers adds users to the tenant, as returned by the IAM user
+// endpoints. Users are identified by their email.
+func (s *Server) AddIAMUsers(users ...platformTypes.IamUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range users {
+		s.iamUsers.put(user.Email, user)
+	}
+}
+
+// AddTenants adds tenants to the tenant information endpoint.
+func (s *Server) AddTenants(tenants ...platformTypes.TenantInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants = append(s.tenants, tenants...)
+}
+
+// AddRiskyUsers adds users to the risk endpoints.
+func (s *Server) AddRiskyUsers(users ...platformTypes.ListRiskyUsersResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.riskyUsers = append(s.riskyUsers, users...)
+}
+
+// AddRiskyHosts adds hosts to the risk endpoints.
+func (s *Server) AddRiskyHosts(hosts ...platformTypes.ListRiskyHostsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.riskyHosts = append(s.riskyHosts, hosts...)
+}
+
+func (s *Server) registerPlatform() {
+	s.handle("GET api_keys/validate", s.validateAPIKey)
+	s.handle("GET public_api/v1/health_check/{$}", s.healthCheck)
+	s.handle("POST public_api/v1/get_tenant_info/{$}", s.getTenantInfo)
+
+	s.handle("POST public_api/v1/rbac/get_users/{$}", s.listUsers)
+	s.handle("POST public_api/v1/rbac/get_user_group/{$}", s.getUserGroups)
+	s.handle("POST public_api/v1/rbac/set_user_role/{$}", s.setUserRole)
+	s.handle("POST public_api/v1/get_risk_score/{$}", s.getRiskScore)
+	s.handle("POST public_api/v1/risk/get_risky_users/{$}", s.listRiskyUsers)
+	s.handle("POST public_api/v1/risky_hosts/{$}", s.listRiskyHosts)
+
+	s.handle("GET platform/iam/v1/user-group", s.listUserGroups)
+	s.handle("POST platform/iam/v1/user-group", s.createUserGroup)
+	s.handle("PATCH platform/iam/v1/user-group/{id}", s.editUserGroup)
+	s.handle("DELETE platform/iam/v1/user-group/{id}", s.deleteUserGroup)
+	s.handle("GET platform/iam/v1/user", s.listIAMUsers)
+	s.handle("GET platform/iam/v1/user/{email}", s.getIAMUser)
+	s.handle("PATCH platform/iam/v1/user/{email}", s.editIAMUser)
+	s.handle("GET platform/iam/v1/scope/{type}/{id}", s.getScope)
+	s.handle("PUT platform/iam/v1/scope/{type}/{id}", s.editScope)
+	s.handle("GET platform/iam/v1/role/{$}", s.listRoles)
+	s.handle("POST platform/iam/v1/role/{$}", s.createRole)
+	s.handle("DELETE platform/iam/v1/role/{id}", s.deleteRole)
+	s.handle("GET platform/iam/v1/role/permission-config", s.listPermissionConfigs)
+
+	s.handle("POST public_api/v1/asset-groups/create", s.createAssetGroup)
+	s.handle("POST public_api/v1/asset-groups/update/{id}", s.updateAssetGroup)
+	s.handle("POST public_api/v1/asset-groups/delete/{id}", s.deleteAssetGroup)
+	s.handle("POST public_api/v1/asset-groups", s.listAssetGroups)
+
+	s.handle("POST public_api/v1/authentication-settings/get/metadata", s.listIDPMetadata)
+	s.handle("POST public_api/v1/authentication-settings/get/settings", s.listAuthSettings)
+	s.handle("POST public_api/v1/authentication-settings/create", s.createAuthSettings)
+	s.handle("POST public_api/v1/authentication-settings/update", s.updateAuthSettings)
+	s.handle("POST public_api/v1/authentication-settings/delete", s.deleteAuthSettings)
+
+	s.handle("POST platform/notifications/v1/rule", s.createNotificationRule)
+	s.handle("GET platform/notifications/v1/rule/{id}", s.getNotificationRule)
+	s.handle("PUT platform/notifications/v1/rule/{id}", s.updateNotificationRule)
+	s.handle("DELETE platform/notifications/v1/rule/{id}", s.deleteNotificationRule)
+	s.handle("GET platform/notifications/v1/list-rules", s.listNotificationRules)
+	s.handle("PATCH platform/notifications/v1/update-rule-status/{id}", s.toggleNotificationRule)
+
+	s.handle("POST public_api/v1/integrations/syslog/create", s.createSyslogIntegration)
+	s.handle("POST public_api/v1/integrations/syslog/get", s.listSyslogIntegrations)
+}
+
+func (s *Server) validateAPIKey(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, "true")
+}
+
+func (s *Server) healthCheck(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, platformTypes.HealthCheckResponse{
+		Service:   "cortextest",
+		Status:    "available",
+		Timestamp: now(),
+	}, "reply")
+}
+
+func (s *Server) getTenantInfo(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.GetTenantInfoRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	tenants := []platformTypes.TenantInfo{}
+	for _, tenant := range s.tenants {
+		if len(req.Tenants) == 0 || slices.Contains(req.Tenants, tenant.TenantID) {
+			tenants = append(tenants, tenant)
+		}
+	}
+	writeJSON(w, http.StatusOK, tenants, "reply")
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.users.all(), "reply")
+}
+
+func (s *Server) getUserGroups(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.GetUserGroupRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	groups := []platformTypes.UserGroup{}
+	for _, group := range s.userGroups.all() {
+		if slices.Contains(req.GroupNames, group.GroupName) {
+			groups = append(groups, group)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": groups}, "reply")
+}
+
+func (s *Server) setUserRole(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.SetRoleRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	count := 0
+	for _, email := range req.UserEmails {
+		if user, ok := s.users.get(email); ok {
+			user.RoleName = req.RoleName
+			count++
+		}
+		if user, ok := s.iamUsers.get(email); ok {
+			user.RoleName = req.RoleName
+		}
+	}
+	writeJSON(w, http.StatusOK, platformTypes.SetRoleResponse{UpdateCount: strconv.Itoa(count)}, "reply")
+}
+
+func (s *Server) getRiskScore(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.GetRiskScoreRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	var score platformTypes.GetRiskScoreResponse
+	for _, user := range s.riskyUsers {
+		if user.ID == req.ID {
+			convert(user, &score)
+			writeJSON(w, http.StatusOK, score, "reply")
+			return
+		}
+	}
+	for _, host := range s.riskyHosts {
+		if host.ID == req.ID {
+			convert(host, &score)
+			writeJSON(w, http.StatusOK, score, "reply")
+			return
+		}
+	}
+	notFound(w, r, "entity", req.ID)
+}
+
+func (s *Server) listRiskyUsers(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, nonNil(s.riskyUsers), "reply")
+}
+
+func (s *Server) listRiskyHosts(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, nonNil(s.riskyHosts), "reply")
+}
+
+func (s *Server) listUserGroups(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.userGroups.all(), "data")
+}
+
+func (s *Server) createUserGroup(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.UserGroupCreateRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.GroupName == "" {
+		badRequest(w, r, "group_name is required")
+		return
+	}
+	for _, group := range s.userGroups.all() {
+		if group.GroupName == req.GroupName {
+			conflict(w, r, fmt.Sprintf("user group %q already exists", req.GroupName))
+			return
+		}
+	}
+	ts := now()
+	group := platformTypes.UserGroup{
+		GroupID:        s.newUUID(),
+		GroupName:      req.GroupName,
+		Description:    req.Description,
+		RoleName:       req.RoleName,
+		PrettyRoleName: s.prettyRoleName(req.RoleName),
+		CreatedBy:      Actor,
+		CreatedTS:      ts,
+		UpdatedTS:      ts,
+		Users:          nonNil(req.Users),
+		GroupType:      "CUSTOM",
+		NestedGroups:   s.nestedGroups(req.NestedGroups),
+		IDPGroups:      nonNil(req.IDPGroups),
+	}
+	s.userGroups.put(group.GroupID, group)
+	writeJSON(w, http.StatusOK, platformTypes.UserGroupCreateResponse{
+		Message: fmt.Sprintf("user group with group id %s created successfully", group.GroupID),
+	}, "data")
+}
+
+func (s *Server) editUserGroup(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	group, ok := s.userGroups.get(id)
+	if !ok {
+		notFound(w, r, "user group", id)
+		return
+	}
+	var req platformTypes.UserGroupEditRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.GroupName != "" {
+		group.GroupName = req.GroupName
+	}
+	if req.RoleName != "" {
+		group.RoleName = req.RoleName
+		group.PrettyRoleName = s.prettyRoleName(req.RoleName)
+	}
+	if req.Description != "" {
+		group.Description = req.Description
+	}
+	if req.Users != nil {
+		group.Users = req.Users
+	}
+	if req.NestedGroupIDs != nil {
+		group.NestedGroups = s.nestedGroups(req.NestedGroupIDs)
+	}
+	if req.IDPGroups != nil {
+		group.IDPGroups = req.IDPGroups
+	}
+	group.UpdatedTS = now()
+	writeJSON(w, http.StatusOK, platformTypes.UserGroupEditResponse{
+		Message: fmt.Sprintf("user group with group id %s updated successfully", id),
+	}, "data")
+}
+
+func (s *Server) deleteUserGroup(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.userGroups.remove(id) {
+		notFound(w, r, "user group", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, platformTypes.UserGroupDeleteResponse{
+		Message: fmt.Sprintf("user group with group id %s deleted successfully", id),
+	}, "data")
+}
+
+// nestedGroups returns the user groups with the given IDs.
+func (s *Server) nestedGroups(ids []string) []platformTypes.NestedGroup {
+	groups := []platformTypes.NestedGroup{}
+	for _, id := range ids {
+		if group, ok := s.userGroups.get(id); ok {
+			groups = append(groups, platformTypes.NestedGroup{GroupID: id, GroupName: group.GroupName})
+		}
+	}
+	return groups
+}
+
+// prettyRoleName returns the display name of the role with the given ID.
+func (s *Server) prettyRoleName(roleID string) string {
+	if role, ok := s.roles.get(roleID); ok {
+		return role.PrettyName
+	}
+	return roleID
+}
+
+func (s *Server) listIAMUsers(w http.ResponseWriter, _ *http.Request) {
+	users := s.iamUsers.all()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":     users,
+		"metadata": platformTypes.IamUsersMetadata{TotalCount: len(users)},
+	})
+}
+
+func (s *Server) getIAMUser(w http.ResponseWriter, r *http.Request) {
+	email := r.PathValue("email")
+	user, ok := s.iamUsers.get(email)
+	if !ok {
+		notFound(w, r, "user", email)
+		return
+	}
+	writeJSON(w, http.StatusOK, *user, "data")
+}
+
+func (s *Server) editIAMUser(w http.ResponseWriter, r *http.Request) {
+	email := r.PathValue("email")
+	user, ok := s.iamUsers.get(email)
+	if !ok {
+		notFound(w, r, "user", email)
+		return
+	}
+	var req platformTypes.IamUserEditRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.FirstName != nil {
+		user.FirstName = *req.FirstName
+	}
+	if req.LastName != nil {
+		user.LastName = *req.LastName
+	}
+	if req.RoleId != nil {
+		user.RoleName = *req.RoleId
+	}
+	if req.PhoneNumber != nil {
+		user.PhoneNumber = *req.PhoneNumber
+	}
+	if req.Status != nil {
+		user.Status = *req.Status
+	}
+	if req.Hidden != nil {
+		user.Hidden = *req.Hidden
+	}
+	if req.UserGroups != nil {
+		user.Groups = s.nestedGroups(req.UserGroups)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("user %s updated successfully", email),
+	}, "data")
+}
+
+func (s *Server) getScope(w http.ResponseWriter, r *http.Request) {
+	scope := s.scopes[r.PathValue("type")+"/"+r.PathValue("id")]
+	writeJSON(w, http.StatusOK, scope, "data")
+}
+
+func (s *Server) editScope(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.EditScopeRequestData
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	var scope platformTypes.Scope
+	if req.Assets != nil {
+		scope.Assets = &platformTypes.Assets{Mode: req.Assets.Mode, AssetGroups: []platformTypes.ScopeAssetGroup{}}
+		for _, id := range req.Assets.AssetGroupIDs {
+			group, ok := s.assetGroups.get(strconv.Itoa(id))
+			if !ok {
+				badRequest(w, r, fmt.Sprintf("asset group %d does not exist", id))
+				return
+			}
+			scope.Assets.AssetGroups = append(scope.Assets.AssetGroups, platformTypes.ScopeAssetGroup{ID: id, Name: group.Name})
+		}
+	}
+	if req.DatasetsRows != nil {
+		scope.DatasetsRows = &platformTypes.DatasetsRows{
+			DefaultFilterMode: req.DatasetsRows.DefaultFilterMode,
+			Filters:           nonNil(req.DatasetsRows.Filters),
+		}
+	}
+	if req.Endpoints != nil {
+		scope.Endpoints = &platformTypes.Endpoints{}
+		if groups := req.Endpoints.EndpointGroups; groups != nil {
+			scope.Endpoints.EndpointGroups = &platformTypes.EndpointGroups{Mode: groups.Mode, Tags: tags(groups.Names)}
+		}
+		if endpointTags := req.Endpoints.EndpointTags; endpointTags != nil {
+			scope.Endpoints.EndpointTags = &platformTypes.EndpointTags{Mode: endpointTags.Mode, Tags: tags(endpointTags.Names)}
+		}
+	}
+	if req.CasesIssues != nil {
+		scope.CasesIssues = &platformTypes.CasesIssues{Mode: req.CasesIssues.Mode, Tags: tags(req.CasesIssues.Names)}
+	}
+	s.scopes[r.PathValue("type")+"/"+r.PathValue("id")] = scope
+	writeJSON(w, http.StatusOK, map[string]string{"message": "scope updated successfully"}, "data")
+}
+
+// tags returns the scope tags with the given names.
+func tags(names []string) []platformTypes.Tag {
+	tags := []platformTypes.Tag{}
+	for _, name := range names {
+		tags = append(tags, platformTypes.Tag{TagID: name, TagName: name})
+	}
+	return tags
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, _ *http.Request) {
+	var resp platformTypes.ListRolesResponse
+	resp.Data = s.roles.all()
+	resp.Metadata.TotalCount = len(resp.Data)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) createRole(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.RoleCreateRequestData
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.PrettyName == "" {
+		badRequest(w, r, "pretty_name is required")
+		return
+	}
+	for _, role := range s.roles.all() {
+		if role.PrettyName == req.PrettyName {
+			conflict(w, r, fmt.Sprintf("role %q already exists", req.PrettyName))
+			return
+		}
+	}
+	ts := now()
+	role := platformTypes.RoleListItem{
+		RoleID:      s.newUUID(),
+		PrettyName:  req.PrettyName,
+		Description: req.Description,
+		IsCustom:    true,
+		CreatedBy:   Actor,
+		CreatedTs:   ts,
+		UpdatedTs:   ts,
+	}
+	s.roles.put(role.RoleID, role)
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("role_id %s created successfully", role.RoleID),
+	}, "data")
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.roles.remove(id) {
+		notFound(w, r, "role", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("role_id %s deleted successfully", id),
+	}, "data")
+}
+
+func (s *Server) listPermissionConfigs(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, platformTypes.PermissionConfigsResponseData{
+		RbacPermissions: []platformTypes.RbacPermission{},
+		DatasetGroups:   []platformTypes.DatasetGroup{},
+	}, "data")
+}
+
+// assetGroupResponse is the response of the asset group mutations.
+type assetGroupResponse struct {
+	Success      bool `json:"success"`
+	AssetGroupID int  `json:"asset_group_id"`
+}
+
+func (s *Server) createAssetGroup(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.CreateOrUpdateAssetGroupRequest
+	if !decodeRequest(w, r, &req, "request_data", "asset_group") {
+		return
+	}
+	if req.GroupName == "" {
+		badRequest(w, r, "group_name is required")
+		return
+	}
+	for _, group := range s.assetGroups.all() {
+		if group.Name == req.GroupName {
+			conflict(w, r, fmt.Sprintf("asset group %q already exists", req.GroupName))
+			return
+		}
+	}
+	ts := now()
+	group := platformTypes.AssetGroup{
+		ID:                  s.nextSeq(),
+		Name:                req.GroupName,
+		Type:                req.GroupType,
+		Description:         req.GroupDescription,
+		Filter:              []platformTypes.AssetGroupFilter{},
+		CreationTime:        ts,
+		CreatedBy:           Actor,
+		CreatedByPretty:     Actor,
+		LastUpdateTime:      ts,
+		ModifiedBy:          Actor,
+		ModifiedByPretty:    Actor,
+		MembershipPredicate: req.MembershipPredicate,
+	}
+	s.assetGroups.put(strconv.Itoa(group.ID), group)
+	writeJSON(w, http.StatusOK, assetGroupResponse{Success: true, AssetGroupID: group.ID}, "reply", "data")
+}
+
+func (s *Server) updateAssetGroup(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	group, ok := s.assetGroups.get(id)
+	if !ok {
+		notFound(w, r, "asset group", id)
+		return
+	}
+	var req platformTypes.CreateOrUpdateAssetGroupRequest
+	if !decodeRequest(w, r, &req, "request_data", "asset_group") {
+		return
+	}
+	if req.GroupName != "" {
+		group.Name = req.GroupName
+	}
+	if req.GroupType != "" {
+		group.Type = req.GroupType
+	}
+	group.Description = req.GroupDescription
+	group.MembershipPredicate = req.MembershipPredicate
+	group.LastUpdateTime = now()
+	group.ModifiedBy = Actor
+	group.ModifiedByPretty = Actor
+	writeJSON(w, http.StatusOK, assetGroupResponse{Success: true, AssetGroupID: group.ID}, "reply", "data")
+}
+
+func (s *Server) deleteAssetGroup(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	group, ok := s.assetGroups.get(id)
+	if !ok {
+		notFound(w, r, "asset group", id)
+		return
+	}
+	groupID := group.ID
+	s.assetGroups.remove(id)
+	writeJSON(w, http.StatusOK, assetGroupResponse{Success: true, AssetGroupID: groupID}, "reply", "data")
+}
+
+// listAssetGroupsRequest is the request of the asset group list endpoint.
+type listAssetGroupsRequest struct {
+	Filters    *criteria  `json:"filters"`
+	Sort       []sortSpec `json:"sort"`
+	SearchFrom int        `json:"search_from"`
+	SearchTo   int        `json:"search_to"`
+}
+
+// sortSpec is a sort criterion in the search format of the API.
+type sortSpec struct {
+	Field string `json:"FIELD"`
+	Order string `json:"ORDER"`
+}
+
+// sortKeys returns the sort keys of the given sort criteria.
+func sortKeys(specs []sortSpec) []sortKey {
+	keys := make([]sortKey, 0, len(specs))
+	for _, spec := range specs {
+		keys = append(keys, sortKey{field: spec.Field, desc: isDescending(spec.Order)})
+	}
+	return keys
+}
+
+func (s *Server) listAssetGroups(w http.ResponseWriter, r *http.Request) {
+	var req listAssetGroupsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	groups, _, err := run(s.assetGroups.all(), listQuery{
+		match: func(doc map[string]any) (bool, error) { return req.Filters.match(doc, nil) },
+		sort:  sortKeys(req.Sort),
+		from:  req.SearchFrom,
+		to:    req.SearchTo,
+	})
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, groups, "reply", "data")
+}
+
+func (s *Server) listIDPMetadata(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, platformTypes.ListIDPMetadataResponse{
+		TenantID:    "cortextest",
+		SpEntityID:  tenantURL + "/saml/metadata",
+		SpLogoutURL: tenantURL + "/saml/logout",
+		SpURL:       tenantURL + "/saml/acs",
+	})
+}
+
+func (s *Server) listAuthSettings(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.authSettings.all(), "reply")
+}
+
+func (s *Server) createAuthSettings(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.CreateAuthSettingsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if _, ok := s.authSettings.get(req.Domain); ok {
+		conflict(w, r, fmt.Sprintf("authentication settings for domain %q already exist", req.Domain))
+		return
+	}
+	var settings platformTypes.AuthSettings
+	convert(req, &settings)
+	settings.TenantID = "cortextest"
+	settings.IDPEnabled = true
+	settings.SpEntityID = tenantURL + "/saml/metadata"
+	settings.SpLogoutURL = tenantURL + "/saml/logout"
+	settings.SpURL = tenantURL + "/saml/acs"
+	s.authSettings.put(req.Domain, settings)
+	writeJSON(w, http.StatusOK, true, "reply")
+}
+
+func (s *Server) updateAuthSettings(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.UpdateAuthSettingsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	current, ok := s.authSettings.get(req.CurrentDomain)
+	if !ok {
+		notFound(w, r, "authentication settings for domain", req.CurrentDomain)
+		return
+	}
+	settings := *current
+	settings.Name = req.Name
+	settings.Domain = req.NewDomain
+	settings.DefaultRole = req.DefaultRole
+	settings.IsAccountRole = req.IsAccountRole
+	settings.Mappings = req.Mappings
+	settings.AdvancedSettings = req.AdvancedSettings
+	settings.IDPSingleSignOnURL = req.IDPSingleSignOnURL
+	settings.IDPCertificate = req.IDPCertificate
+	settings.IDPIssuer = req.IDPIssuer
+	settings.MetadataURL = req.MetadataURL
+	if req.NewDomain != req.CurrentDomain {
+		s.authSettings.remove(req.CurrentDomain)
+	}
+	s.authSettings.put(req.NewDomain, settings)
+	writeJSON(w, http.StatusOK, true, "reply")
+}
+
+func (s *Server) deleteAuthSettings(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.DeleteAuthSettingsRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if !s.authSettings.remove(req.Domain) {
+		notFound(w, r, "authentication settings for domain", req.Domain)
+		return
+	}
+	writeJSON(w, http.StatusOK, true, "reply")
+}
+
+func (s *Server) createNotificationRule(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.CreateOrUpdateNotificationForwardingConfigurationRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	var rule platformTypes.NotificationForwardingConfigurationAPI
+	convert(req, &rule)
+	rule.RuleUUID = s.newUUID()
+	rule.CreatedBy = Actor
+	rule.CreatedAt = int(now())
+	rule.ModifiedAt = rule.CreatedAt
+	rule.Enabled = true
+	rule.TimeZone = "UTC"
+	s.notificationRules.put(rule.RuleUUID, rule)
+	writeJSON(w, http.StatusOK, rule, "data")
+}
+
+func (s *Server) getNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.notificationRules.get(id)
+	if !ok {
+		notFound(w, r, "notification rule", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *rule, "data")
+}
+
+func (s *Server) updateNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	current, ok := s.notificationRules.get(id)
+	if !ok {
+		notFound(w, r, "notification rule", id)
+		return
+	}
+	var req platformTypes.CreateOrUpdateNotificationForwardingConfigurationRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	var rule platformTypes.NotificationForwardingConfigurationAPI
+	convert(req, &rule)
+	rule.RuleUUID = id
+	rule.CreatedBy = current.CreatedBy
+	rule.CreatedAt = current.CreatedAt
+	rule.ModifiedAt = int(now())
+	rule.Enabled = current.Enabled
+	rule.TimeZone = current.TimeZone
+	s.notificationRules.put(id, rule)
+	writeJSON(w, http.StatusOK, rule, "data")
+}
+
+func (s *Server) deleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.notificationRules.remove(id) {
+		notFound(w, r, "notification rule", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) listNotificationRules(w http.ResponseWriter, _ *http.Request) {
+	var resp platformTypes.ListNotificationForwardingConfigurationsResponse
+	resp.Data = s.notificationRules.all()
+	resp.Metadata.TotalCount = len(resp.Data)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) toggleNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	rule, ok := s.notificationRules.get(id)
+	if !ok {
+		notFound(w, r, "notification rule", id)
+		return
+	}
+	var req platformTypes.ToggleNotificationForwardingConfigurationRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	switch req.Status {
+	case "enable":
+		rule.Enabled = true
+	case "disable":
+		rule.Enabled = false
+	default:
+		badRequest(w, r, fmt.Sprintf("invalid status %q", req.Status))
+		return
+	}
+	rule.ModifiedAt = int(now())
+	writeJSON(w, http.StatusOK, *rule, "data")
+}
+
+// syslogFilterAliases maps the fields of the syslog list filters to the keys
+// of syslog integrations.
+var syslogFilterAliases = map[string]string{
+	"id":       "SYSLOG_INTEGRATION_ID",
+	"name":     "SYSLOG_INTEGRATION_NAME",
+	"address":  "SYSLOG_INTEGRATION_ADDRESS",
+	"port":     "SYSLOG_INTEGRATION_PORT",
+	"protocol": "SYSLOG_INTEGRATION_PROTOCOL",
+	"status":   "SYSLOG_INTEGRATION_STATUS",
+	"facility": "FACILITY",
+}
+
+func (s *Server) createSyslogIntegration(w http.ResponseWriter, r *http.Request) {
+	var req platformTypes.CreateSyslogIntegrationRequest
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	if req.Name == "" || req.Address == "" {
+		badRequest(w, r, "name and address are required")
+		return
+	}
+	integration := platformTypes.SyslogIntegration{
+		ID:       s.nextSeq(),
+		Name:     req.Name,
+		Address:  req.Address,
+		Port:     req.Port,
+		Protocol: req.Protocol,
+		Facility: req.Facility,
+		Status:   "ACTIVE",
+	}
+	if req.SecurityInfo != "" {
+		var info platformTypes.SyslogIntegrationSecurityInfo
+		if err := json.Unmarshal([]byte(req.SecurityInfo), &info); err != nil {
+			badRequest(w, r, fmt.Sprintf("invalid security_info: %v", err))
+			return
+		}
+		integration.CertificateName = &info.CertificateName
+	}
+	s.syslogIntegrations.put(strconv.Itoa(integration.ID), integration)
+	writeJSON(w, http.StatusOK, platformTypes.CreateSyslogIntegrationResponse{
+		IntegrationID: integration.ID,
+		Name:          integration.Name,
+	})
+}
+
+func (s *Server) listSyslogIntegrations(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filters []fieldFilter `json:"filters"`
+	}
+	if !decodeRequest(w, r, &req, "request_data") {
+		return
+	}
+	integrations, count, err := run(s.syslogIntegrations.all(), listQuery{
+		match: func(doc map[string]any) (bool, error) {
+			return matchFieldFilters(doc, req.Filters, syslogFilterAliases)
+		},
+	})
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, platformTypes.ListSyslogIntegrationsResponse{
+		Count:        count,
+		Integrations: integrations,
+	})
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it is encoded
+// as an empty JSON array.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
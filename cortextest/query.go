@@ -0,0 +1,372 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// document returns the JSON object form of v, on which filters and sorts
+// are evaluated.
+func document(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var doc map[string]any
+	_ = json.Unmarshal(data, &doc)
+	return doc
+}
+
+// convert converts v to out through its JSON form, e.g. to turn a stored
+// resource into the shape returned by another endpoint.
+func convert(v, out any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("cortextest: failed to marshal %T: %v", v, err))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		panic(fmt.Sprintf("cortextest: failed to convert %T to %T: %v", v, out, err))
+	}
+}
+
+// lookup returns the value of field in doc. Aliases map the field names
+// accepted by an endpoint's filters to the keys of its documents. Keys are
+// matched exactly, then case-insensitively, then as a dot-separated path.
+func lookup(doc map[string]any, field string, aliases map[string]string) (any, bool) {
+	if alias, ok := aliases[field]; ok {
+		field = alias
+	}
+	if v, ok := doc[field]; ok {
+		return v, true
+	}
+	for key, v := range doc {
+		if strings.EqualFold(key, field) {
+			return v, true
+		}
+	}
+	head, rest, ok := strings.Cut(field, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := doc[head].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookup(nested, rest, nil)
+}
+
+// criteria is a filter in the search format of the API. It is either a
+// group of nested criteria, combined with AND or OR, or a condition on a
+// single field.
+type criteria struct {
+	AND         []criteria `json:"AND,omitempty"`
+	OR          []criteria `json:"OR,omitempty"`
+	SearchField string     `json:"SEARCH_FIELD,omitempty"`
+	SearchType  string     `json:"SEARCH_TYPE,omitempty"`
+	SearchValue any        `json:"SEARCH_VALUE,omitempty"`
+}
+
+// match reports whether doc matches the criteria. An empty criteria
+// matches every document.
+func (c *criteria) match(doc map[string]any, aliases map[string]string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	for _, sub := range c.AND {
+		ok, err := sub.match(doc, aliases)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(c.OR) > 0 {
+		matched := false
+		for _, sub := range c.OR {
+			ok, err := sub.match(doc, aliases)
+			if err != nil {
+				return false, err
+			}
+			matched = matched || ok
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if c.SearchField == "" {
+		return true, nil
+	}
+	value, _ := lookup(doc, c.SearchField, aliases)
+	return matchCondition(value, c.SearchType, c.SearchValue)
+}
+
+// fieldFilter is a filter in the {field, operator, value} format of the
+// compliance and syslog endpoints.
+type fieldFilter struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+}
+
+// fieldFilterOperators maps the operators of field filters to search types.
+var fieldFilterOperators = map[string]string{
+	"eq":        "EQ",
+	"neq":       "NEQ",
+	"gt":        "GT",
+	"gte":       "GTE",
+	"lt":        "LT",
+	"lte":       "LTE",
+	"in":        "IN",
+	"nin":       "NIN",
+	"contains":  "CONTAINS",
+	"ncontains": "NCONTAINS",
+}
+
+// matchFieldFilters reports whether doc matches every filter.
+func matchFieldFilters(doc map[string]any, filters []fieldFilter, aliases map[string]string) (bool, error) {
+	for _, f := range filters {
+		searchType, ok := fieldFilterOperators[strings.ToLower(f.Operator)]
+		if !ok {
+			return false, fmt.Errorf("unsupported filter operator %q", f.Operator)
+		}
+		value, _ := lookup(doc, f.Field, aliases)
+		if ok, err := matchCondition(value, searchType, f.Value); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchCondition reports whether a field value satisfies a search condition.
+func matchCondition(value any, searchType string, searchValue any) (bool, error) {
+	switch strings.ToUpper(searchType) {
+	case "EQ":
+		return anyElement(value, func(v any) bool { return equal(v, searchValue) }), nil
+	case "NEQ":
+		return !anyElement(value, func(v any) bool { return equal(v, searchValue) }), nil
+	case "GT":
+		return compareValues(value, searchValue) > 0, nil
+	case "GTE":
+		return compareValues(value, searchValue) >= 0, nil
+	case "LT":
+		return compareValues(value, searchValue) < 0, nil
+	case "LTE":
+		return compareValues(value, searchValue) <= 0, nil
+	case "IN":
+		return anyElement(value, func(v any) bool { return in(v, searchValue) }), nil
+	case "NIN":
+		return !anyElement(value, func(v any) bool { return in(v, searchValue) }), nil
+	case "CONTAINS":
+		return anyElement(value, func(v any) bool { return contains(v, searchValue) }), nil
+	case "NCONTAINS":
+		return !anyElement(value, func(v any) bool { return contains(v, searchValue) }), nil
+	case "ARRAY_CONTAINS":
+		return anyElement(value, func(v any) bool { return in(v, searchValue) }), nil
+	case "ARRAY_NOT_CONTAINS":
+		return !anyElement(value, func(v any) bool { return in(v, searchValue) }), nil
+	case "IS_EMPTY":
+		return isEmpty(value), nil
+	case "NIS_EMPTY", "JSON_IS_NOT_EMPTY":
+		return !isEmpty(value), nil
+	case "WILDCARD", "WILDCARD_NOT":
+		re, err := regexp.Compile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(text(searchValue)), `\*`, ".*") + "$")
+		if err != nil {
+			return false, err
+		}
+		matched := anyElement(value, func(v any) bool { return re.MatchString(text(v)) })
+		return matched == (strings.ToUpper(searchType) == "WILDCARD"), nil
+	case "RLIKE", "REGEX", "REGEX_MATCH", "NRLIKE", "REGEX_NOT", "REGEX_NOT_MATCH":
+		re, err := regexp.Compile(text(searchValue))
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression %q: %w", text(searchValue), err)
+		}
+		matched := anyElement(value, func(v any) bool { return re.MatchString(text(v)) })
+		negated := strings.HasPrefix(strings.ToUpper(searchType), "N") || strings.Contains(strings.ToUpper(searchType), "_NOT")
+		return matched != negated, nil
+	case "RANGE":
+		bounds, ok := searchValue.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("RANGE requires a {\"from\", \"to\"} value")
+		}
+		return compareValues(value, bounds["from"]) >= 0 && compareValues(value, bounds["to"]) <= 0, nil
+	default:
+		return false, fmt.Errorf("unsupported search type %q", searchType)
+	}
+}
+
+// anyElement reports whether pred holds for value or, if value is an
+// array, for any of its elements.
+func anyElement(value any, pred func(any) bool) bool {
+	if values, ok := value.([]any); ok {
+		return slices.ContainsFunc(values, pred)
+	}
+	return pred(value)
+}
+
+// equal reports whether a field value equals a search value. Booleans also
+// equal the strings "yes" and "no", which some endpoints use for them.
+func equal(value, searchValue any) bool {
+	if b, ok := value.(bool); ok {
+		switch strings.ToLower(text(searchValue)) {
+		case "true", "yes":
+			return b
+		case "false", "no":
+			return !b
+		}
+		return false
+	}
+	return text(value) == text(searchValue)
+}
+
+// in reports whether value equals the search value or, if it is an array
+// or a comma-separated string, any of its elements.
+func in(value, searchValue any) bool {
+	switch sv := searchValue.(type) {
+	case []any:
+		return slices.ContainsFunc(sv, func(v any) bool { return equal(value, v) })
+	case string:
+		for _, v := range strings.Split(sv, ",") {
+			if equal(value, strings.TrimSpace(v)) {
+				return true
+			}
+		}
+		return false
+	default:
+		return equal(value, searchValue)
+	}
+}
+
+// contains reports whether the text of value contains the search value,
+// ignoring case.
+func contains(value, searchValue any) bool {
+	return value != nil && strings.Contains(strings.ToLower(text(value)), strings.ToLower(text(searchValue)))
+}
+
+// isEmpty reports whether a field value is missing or empty.
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// text returns the string form of a JSON scalar.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// compareValues orders two JSON scalars: numerically if both are numbers,
+// and by their string form otherwise. Missing values sort first.
+func compareValues(a, b any) int {
+	if a == nil || b == nil {
+		return cmp.Compare(boolInt(a != nil), boolInt(b != nil))
+	}
+	af, aErr := strconv.ParseFloat(text(a), 64)
+	bf, bErr := strconv.ParseFloat(text(b), 64)
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(af, bf)
+	}
+	return cmp.Compare(text(a), text(b))
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sortKey orders a list by a field.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// isDescending reports whether a sort order, e.g. "DESC", "desc" or -1, is
+// descending.
+func isDescending(order string) bool {
+	return strings.EqualFold(order, "desc") || order == "-1"
+}
+
+// listQuery describes the filtering, sorting and pagination of a list.
+type listQuery struct {
+	match   func(doc map[string]any) (bool, error)
+	sort    []sortKey
+	from    int
+	to      int // 0 means the end of the list
+	aliases map[string]string
+}
+
+// run applies q to rows. It returns the requested page and the number of
+// rows matching the filter.
+func run[T any](rows []T, q listQuery) ([]T, int, error) {
+	type entry struct {
+		row T
+		doc map[string]any
+	}
+	var matched []entry
+	for _, row := range rows {
+		doc := document(row)
+		if q.match != nil {
+			ok, err := q.match(doc)
+			if err != nil {
+				return nil, 0, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		matched = append(matched, entry{row, doc})
+	}
+
+	slices.SortStableFunc(matched, func(a, b entry) int {
+		for _, key := range q.sort {
+			av, _ := lookup(a.doc, key.field, q.aliases)
+			bv, _ := lookup(b.doc, key.field, q.aliases)
+			c := compareValues(av, bv)
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	from, to := max(q.from, 0), len(matched)
+	if q.to > 0 {
+		to = min(q.to, len(matched))
+	}
+	page := []T{}
+	for i := from; i < to; i++ {
+		page = append(page, matched[i].row)
+	}
+	return page, len(matched), nil
+}
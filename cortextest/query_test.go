@@ -0,0 +1,126 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCriteriaMatch(t *testing.T) {
+	doc := map[string]any{
+		"name":     "s3-public",
+		"severity": "high",
+		"score":    float64(7),
+		"labels":   []any{"aws", "storage"},
+		"enabled":  true,
+		"metadata": map[string]any{"owner": "secops"},
+	}
+
+	tests := []struct {
+		name     string
+		criteria *criteria
+		aliases  map[string]string
+		want     bool
+	}{
+		{name: "nil matches", criteria: nil, want: true},
+		{name: "eq", criteria: &criteria{SearchField: "severity", SearchType: "EQ", SearchValue: "high"}, want: true},
+		{name: "eq case-insensitive key", criteria: &criteria{SearchField: "SEVERITY", SearchType: "EQ", SearchValue: "high"}, want: true},
+		{name: "neq", criteria: &criteria{SearchField: "severity", SearchType: "NEQ", SearchValue: "high"}, want: false},
+		{name: "gte", criteria: &criteria{SearchField: "score", SearchType: "GTE", SearchValue: 7}, want: true},
+		{name: "lt", criteria: &criteria{SearchField: "score", SearchType: "LT", SearchValue: 7}, want: false},
+		{name: "in list", criteria: &criteria{SearchField: "severity", SearchType: "IN", SearchValue: []any{"low", "high"}}, want: true},
+		{name: "array contains", criteria: &criteria{SearchField: "labels", SearchType: "ARRAY_CONTAINS", SearchValue: "aws"}, want: true},
+		{name: "contains", criteria: &criteria{SearchField: "name", SearchType: "CONTAINS", SearchValue: "PUBLIC"}, want: true},
+		{name: "wildcard", criteria: &criteria{SearchField: "name", SearchType: "WILDCARD", SearchValue: "s3-*"}, want: true},
+		{name: "bool as yes", criteria: &criteria{SearchField: "enabled", SearchType: "EQ", SearchValue: "yes"}, want: true},
+		{name: "dotted path", criteria: &criteria{SearchField: "metadata.owner", SearchType: "EQ", SearchValue: "secops"}, want: true},
+		{name: "alias", criteria: &criteria{SearchField: "level", SearchType: "EQ", SearchValue: "high"}, aliases: map[string]string{"level": "severity"}, want: true},
+		{name: "is empty", criteria: &criteria{SearchField: "missing", SearchType: "IS_EMPTY"}, want: true},
+		{
+			name: "and",
+			criteria: &criteria{AND: []criteria{
+				{SearchField: "severity", SearchType: "EQ", SearchValue: "high"},
+				{SearchField: "score", SearchType: "GT", SearchValue: 9},
+			}},
+			want: false,
+		},
+		{
+			name: "or",
+			criteria: &criteria{OR: []criteria{
+				{SearchField: "severity", SearchType: "EQ", SearchValue: "low"},
+				{SearchField: "score", SearchType: "GT", SearchValue: 5},
+			}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.criteria.match(doc, tt.aliases)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCriteriaMatch_UnsupportedSearchType(t *testing.T) {
+	_, err := (&criteria{SearchField: "name", SearchType: "FUZZY", SearchValue: "x"}).match(map[string]any{}, nil)
+	assert.ErrorContains(t, err, `unsupported search type "FUZZY"`)
+}
+
+func TestMatchFieldFilters(t *testing.T) {
+	doc := map[string]any{"name": "CIS AWS", "insert_ts": float64(100)}
+	aliases := map[string]string{"creation_time": "insert_ts"}
+
+	ok, err := matchFieldFilters(doc, []fieldFilter{
+		{Field: "name", Operator: "contains", Value: "aws"},
+		{Field: "creation_time", Operator: "gte", Value: 100},
+	}, aliases)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = matchFieldFilters(doc, []fieldFilter{{Field: "name", Operator: "neq", Value: "CIS AWS"}}, aliases)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = matchFieldFilters(doc, []fieldFilter{{Field: "name", Operator: "like", Value: "x"}}, aliases)
+	assert.ErrorContains(t, err, `unsupported filter operator "like"`)
+}
+
+func TestRun(t *testing.T) {
+	type row struct {
+		Name  string `json:"name"`
+		Score int    `json:"score"`
+	}
+	rows := []row{{"a", 3}, {"b", 1}, {"c", 2}, {"d", 5}}
+
+	page, count, err := run(rows, listQuery{
+		match: func(doc map[string]any) (bool, error) {
+			return (&criteria{SearchField: "score", SearchType: "GT", SearchValue: 1}).match(doc, nil)
+		},
+		sort: []sortKey{{field: "score", desc: true}},
+		from: 1,
+		to:   3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []row{{"a", 3}, {"c", 2}}, page)
+
+	page, count, err = run(rows, listQuery{from: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Empty(t, page)
+	assert.NotNil(t, page)
+}
+
+func TestIsDescending(t *testing.T) {
+	assert.True(t, isDescending("DESC"))
+	assert.True(t, isDescending("desc"))
+	assert.True(t, isDescending("-1"))
+	assert.False(t, isDescending("ASC"))
+	assert.False(t, isDescending(""))
+}
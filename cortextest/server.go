@@ -0,0 +1,204 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cortextest provides an in-memory fake of the Cortex Cloud public
+// API for unit tests.
+//
+// A Server emulates every endpoint called by the SDK modules. It keeps the
+// resources created through it in memory, wraps requests and responses in
+// the same "request_data" and "reply" envelopes as a tenant, applies the
+// filters, sorting and pagination of the list and search endpoints, and
+// returns errors in the shapes the tenant uses:
+//
+//	srv := cortextest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := cloudsec.NewClient(srv.ClientOptions()...)
+//	rule, err := client.Create(ctx, types.CreateRuleRequest{Name: "my-rule"})
+//
+// Read-only resources, such as users and tenants, are seeded with the Add
+// methods of the Server. Failures are injected with FailNext.
+package cortextest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+)
+
+const (
+	// APIKey is the API key returned by ClientOptions.
+	APIKey = "cortextest-key"
+	// APIKeyID is the API key ID returned by ClientOptions.
+	APIKeyID = 1
+	// Actor is the user recorded as the creator and modifier of the
+	// resources created through the Server.
+	Actor = "cortextest@example.com"
+)
+
+// Server is a fake Cortex Cloud tenant. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	failures []failure
+	state
+}
+
+// failure is an error response injected with FailNext.
+type failure struct {
+	method     string
+	path       string
+	statusCode int
+	remaining  int
+}
+
+// NewServer starts and returns a new Server with an empty tenant. The caller
+// must call Close when done.
+func NewServer() *Server {
+	s := &Server{mux: http.NewServeMux()}
+	s.state = newState()
+	s.registerPlatform()
+	s.registerCloudSec()
+	s.registerAppSec()
+	s.registerCWP()
+	s.registerVulnerability()
+	s.registerCompliance()
+	s.registerCloudOnboarding()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// ClientOptions returns the options that configure a module client to send
+// its requests to the Server, e.g.:
+//
+//	client, err := cloudsec.NewClient(srv.ClientOptions()...)
+//
+// Retries are disabled, so that injected failures are returned immediately;
+// options passed after these override them.
+func (s *Server) ClientOptions() []config.Option {
+	return []config.Option{
+		config.WithCortexAPIURL(s.URL),
+		config.WithCortexAPIKey(APIKey),
+		config.WithCortexAPIKeyID(APIKeyID),
+		config.WithCortexAPIKeyType("standard"),
+		config.WithTransport(s.Client().Transport),
+		config.WithMaxRetries(0),
+		config.WithSkipLoggingTransport(true),
+	}
+}
+
+// FailNext makes the next count requests to the endpoint fail with the given
+// status code, in the error shape of the endpoint. The endpoint is a path
+// relative to the API URL, e.g. "public_api/v1/rule/search"; requests whose
+// path starts with it fail, whatever their path parameters. An empty method
+// matches every method.
+func (s *Server) FailNext(method, endpoint string, statusCode, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{
+		method:     method,
+		path:       "/" + strings.Trim(endpoint, "/"),
+		statusCode: statusCode,
+		remaining:  count,
+	})
+}
+
+// injectedFailure returns the status code of the failure injected for r, if
+// any, and consumes it.
+func (s *Server) injectedFailure(r *http.Request) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.failures {
+		f := &s.failures[i]
+		if f.remaining <= 0 || (f.method != "" && f.method != r.Method) || !strings.HasPrefix(r.URL.Path, f.path) {
+			continue
+		}
+		f.remaining--
+		return f.statusCode, true
+	}
+	return 0, false
+}
+
+// serveHTTP authenticates r and dispatches it to the handler of its endpoint.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	style := errorStyleFor(r.URL.Path)
+	if r.Header.Get("Authorization") == "" || r.Header.Get("x-xdr-auth-id") == "" {
+		writeError(w, style, http.StatusUnauthorized, "missing API key or API key ID")
+		return
+	}
+	if statusCode, ok := s.injectedFailure(r); ok {
+		writeError(w, style, statusCode, http.StatusText(statusCode))
+		return
+	}
+	if _, pattern := s.mux.Handler(r); pattern == "" {
+		writeError(w, style, http.StatusNotFound, fmt.Sprintf("no endpoint %s %s", r.Method, r.URL.Path))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle registers the handler of an endpoint. The pattern is an
+// http.ServeMux pattern without the leading slash, e.g.
+// "GET public_api/v1/rule/{id}".
+func (s *Server) handle(pattern string, handler func(w http.ResponseWriter, r *http.Request)) {
+	method, path, _ := strings.Cut(pattern, " ")
+	s.mux.HandleFunc(method+" /"+path, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		handler(w, r)
+	})
+}
+
+// decodeRequest decodes the JSON body of r into v, after unwrapping the
+// given wrapper keys, e.g. "request_data". It writes a 400 response and
+// returns false if the body is invalid.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any, wrapperKeys ...string) bool {
+	style := errorStyleFor(r.URL.Path)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, style, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return false
+	}
+	for _, key := range wrapperKeys {
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			writeError(w, style, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return false
+		}
+		inner, ok := wrapper[key]
+		if !ok {
+			writeError(w, style, http.StatusBadRequest, fmt.Sprintf("request body is missing the %q key", key))
+			return false
+		}
+		body = inner
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, style, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the JSON body of a response, wrapped in the given
+// wrapper keys, e.g. "reply".
+func writeJSON(w http.ResponseWriter, statusCode int, v any, wrapperKeys ...string) {
+	for i := len(wrapperKeys) - 1; i >= 0; i-- {
+		v = map[string]any{wrapperKeys[i]: v}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
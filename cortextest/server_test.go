@@ -0,0 +1,189 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cloudsec"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/compliance"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/platform"
+	cloudsecTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/cloudsec"
+	complianceTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/compliance"
+	platformTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/platform"
+)
+
+func newCloudSecClient(t *testing.T) (*Server, *cloudsec.Client) {
+	t.Helper()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+	client, err := cloudsec.NewClient(srv.ClientOptions()...)
+	require.NoError(t, err)
+	return srv, client
+}
+
+func createRule(t *testing.T, client *cloudsec.Client, name, severity string) cloudsecTypes.RuleResponse {
+	t.Helper()
+	rule, err := client.Create(context.Background(), cloudsecTypes.CreateRuleRequest{
+		Name:       name,
+		Class:      "config",
+		AssetTypes: []string{"aws-s3-bucket"},
+		Severity:   severity,
+		Query:      cloudsecTypes.QueryRequest{XQL: "config from cloud.resource"},
+	})
+	require.NoError(t, err)
+	return rule
+}
+
+func TestServer_CloudSecRuleLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, client := newCloudSecClient(t)
+
+	created := createRule(t, client, "public-bucket", "high")
+	assert.NotEmpty(t, created.ID)
+	assert.True(t, created.Enabled)
+	assert.Equal(t, Actor, created.CreatedBy)
+
+	got, err := client.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	updated, err := client.Update(ctx, created.ID, cloudsecTypes.UpdateRuleRequest{Description: "updated"})
+	require.NoError(t, err)
+	assert.Equal(t, "updated", updated.Description)
+	assert.Equal(t, "public-bucket", updated.Name)
+
+	require.NoError(t, client.Delete(ctx, created.ID))
+	_, err = client.Get(ctx, created.ID)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestServer_CloudSecRuleSearch(t *testing.T) {
+	ctx := context.Background()
+	_, client := newCloudSecClient(t)
+	createRule(t, client, "rule-a", "high")
+	createRule(t, client, "rule-b", "low")
+	createRule(t, client, "rule-c", "high")
+
+	resp, err := client.Search(ctx, cloudsecTypes.SearchRulesRequest{
+		Filter:   &cloudsecTypes.FilterCriteria{SearchField: "severity", SearchType: "EQ", SearchValue: "high"},
+		Sort:     []cloudsecTypes.SortCriteria{{Field: "name", Order: "DESC"}},
+		SearchTo: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "rule-c", resp.Data[0].Name)
+	assert.EqualValues(t, 2, resp.Metadata.FilterCount)
+	assert.EqualValues(t, 3, resp.Metadata.TotalCount)
+}
+
+func TestServer_Conflict(t *testing.T) {
+	_, client := newCloudSecClient(t)
+	createRule(t, client, "duplicate", "high")
+
+	_, err := client.Create(context.Background(), cloudsecTypes.CreateRuleRequest{
+		Name:       "duplicate",
+		Class:      "config",
+		AssetTypes: []string{"aws-s3-bucket"},
+		Severity:   "high",
+	})
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestServer_FailNext(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newCloudSecClient(t)
+
+	srv.FailNext(http.MethodPost, "public_api/v1/rule/search", http.StatusServiceUnavailable, 1)
+
+	_, err := client.Search(ctx, cloudsecTypes.SearchRulesRequest{})
+	assert.Error(t, err)
+
+	_, err = client.Search(ctx, cloudsecTypes.SearchRulesRequest{})
+	assert.NoError(t, err)
+}
+
+func TestServer_RequiresAuthentication(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	resp, err := srv.Client().Post(srv.URL+"/public_api/v1/rule/search", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_UnknownEndpoint(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/public_api/v1/unknown", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", APIKey)
+	req.Header.Set("x-xdr-auth-id", "1")
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_PlatformRoles(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer()
+	defer srv.Close()
+	client, err := platform.NewClient(srv.ClientOptions()...)
+	require.NoError(t, err)
+
+	created, err := client.CreateRole(ctx, platformTypes.RoleCreateRequest{
+		RequestData: platformTypes.RoleCreateRequestData{PrettyName: "Auditor"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.RoleID)
+
+	roles, err := client.ListAllRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles.Data, 1)
+	assert.Equal(t, "Auditor", roles.Data[0].PrettyName)
+
+	require.NoError(t, client.DeleteRole(ctx, created.RoleID))
+	roles, err = client.ListAllRoles(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, roles.Data)
+}
+
+func TestServer_ComplianceStandards(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer()
+	defer srv.Close()
+	client, err := compliance.NewClient(srv.ClientOptions()...)
+	require.NoError(t, err)
+
+	ok, err := client.CreateStandard(ctx, complianceTypes.CreateStandardRequest{StandardName: "Internal", Labels: []string{"custom"}})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	list, err := client.ListStandards(ctx, complianceTypes.ListStandardsRequest{
+		Filters: []complianceTypes.Filter{{Field: "name", Operator: "eq", Value: "Internal"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Standards, 1)
+	assert.Equal(t, 1, list.TotalCount)
+
+	id := list.Standards[0].ID
+	standard, err := client.GetStandard(ctx, complianceTypes.GetStandardRequest{ID: id})
+	require.NoError(t, err)
+	assert.Equal(t, "Internal", standard.Name)
+
+	ok, err = client.DeleteStandard(ctx, complianceTypes.DeleteStandardRequest{ID: id})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = client.GetStandard(ctx, complianceTypes.GetStandardRequest{ID: id})
+	assert.Error(t, err)
+}
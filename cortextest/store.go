@@ -0,0 +1,102 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// state is the in-memory content of the tenant. It is guarded by Server.mu.
+type state struct {
+	seq int // last generated ID
+
+	platformState
+	cloudSecState
+	appSecState
+	cwpState
+	vulnerabilityState
+	complianceState
+	cloudOnboardingState
+}
+
+func newState() state {
+	return state{
+		platformState:        newPlatformState(),
+		cloudSecState:        newCloudSecState(),
+		appSecState:          newAppSecState(),
+		cwpState:             newCWPState(),
+		vulnerabilityState:   newVulnerabilityState(),
+		complianceState:      newComplianceState(),
+		cloudOnboardingState: newCloudOnboardingState(),
+	}
+}
+
+// nextSeq returns a new sequence number, unique across the tenant.
+func (s *state) nextSeq() int {
+	s.seq++
+	return s.seq
+}
+
+// newUUID returns a new ID in the UUID format used by most resources. IDs
+// are deterministic, so that tests can rely on them.
+func (s *state) newUUID() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextSeq())
+}
+
+// now returns the current time in Unix milliseconds, the timestamp format
+// of the API.
+func now() int64 {
+	return time.Now().UnixMilli()
+}
+
+// table is a collection of resources indexed by ID, listed in insertion
+// order.
+type table[T any] struct {
+	ids  []string
+	rows map[string]*T
+}
+
+func newTable[T any]() *table[T] {
+	return &table[T]{rows: make(map[string]*T)}
+}
+
+// put inserts row, or replaces the row with the same ID.
+func (t *table[T]) put(id string, row T) {
+	if _, ok := t.rows[id]; !ok {
+		t.ids = append(t.ids, id)
+	}
+	t.rows[id] = &row
+}
+
+// get returns the row with the given ID. The row may be modified in place.
+func (t *table[T]) get(id string) (*T, bool) {
+	row, ok := t.rows[id]
+	return row, ok
+}
+
+// remove deletes the row with the given ID, reporting whether it existed.
+func (t *table[T]) remove(id string) bool {
+	if _, ok := t.rows[id]; !ok {
+		return false
+	}
+	delete(t.rows, id)
+	t.ids = slices.DeleteFunc(t.ids, func(v string) bool { return v == id })
+	return true
+}
+
+// all returns a copy of the rows, in insertion order.
+func (t *table[T]) all() []T {
+	rows := make([]T, 0, len(t.ids))
+	for _, id := range t.ids {
+		rows = append(rows, *t.rows[id])
+	}
+	return rows
+}
+
+// len returns the number of rows.
+func (t *table[T]) len() int {
+	return len(t.ids)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortextest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	vulnerabilityTypes "github.com/PaloAltoNetworks/cortex-cloud-go/types/vulnerability"
+)
+
+// vulnerabilityState holds the resources of the vulnerability module.
+type vulnerabilityState struct {
+	vulnerabilityPolicies *table[vulnerabilityTypes.VulnerabilityManagementPolicy]
+}
+
+func newVulnerabilityState() vulnerabilityState {
+	return vulnerabilityState{
+		vulnerabilityPolicies: newTable[vulnerabilityTypes.VulnerabilityManagementPolicy](),
+	}
+}
+
+func (s *Server) registerVulnerability() {
+	s.handle("POST public_api/uvm_public/v1/create_policy", s.createVulnerabilityPolicy)
+	s.handle("GET public_api/uvm_public/v1/get_policy/{id}", s.getVulnerabilityPolicy)
+	s.handle("PUT public_api/uvm_public/v1/update_policy/{id}", s.updateVulnerabilityPolicy)
+	s.handle("DELETE public_api/uvm_public/v1/delete_policy/{id}", s.deleteVulnerabilityPolicy)
+	s.handle("POST public_api/uvm_public/v1/list_policies", s.listVulnerabilityPolicies)
+}
+
+// filterData is the filter, sort and paging of the list endpoints that take
+// a "filter_data" object.
+type filterData struct {
+	Filter *criteria   `json:"filter"`
+	Sort   []sortSpec  `json:"sort"`
+	Paging pagingRange `json:"paging"`
+}
+
+// pagingRange is the half-open range of a page of results.
+type pagingRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// query returns the list query of the filter data.
+func (f filterData) query(aliases map[string]string) listQuery {
+	return listQuery{
+		match:   func(doc map[string]any) (bool, error) { return f.Filter.match(doc, aliases) },
+		sort:    sortKeys(f.Sort),
+		from:    f.Paging.From,
+		to:      f.Paging.To,
+		aliases: aliases,
+	}
+}
+
+func (s *Server) createVulnerabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	var req vulnerabilityTypes.CreateVulnerabilityManagementPolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" || req.MatchCriteria == nil || req.ActionCategory == "" || req.PolicyType == "" {
+		badRequest(w, r, "name, match_criteria, action_category and policy_type are required")
+		return
+	}
+	for _, policy := range s.vulnerabilityPolicies.all() {
+		if policy.NAME == req.Name {
+			conflict(w, r, "policy with name "+req.Name+" already exists")
+			return
+		}
+	}
+	var policy vulnerabilityTypes.VulnerabilityManagementPolicy
+	policy.ID = s.newUUID()
+	applyVulnerabilityPolicy(&policy, vulnerabilityTypes.UpdateVulnerabilityManagementPolicyRequest(req))
+	s.vulnerabilityPolicies.put(policy.ID, policy)
+	writeJSON(w, http.StatusOK, vulnerabilityTypes.CreateVulnerabilityManagementPolicyResponse{ID: policy.ID})
+}
+
+// applyVulnerabilityPolicy sets the fields of policy from req.
+func applyVulnerabilityPolicy(policy *vulnerabilityTypes.VulnerabilityManagementPolicy, req vulnerabilityTypes.UpdateVulnerabilityManagementPolicyRequest) {
+	policy.NAME = req.Name
+	policy.DESCRIPTION = req.Description
+	policy.PRIORITY = req.Priority
+	policy.STATUS = req.Status
+	if policy.STATUS == "" {
+		policy.STATUS = "ENABLED"
+	}
+	policy.MATCH_CRITERIA = req.MatchCriteria
+	policy.EXCLUSIONS = req.ExclusionCriteria
+	policy.ACTION = nonNil(req.Action)
+	policy.ACTION_CATEGORY = req.ActionCategory
+	policy.SEVERITY = req.Severity
+	policy.ASSET_GROUP_SCOPE = req.AssetGroupScope
+	policy.POLICY_TYPE = req.PolicyType
+	policy.MODIFIED_BY = Actor
+	policy.MODIFIED_TIMESTAMP = time.Now().UTC().Format(time.RFC3339)
+}
+
+func (s *Server) getVulnerabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.vulnerabilityPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, *policy)
+}
+
+func (s *Server) updateVulnerabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	policy, ok := s.vulnerabilityPolicies.get(id)
+	if !ok {
+		notFound(w, r, "policy", id)
+		return
+	}
+	var req vulnerabilityTypes.UpdateVulnerabilityManagementPolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Name == "" || req.MatchCriteria == nil || req.ActionCategory == "" || req.PolicyType == "" {
+		badRequest(w, r, "name, match_criteria, action_category and policy_type are required")
+		return
+	}
+	applyVulnerabilityPolicy(policy, req)
+	writeJSON(w, http.StatusOK, vulnerabilityTypes.UpdateVulnerabilityManagementPolicyResponse{ID: id})
+}
+
+func (s *Server) deleteVulnerabilityPolicy(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.vulnerabilityPolicies.remove(id) {
+		notFound(w, r, "policy", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, true)
+}
+
+func (s *Server) listVulnerabilityPolicies(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FilterData filterData `json:"filter_data"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	policies, count, err := run(s.vulnerabilityPolicies.all(), req.FilterData.query(nil))
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, vulnerabilityTypes.ListVulnerabilityManagementPoliciesResponse{
+		DATA:         policies,
+		FILTER_COUNT: count,
+		TOTAL_COUNT:  s.vulnerabilityPolicies.len(),
+	})
+}
+
+// rawJSON returns the JSON encoding of v as a string, the format of the
+// fields that some endpoints return as embedded JSON documents.
+func rawJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
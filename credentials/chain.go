@@ -0,0 +1,69 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Chain is a Provider that returns the credentials of the first of its
+// providers that has some. Providers failing with an error matching
+// ErrNotFound are skipped; any other error is returned, so that a broken
+// source is reported rather than silently replaced by the next one.
+type Chain struct {
+	providers []Provider
+
+	mu      sync.Mutex
+	current Provider
+}
+
+// NewChain returns a Chain trying the given providers in order, e.g.:
+//
+//	credentials.NewChain(credentials.Env{}, credentials.NewFile(path))
+func NewChain(providers ...Provider) *Chain {
+	return &Chain{providers: providers}
+}
+
+// Retrieve returns the credentials of the provider that supplied the last
+// ones, or of the first provider that has some.
+func (c *Chain) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	current := c.current
+	c.mu.Unlock()
+	if current != nil {
+		creds, err := current.Retrieve(ctx)
+		if !errors.Is(err, ErrNotFound) {
+			return creds, err
+		}
+	}
+
+	for _, p := range c.providers {
+		creds, err := p.Retrieve(ctx)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return Credentials{}, err
+		}
+		c.mu.Lock()
+		c.current = p
+		c.mu.Unlock()
+		return creds, nil
+	}
+	return Credentials{}, fmt.Errorf("%w in any of %d providers", ErrNotFound, len(c.providers))
+}
+
+// Expire expires the credentials of every provider, and makes the next call
+// to Retrieve try them in order again.
+func (c *Chain) Expire() {
+	c.mu.Lock()
+	c.current = nil
+	c.mu.Unlock()
+	for _, p := range c.providers {
+		p.Expire()
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider returns a fixed result and counts its calls.
+type stubProvider struct {
+	creds     Credentials
+	err       error
+	retrieved int
+	expired   int
+}
+
+func (p *stubProvider) Retrieve(context.Context) (Credentials, error) {
+	p.retrieved++
+	return p.creds, p.err
+}
+
+func (p *stubProvider) Expire() { p.expired++ }
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should skip providers without credentials", func(t *testing.T) {
+		missing := &stubProvider{err: ErrNotFound}
+		found := &stubProvider{creds: Credentials{APIKey: "key", APIKeyID: 1}}
+		chain := NewChain(missing, found)
+
+		creds, err := chain.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "key", creds.APIKey)
+
+		// The provider that supplied the credentials is asked first
+		_, err = chain.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, missing.retrieved)
+		assert.Equal(t, 2, found.retrieved)
+
+		chain.Expire()
+		assert.Equal(t, 1, missing.expired)
+		assert.Equal(t, 1, found.expired)
+		_, err = chain.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, missing.retrieved)
+	})
+
+	t.Run("should return the errors of broken providers", func(t *testing.T) {
+		broken := &stubProvider{err: errors.New("permission denied")}
+		chain := NewChain(broken, Static{APIKey: "key", APIKeyID: 1})
+
+		_, err := chain.Retrieve(ctx)
+		assert.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("should fail if no provider has credentials", func(t *testing.T) {
+		chain := NewChain(&stubProvider{err: ErrNotFound})
+
+		_, err := chain.Retrieve(ctx)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
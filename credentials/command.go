@@ -0,0 +1,80 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// commandExpiryWindow is how long before their expiration the credentials
+// of a Command are loaded again, so that requests in flight do not race
+// the expiration.
+const commandExpiryWindow = time.Minute
+
+// Command is a Provider that runs an external command, such as a secret
+// manager CLI, and reads the credentials from its standard output as JSON:
+//
+//	{"api_key": "...", "api_key_id": 42, "expiration": "2025-01-02T15:04:05Z"}
+//
+// The credentials are cached until shortly before their expiration, or
+// until Expire is called if they have none.
+type Command struct {
+	name string
+	args []string
+
+	mu    sync.Mutex
+	creds *Credentials
+}
+
+// NewCommand returns a Command provider running the named program with the
+// given arguments.
+func NewCommand(name string, args ...string) *Command {
+	return &Command{name: name, args: args}
+}
+
+// Retrieve returns the cached credentials, running the command if there are
+// none or they are about to expire.
+func (c *Command) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.creds != nil && !c.creds.expiresWithin(commandExpiryWindow) {
+		return *c.creds, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Credentials{}, fmt.Errorf("credentials command %s failed: %w: %s", c.name, err, msg)
+		}
+		return Credentials{}, fmt.Errorf("credentials command %s failed: %w", c.name, err)
+	}
+	var creds Credentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse the output of credentials command %s: %w", c.name, err)
+	}
+	if err := creds.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("invalid output of credentials command %s: %w", c.name, err)
+	}
+	if creds.Expired() {
+		return Credentials{}, fmt.Errorf("credentials command %s: %w", c.name, ErrExpired)
+	}
+	c.creds = &creds
+	return creds, nil
+}
+
+// Expire makes the next call to Retrieve run the command again.
+func (c *Command) Expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creds = nil
+}
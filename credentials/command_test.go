@@ -0,0 +1,86 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCommand returns a Command printing output and appending a line to
+// a file on every run, and a function returning the number of runs.
+func countingCommand(t *testing.T, output string) (*Command, func() int) {
+	t.Helper()
+	runs := filepath.Join(t.TempDir(), "runs")
+	provider := NewCommand("sh", "-c", fmt.Sprintf("echo run >> %s; printf '%%s' '%s'", runs, output))
+	return provider, func() int {
+		data, err := os.ReadFile(runs)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		require.NoError(t, err)
+		return strings.Count(string(data), "run")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should cache credentials without expiration", func(t *testing.T) {
+		provider, runs := countingCommand(t, `{"api_key": "key", "api_key_id": 3}`)
+
+		for range 2 {
+			creds, err := provider.Retrieve(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, Credentials{APIKey: "key", APIKeyID: 3}, creds)
+		}
+		assert.Equal(t, 1, runs())
+
+		provider.Expire()
+		_, err := provider.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, runs())
+	})
+
+	t.Run("should run again when the credentials are about to expire", func(t *testing.T) {
+		expiration := time.Now().Add(30 * time.Second).UTC().Format(time.RFC3339)
+		provider, runs := countingCommand(t, `{"api_key": "key", "api_key_id": 3, "expiration": "`+expiration+`"}`)
+
+		for range 2 {
+			creds, err := provider.Retrieve(ctx)
+			require.NoError(t, err)
+			assert.False(t, creds.Expired())
+		}
+		assert.Equal(t, 2, runs())
+	})
+
+	t.Run("should reject expired credentials", func(t *testing.T) {
+		provider, _ := countingCommand(t, `{"api_key": "key", "api_key_id": 3, "expiration": "2000-01-01T00:00:00Z"}`)
+
+		_, err := provider.Retrieve(ctx)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("should report the error output of the command", func(t *testing.T) {
+		provider := NewCommand("sh", "-c", "echo 'vault is sealed' >&2; exit 2")
+
+		_, err := provider.Retrieve(ctx)
+		assert.ErrorContains(t, err, "vault is sealed")
+	})
+
+	t.Run("should reject invalid output", func(t *testing.T) {
+		provider, _ := countingCommand(t, `not json`)
+
+		_, err := provider.Retrieve(ctx)
+		assert.ErrorContains(t, err, "failed to parse the output")
+	})
+}
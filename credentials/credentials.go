@@ -0,0 +1,87 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package credentials provides the sources of the API key used by SDK
+// clients to authenticate their requests.
+//
+// A client configured with a Provider asks it for credentials before every
+// request attempt instead of using a static API key, so that a rotated key
+// is picked up without rebuilding the client. When the tenant rejects the
+// credentials with HTTP 401, the client expires them and retries the
+// request once with freshly loaded credentials.
+package credentials
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrExpired is matched by errors.Is when a provider only has credentials
+// that have already expired.
+var ErrExpired = errors.New("credentials have expired")
+
+// ErrNotFound is matched by errors.Is when a provider has no credentials to
+// offer, e.g. because its environment variables are not set. A Chain moves
+// on to its next provider.
+var ErrNotFound = errors.New("credentials not found")
+
+// Credentials identify the API key used to sign requests.
+type Credentials struct {
+	// APIKey is the secret API key.
+	APIKey string `json:"api_key"`
+	// APIKeyID is the ID of the API key.
+	APIKeyID int `json:"api_key_id"`
+	// APIKeyType is "standard" or "advanced". If empty, the key type of the
+	// client configuration is used.
+	APIKeyType string `json:"api_key_type,omitempty"`
+	// Expires is when the credentials expire. The zero value means that
+	// they do not expire.
+	Expires time.Time `json:"expiration,omitzero"`
+}
+
+// Expired reports whether the credentials have expired.
+func (c Credentials) Expired() bool {
+	return c.expiresWithin(0)
+}
+
+// expiresWithin reports whether the credentials expire within d from now.
+func (c Credentials) expiresWithin(d time.Duration) bool {
+	return !c.Expires.IsZero() && !time.Now().Add(d).Before(c.Expires)
+}
+
+// validate returns an error if the credentials cannot sign requests.
+func (c Credentials) validate() error {
+	switch {
+	case c.APIKey == "":
+		return errors.New("API key not set")
+	case c.APIKeyID <= 0:
+		return errors.New("API key ID not set")
+	default:
+		return nil
+	}
+}
+
+// Provider supplies the credentials of a client. Implementations must be
+// safe for concurrent use.
+type Provider interface {
+	// Retrieve returns the current credentials. It is called before every
+	// request attempt, so implementations should cache credentials that
+	// are expensive to load.
+	Retrieve(ctx context.Context) (Credentials, error)
+	// Expire discards any cached credentials, so that the next call to
+	// Retrieve loads them again. The client calls it when the tenant
+	// rejects the credentials.
+	Expire()
+}
+
+// Static is a Provider that always returns the same credentials.
+type Static Credentials
+
+// Retrieve returns the credentials.
+func (s Static) Retrieve(context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// Expire does nothing, as static credentials cannot be reloaded.
+func (Static) Expire() {}
@@ -0,0 +1,46 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by Env, the same as those read by the client
+// configuration.
+const (
+	EnvAPIKey     = "CORTEXCLOUD_API_KEY"
+	EnvAPIKeyID   = "CORTEXCLOUD_API_KEY_ID"
+	EnvAPIKeyType = "CORTEXCLOUD_API_KEY_TYPE"
+)
+
+// Env is a Provider that reads the credentials from the environment on every
+// call, so that changes to the environment of the process are picked up.
+type Env struct{}
+
+// Retrieve returns the credentials set in the EnvAPIKey, EnvAPIKeyID and
+// EnvAPIKeyType environment variables. It returns an error matching
+// ErrNotFound if the key or the key ID is not set.
+func (Env) Retrieve(context.Context) (Credentials, error) {
+	key, keyOK := os.LookupEnv(EnvAPIKey)
+	keyID, keyIDOK := os.LookupEnv(EnvAPIKeyID)
+	if !keyOK || !keyIDOK {
+		return Credentials{}, fmt.Errorf("%w: %s and %s must be set", ErrNotFound, EnvAPIKey, EnvAPIKeyID)
+	}
+	id, err := strconv.Atoi(keyID)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("invalid value for %s: %q: expected integer", EnvAPIKeyID, keyID)
+	}
+	return Credentials{
+		APIKey:     key,
+		APIKeyID:   id,
+		APIKeyType: os.Getenv(EnvAPIKeyType),
+	}, nil
+}
+
+// Expire does nothing, as the environment is read on every call.
+func (Env) Expire() {}
@@ -0,0 +1,27 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvAPIKeyID, "12")
+	t.Setenv(EnvAPIKeyType, "standard")
+	creds, err := Env{}.Retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{APIKey: "env-key", APIKeyID: 12, APIKeyType: "standard"}, creds)
+
+	t.Setenv(EnvAPIKeyID, "twelve")
+	_, err = Env{}.Retrieve(ctx)
+	assert.ErrorContains(t, err, "expected integer")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// File is a Provider that reads the credentials from a JSON file, e.g. one
+// mounted from a secret store:
+//
+//	{"api_key": "...", "api_key_id": 42, "api_key_type": "advanced"}
+//
+// The file is read again whenever its modification time or size changes,
+// so a rotated key is picked up by the next request.
+type File struct {
+	path string
+
+	mu      sync.Mutex
+	creds   Credentials
+	modTime time.Time
+	size    int64
+	loaded  bool
+}
+
+// NewFile returns a File provider reading the file at path.
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Retrieve returns the credentials in the file, reading it again if it has
+// changed since the last call. It returns an error matching ErrNotFound if
+// the file does not exist.
+func (f *File) Retrieve(context.Context) (Credentials, error) {
+	info, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		return Credentials{}, fmt.Errorf("%w: no credentials file %s", ErrNotFound, f.path)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.creds, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse credentials file %s: %w", f.path, err)
+	}
+	if err := creds.validate(); err != nil {
+		return Credentials{}, fmt.Errorf("invalid credentials file %s: %w", f.path, err)
+	}
+	f.creds, f.modTime, f.size, f.loaded = creds, info.ModTime(), info.Size(), true
+	return creds, nil
+}
+
+// Expire makes the next call to Retrieve read the file again.
+func (f *File) Expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loaded = false
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCredentialsFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	provider := NewFile(path)
+
+	_, err := provider.Retrieve(ctx)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	start := time.Now().Add(-time.Hour)
+	writeCredentialsFile(t, path, `{"api_key": "first", "api_key_id": 1, "api_key_type": "standard"}`, start)
+	creds, err := provider.Retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{APIKey: "first", APIKeyID: 1, APIKeyType: "standard"}, creds)
+
+	// A rotated key is picked up as soon as the file changes
+	writeCredentialsFile(t, path, `{"api_key": "second", "api_key_id": 2}`, start.Add(time.Minute))
+	creds, err = provider.Retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{APIKey: "second", APIKeyID: 2}, creds)
+
+	writeCredentialsFile(t, path, `{"api_key": ""}`, start.Add(2*time.Minute))
+	_, err = provider.Retrieve(ctx)
+	assert.ErrorContains(t, err, "API key not set")
+}
+
+func TestFile_Expire(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	provider := NewFile(path)
+	modTime := time.Now().Add(-time.Hour)
+
+	writeCredentialsFile(t, path, `{"api_key": "aaaa", "api_key_id": 1}`, modTime)
+	_, err := provider.Retrieve(ctx)
+	require.NoError(t, err)
+
+	// Same size and modification time: only an explicit expiry reloads it
+	writeCredentialsFile(t, path, `{"api_key": "bbbb", "api_key_id": 1}`, modTime)
+	creds, err := provider.Retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa", creds.APIKey)
+
+	provider.Expire()
+	creds, err = provider.Retrieve(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "bbbb", creds.APIKey)
+}
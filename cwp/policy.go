@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
 	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/cwp"
 	convert "github.com/PaloAltoNetworks/cortex-cloud-go/types/util"
 )
@@ -30,7 +31,7 @@ import (
 // For non-compliance policies, RulesIDs and Condition are also required.
 func (c *Client) CreatePolicy(ctx context.Context, input types.CreateOrUpdatePolicyRequest) (*types.CreateOrUpdatePolicyResponse, error) {
 	var resp types.CreateOrUpdatePolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, PoliciesV2Endpoint, nil, nil, input, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, PoliciesV2Endpoint, nil, nil, input, &resp, &client.DoOptions{Operation: "CreatePolicy"})
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +41,7 @@ func (c *Client) CreatePolicy(ctx context.Context, input types.CreateOrUpdatePol
 // GetPolicyByID retrieves a specific CWP policy by ID.
 func (c *Client) GetPolicyByID(ctx context.Context, policyId string) (*types.Policy, error) {
 	var resp types.Policy
-	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesV2Endpoint, &[]string{policyId}, nil, nil, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesV2Endpoint, &[]string{policyId}, nil, nil, &resp, &client.DoOptions{Operation: "GetPolicyByID"})
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +60,7 @@ func (c *Client) ListPolicies(ctx context.Context, policyTypes []string) ([]type
 	}
 
 	var resp []types.Policy
-	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesV2Endpoint, nil, queryParams, nil, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, PoliciesV2Endpoint, nil, queryParams, nil, &resp, &client.DoOptions{Operation: "ListPolicies"})
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +72,7 @@ func (c *Client) ListPolicies(ctx context.Context, policyTypes []string) ([]type
 // The request should contain the full policy object with all required fields.
 // This is a full replacement operation.
 func (c *Client) UpdatePolicy(ctx context.Context, policyID string, input types.CreateOrUpdatePolicyRequest) error {
-	_, err := c.internalClient.Do(ctx, http.MethodPut, PoliciesV2Endpoint, &[]string{policyID}, nil, input, nil, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPut, PoliciesV2Endpoint, &[]string{policyID}, nil, input, nil, &client.DoOptions{Operation: "UpdatePolicy"})
 	return err
 }
 
@@ -80,7 +81,7 @@ func (c *Client) UpdatePolicy(ctx context.Context, policyID string, input types.
 // If closeIssues is true, all issues opened by this policy will be closed.
 func (c *Client) DeletePolicy(ctx context.Context, policyID string, closeIssues bool) error {
 	queryVals := convert.StringToQuery("closeIssues", strconv.FormatBool(closeIssues))
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, PoliciesV1Endpoint, &[]string{policyID}, &queryVals, nil, nil, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, PoliciesV1Endpoint, &[]string{policyID}, &queryVals, nil, nil, &client.DoOptions{Operation: "DeletePolicy"})
 	return err
 }
 
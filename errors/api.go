@@ -74,6 +74,9 @@ type CortexCloudAPIError struct {
 	ErrCode  *int                         `json:"err_code,omitempty"`
 	ErrMsg   *string                      `json:"err_msg,omitempty"`
 	Metadata *CortexCloudAPIErrorMetadata `json:"metadata,omitempty"`
+	// HTTPStatus is the HTTP status of the response the error was parsed
+	// from, or 0 if it is not known.
+	HTTPStatus int `json:"-"`
 }
 
 type CortexCloudAPIErrorReply struct {
@@ -271,6 +274,90 @@ func (e CortexCloudAPIError) HasContent() bool {
 		e.Metadata != nil
 }
 
+// ServerCode returns the error code returned by the API, whatever the format
+// of its error response, or an empty string if it returned none.
+func (e CortexCloudAPIError) ServerCode() string {
+	switch {
+	case e.Reply != nil:
+		return strconv.Itoa(e.Reply.Code)
+	case e.Data != nil && e.Data.Metadata != nil:
+		return strconv.Itoa(e.Data.Metadata.Code)
+	case e.ErrCode != nil && *e.ErrCode != 0:
+		return strconv.Itoa(*e.ErrCode)
+	case e.Metadata != nil && e.Metadata.Code != 0:
+		return strconv.Itoa(e.Metadata.Code)
+	case e.Code != nil:
+		return *e.Code
+	}
+	return ""
+}
+
+// ServerMessage returns the error message returned by the API, whatever the
+// format of its error response, or an empty string if it returned none.
+func (e CortexCloudAPIError) ServerMessage() string {
+	switch {
+	case e.Reply != nil:
+		return e.Reply.Message
+	case e.Data != nil:
+		return e.Data.Message
+	case e.ErrMsg != nil:
+		return *e.ErrMsg
+	case e.Message != nil:
+		return *e.Message
+	}
+	return ""
+}
+
+// ErrorDetails returns the err_extra entries and the per-field details of
+// the error response as CortexCloudSdkErrorDetail, field details sorted by
+// field path.
+func (e CortexCloudAPIError) ErrorDetails() []CortexCloudSdkErrorDetail {
+	var extras []CortexCloudAPIErrorExtra
+	switch {
+	case e.Reply != nil:
+		extras = e.Reply.Extra.Values()
+	case e.Data != nil && e.Data.Metadata != nil:
+		extras = e.Data.Metadata.Extra.Values()
+	case e.Metadata != nil:
+		extras = e.Metadata.Extra.Values()
+	}
+
+	var details []CortexCloudSdkErrorDetail
+	for _, extra := range extras {
+		location := strings.Join(extra.locationAsStringSlice(), ".")
+		if location == "" && extra.Field != nil {
+			location, _ = convertInterfaceToString(extra.Field)
+		}
+		message := extra.Message
+		if message == "" {
+			message = extra.MessageFull
+		}
+		details = append(details, CortexCloudSdkErrorDetail{
+			Location: location,
+			Code:     extra.Type,
+			Message:  message,
+		})
+	}
+
+	if e.Details != nil {
+		if e.Details.Params.Message != "" {
+			details = append(details, CortexCloudSdkErrorDetail{Message: e.Details.Params.Message})
+		}
+		fields := make([]string, 0, len(e.Details.Fields))
+		for field := range e.Details.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			details = append(details, CortexCloudSdkErrorDetail{
+				Location: field,
+				Message:  e.Details.Fields[field].Message,
+			})
+		}
+	}
+	return details
+}
+
 func (e CortexCloudAPIError) Error() string {
 	var sb strings.Builder
 
@@ -635,3 +635,105 @@ func TestHasContent(t *testing.T) {
 		}
 	})
 }
+
+// TestNewAPIError tests normalizing the error response formats into a CortexCloudSdkError
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		status      int
+		wantCode    string
+		wantMessage string
+		wantDetails []CortexCloudSdkErrorDetail
+	}{
+		{
+			name:        "reply",
+			body:        `{"reply":{"err_code":400,"err_msg":"Invalid","err_extra":[{"type":"missing","loc":["body","name"],"msg":"Field required"}]}}`,
+			status:      400,
+			wantCode:    "400",
+			wantMessage: "Invalid",
+			wantDetails: []CortexCloudSdkErrorDetail{{Location: "body.name", Code: "missing", Message: "Field required"}},
+		},
+		{
+			name:        "data and metadata",
+			body:        `{"data":{"err_msg":"Conflict","metadata":{"err_code":409,"err_extra":"Name already used"}}}`,
+			status:      409,
+			wantCode:    "409",
+			wantMessage: "Conflict",
+			wantDetails: []CortexCloudSdkErrorDetail{{Code: "string_error", Message: "Name already used"}},
+		},
+		{
+			name:        "root-level err_code and metadata",
+			body:        `{"err_msg":"Invalid","metadata":{"err_code":400,"err_extra":[{"field":"policy_id","message":"Invalid UUID"}]}}`,
+			status:      400,
+			wantCode:    "400",
+			wantMessage: "Invalid",
+			wantDetails: []CortexCloudSdkErrorDetail{{Location: "policy_id", Message: "Invalid UUID"}},
+		},
+		{
+			name:        "errorCode and per-field details",
+			body:        `{"errorCode":"ValidateError","message":"Validation Failed","details":{"policy.name":{"message":"'name' is required"},"policy.b":{"message":"invalid"}}}`,
+			status:      422,
+			wantCode:    "ValidateError",
+			wantMessage: "Validation Failed",
+			wantDetails: []CortexCloudSdkErrorDetail{
+				{Location: "policy.b", Message: "invalid"},
+				{Location: "policy.name", Message: "'name' is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr CortexCloudAPIError
+			if err := json.Unmarshal([]byte(tt.body), &apiErr); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			apiErr.HTTPStatus = tt.status
+
+			err := NewAPIError(&apiErr)
+			if err.Code != CodeAPIError {
+				t.Errorf("Code = %q, want %q", err.Code, CodeAPIError)
+			}
+			if err.HTTPStatus == nil || *err.HTTPStatus != tt.status {
+				t.Errorf("HTTPStatus = %v, want %d", err.HTTPStatus, tt.status)
+			}
+			if err.ServerCode != tt.wantCode {
+				t.Errorf("ServerCode = %q, want %q", err.ServerCode, tt.wantCode)
+			}
+			if err.ServerMessage != tt.wantMessage {
+				t.Errorf("ServerMessage = %q, want %q", err.ServerMessage, tt.wantMessage)
+			}
+			if len(err.Details) != len(tt.wantDetails) || err.InternalErrorsCount != len(tt.wantDetails) {
+				t.Fatalf("Details = %+v, want %+v", err.Details, tt.wantDetails)
+			}
+			for i, detail := range err.Details {
+				if detail != tt.wantDetails[i] {
+					t.Errorf("Details[%d] = %+v, want %+v", i, detail, tt.wantDetails[i])
+				}
+			}
+			if err.Unwrap() != &apiErr {
+				t.Errorf("error does not wrap the API error")
+			}
+
+			msg := err.Error()
+			if !contains(msg, "server_message='"+tt.wantMessage+"'") || contains(msg, ", underlying_error=") {
+				t.Errorf("Error() = %q", msg)
+			}
+		})
+	}
+}
+
+// TestCortexCloudSdkError_Error_Call tests the description of the call in the error string
+func TestCortexCloudSdkError_Error_Call(t *testing.T) {
+	err := NewInternalSDKError(CodeNetworkError, "request failed", nil)
+	if msg := err.Error(); contains(msg, "method=") || contains(msg, "attempts=") {
+		t.Errorf("Error() = %q describes a call", msg)
+	}
+
+	err.Method, err.Endpoint, err.RequestID, err.Attempts = "GET", "public_api/v1/users", "req-1", 3
+	want := "CortexCloudSdkError: code=NetworkError, method=GET, endpoint=public_api/v1/users, request_id=req-1, attempts=3, message='request failed'"
+	if msg := err.Error(); !contains(msg, want) {
+		t.Errorf("Error() = %q, want it to contain %q", msg, want)
+	}
+}
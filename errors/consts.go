@@ -24,15 +24,23 @@ const (
 	DetailCodeInvalidEnumValue = "InvalidEnumValue"
 	DetailMsgInvalidEnumValue  = "Invalid %s value \"%v\" - expected one of: %s"
 
-	CodeAPIResponseParsingFailure             = ""
-	CodeSDKInitializationFailure              = ""
-	CodeRequestSerializationFailure           = ""
-	CodeURLConstructionFailure                = ""
-	CodeContextCancellation                   = ""
-	CodeHTTPRequestCreationFailure            = ""
-	CodeAuthenticationHeaderGenerationFailure = ""
-	CodeNetworkError                          = ""
-	CodeNoResponseReceived                    = ""
-	CodeResponseBodyReadFailure               = ""
-	CodeResponseDeserializationFailure        = ""
+	// Error codes of CortexCloudSdkError. They are stable and distinct, so
+	// that callers can branch on them.
+	CodeAPIResponseParsingFailure             = "APIResponseParsingFailure"
+	CodeSDKInitializationFailure              = "SDKInitializationFailure"
+	CodeRequestSerializationFailure           = "RequestSerializationFailure"
+	CodeURLConstructionFailure                = "URLConstructionFailure"
+	CodeContextCancellation                   = "ContextCancellation"
+	CodeHTTPRequestCreationFailure            = "HTTPRequestCreationFailure"
+	CodeAuthenticationHeaderGenerationFailure = "AuthenticationHeaderGenerationFailure"
+	CodeNetworkError                          = "NetworkError"
+	CodeNoResponseReceived                    = "NoResponseReceived"
+	CodeResponseBodyReadFailure               = "ResponseBodyReadFailure"
+	CodeResponseDeserializationFailure        = "ResponseDeserializationFailure"
+	CodeRetriesExhausted                      = "RetriesExhausted"
+	CodeCircuitOpen                           = "CircuitOpen"
+	CodeAPIError                              = "APIError"
+	CodeResponseTooLarge                      = "ResponseTooLarge"
+	CodeClockSkew                             = "ClockSkew"
+	CodePanic                                 = "Panic"
 )
@@ -14,6 +14,10 @@ import (
 	"strings"
 )
 
+// ErrClockSkew is wrapped by the error returned when the tenant rejects the
+// signature of a request because the local clock is too far off its own.
+var ErrClockSkew = errors.New("clock skew")
+
 // CortexCloudSdkErrorDetail provides granular context about specific error instances,
 // often used for validation errors or specific problem details within the main error.
 type CortexCloudSdkErrorDetail struct {
@@ -27,12 +31,18 @@ type CortexCloudSdkErrorDetail struct {
 // It can represent errors returned by an upstream API (with HTTPStatus populated)
 // or internal SDK errors (with HTTPStatus as nil).
 type CortexCloudSdkError struct {
-	Code                string                      `json:"code"`                  // A unique, machine-readable error code (e.g., "INVALID_ARGUMENT", "UNAUTHORIZED", "SDK_INIT_FAILED").
-	Message             string                      `json:"message"`               // A human-readable message describing the error.
-	Details             []CortexCloudSdkErrorDetail `json:"details"`               // Optional, additional context or validation errors.
-	InternalErrorsCount int                         `json:"internal_errors_count"` // Total number of internal errors returned.
-	HTTPStatus          *int                        `json:"http_status"`           // The HTTP status code associated with this error. This value is nil for internal errors.
-	Err                 error                       `json:"underlying_error"`      // The underlying Go error returned by the validation module.
+	Code                string                      `json:"code"`                     // A unique, machine-readable error code (e.g., "INVALID_ARGUMENT", "UNAUTHORIZED", "SDK_INIT_FAILED").
+	Message             string                      `json:"message"`                  // A human-readable message describing the error.
+	Details             []CortexCloudSdkErrorDetail `json:"details"`                  // Optional, additional context or validation errors.
+	InternalErrorsCount int                         `json:"internal_errors_count"`    // Total number of internal errors returned.
+	HTTPStatus          *int                        `json:"http_status"`              // The HTTP status code associated with this error. This value is nil for internal errors.
+	Method              string                      `json:"method,omitempty"`         // The HTTP method of the call that failed, e.g. "POST".
+	Endpoint            string                      `json:"endpoint,omitempty"`       // The endpoint of the call that failed, e.g. "public_api/v1/rbac/get_users".
+	RequestID           string                      `json:"request_id,omitempty"`     // The X-Request-ID sent with every attempt of the call.
+	Attempts            int                         `json:"attempts,omitempty"`       // The number of attempts made, retries included.
+	ServerCode          string                      `json:"server_code,omitempty"`    // The error code returned by the API, whatever the format of its error response.
+	ServerMessage       string                      `json:"server_message,omitempty"` // The error message returned by the API, whatever the format of its error response.
+	Err                 error                       `json:"underlying_error"`         // The underlying Go error returned by the validation module.
 }
 
 // Error implements the error interface for CortexCloudSdkError.
@@ -46,6 +56,18 @@ func (e *CortexCloudSdkError) Error() string {
 	if e.HTTPStatus != nil {
 		statusStr = fmt.Sprintf("http_status=%d, ", *e.HTTPStatus)
 	}
+	if e.Method != "" || e.Endpoint != "" {
+		statusStr += fmt.Sprintf("method=%s, endpoint=%s, ", e.Method, e.Endpoint)
+	}
+	if e.RequestID != "" {
+		statusStr += fmt.Sprintf("request_id=%s, ", e.RequestID)
+	}
+	if e.Attempts > 0 {
+		statusStr += fmt.Sprintf("attempts=%d, ", e.Attempts)
+	}
+	if e.ServerCode != "" || e.ServerMessage != "" {
+		statusStr += fmt.Sprintf("server_code=%s, server_message='%s', ", e.ServerCode, e.ServerMessage)
+	}
 
 	var (
 		detailsStr string
@@ -59,7 +81,11 @@ func (e *CortexCloudSdkError) Error() string {
 		errorBytes []byte
 		errorStr   string
 	)
-	if e.Err != nil {
+	// The API error is already described by the server code, message and
+	// details, whatever the format of the error response
+	_, describedAPIError := e.Err.(*CortexCloudAPIError)
+	describedAPIError = describedAPIError && (e.ServerCode != "" || e.ServerMessage != "")
+	if e.Err != nil && !describedAPIError {
 		if errorBytes, err = json.Marshal(e.Err.Error()); err != nil {
 			errorStr = "unknown"
 		}
@@ -103,6 +129,24 @@ func NewCortexCloudSdkError(
 	}
 }
 
+// NewAPIError creates a CortexCloudSdkError for an error response of the API,
+// with the HTTP status, server code and message and details of apiErr. The
+// returned error wraps apiErr.
+func NewAPIError(apiErr *CortexCloudAPIError) *CortexCloudSdkError {
+	var status *int
+	if code := apiErr.statusCode(); code != 0 {
+		status = &code
+	}
+	message := "API request failed"
+	if status != nil {
+		message = fmt.Sprintf("API request failed with HTTP %d", *status)
+	}
+	err := NewCortexCloudSdkError(CodeAPIError, message, apiErr.ErrorDetails(), status, apiErr)
+	err.ServerCode = apiErr.ServerCode()
+	err.ServerMessage = apiErr.ServerMessage()
+	return err
+}
+
 // IsCortexCloudSdkError checks if the given error is of type *CortexCloudSdkError.
 // It uses errors.As to safely perform the type assertion.
 func IsCortexCloudSdkError(err error) bool {
@@ -159,6 +203,38 @@ func (e CortexCloudSdkError) DetailsToPrettyJSON() (string, error) {
 	return string(data), nil
 }
 
+// ResponseTooLargeError reports a response body larger than the configured
+// maximum response size. It is returned wrapped in a CortexCloudSdkError.
+type ResponseTooLargeError struct {
+	Limit         int64 // The configured maximum response size in bytes.
+	ContentLength int64 // The Content-Length announced by the server, or -1 if unknown.
+}
+
+// Error implements the error interface for ResponseTooLargeError.
+func (e *ResponseTooLargeError) Error() string {
+	if e.ContentLength >= 0 {
+		return fmt.Sprintf("response body of %d bytes exceeds the maximum response size of %d bytes", e.ContentLength, e.Limit)
+	}
+	return fmt.Sprintf("response body exceeds the maximum response size of %d bytes", e.Limit)
+}
+
+// PanicError reports a panic recovered inside the SDK while performing a
+// call, e.g. in a custom MarshalJSON or UnmarshalJSON method. It is returned
+// wrapped in a CortexCloudSdkError.
+type PanicError struct {
+	Value      any    // The value the SDK panicked with.
+	Stack      []byte // The stack trace of the panic.
+	BundlePath string // The diagnostic bundle file, or "" if none was written.
+}
+
+// Error implements the error interface for PanicError.
+func (e *PanicError) Error() string {
+	if e.BundlePath == "" {
+		return fmt.Sprintf("SDK panic: %v", e.Value)
+	}
+	return fmt.Sprintf("SDK panic: %v (diagnostic bundle written to %s)", e.Value, e.BundlePath)
+}
+
 // --- Convenience Functions for Common Error Scenarios ---
 
 // NewBadRequest creates a CortexCloudSdkError for HTTP 400 Bad Request.
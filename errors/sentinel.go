@@ -0,0 +1,110 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors matched by CortexCloudSdkError and CortexCloudAPIError
+// through errors.Is, whatever the module that returned them:
+//
+//	if errors.Is(err, cortexerrors.ErrNotFound) {
+//		// ...
+//	}
+var (
+	// ErrNotFound matches errors of calls the API answered with HTTP 404.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches errors of calls the API answered with HTTP
+	// 401, because the credentials are invalid or the signature expired.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches errors of calls the API answered with HTTP 403.
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict matches errors of calls the API answered with HTTP 409.
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited matches errors of calls the API answered with HTTP
+	// 429, once the retries allowed by the retry policy are exhausted.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTimeout matches errors of calls that timed out: their context
+	// deadline or the HTTP timeout expired, or the API answered with HTTP
+	// 408 or 504.
+	ErrTimeout = errors.New("timeout")
+	// ErrCanceled matches errors of calls whose context was canceled.
+	ErrCanceled = errors.New("canceled")
+)
+
+// statusSentinel returns the sentinel error matching the HTTP status, or nil.
+func statusSentinel(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return ErrTimeout
+	}
+	return nil
+}
+
+// Is reports whether the error matches target, one of the sentinel errors of
+// this package, from its HTTP status or the error it wraps.
+func (e *CortexCloudSdkError) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	if e.HTTPStatus != nil && target == statusSentinel(*e.HTTPStatus) {
+		return true
+	}
+	switch target {
+	case ErrTimeout:
+		var timeout interface{ Timeout() bool }
+		return errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &timeout) && timeout.Timeout())
+	case ErrCanceled:
+		return errors.Is(e.Err, context.Canceled)
+	}
+	return false
+}
+
+// Is reports whether the error matches target, one of the sentinel errors of
+// this package, from the error code returned by the API.
+func (e CortexCloudAPIError) Is(target error) bool {
+	sentinel := statusSentinel(e.statusCode())
+	return sentinel != nil && target == sentinel
+}
+
+// statusCode returns the HTTP status of the response the error was parsed
+// from or, if it is not known, the one the error code returned by the API
+// stands for, or 0 if it is not one.
+func (e CortexCloudAPIError) statusCode() int {
+	var code int
+	switch {
+	case e.HTTPStatus != 0:
+		code = e.HTTPStatus
+	case e.Reply != nil:
+		code = e.Reply.Code
+	case e.Data != nil && e.Data.Metadata != nil:
+		code = e.Data.Metadata.Code
+	case e.ErrCode != nil && *e.ErrCode != 0:
+		code = *e.ErrCode
+	case e.Metadata != nil:
+		code = e.Metadata.Code
+	case e.Code != nil:
+		// Errors of unrecognized formats carry the status as "HTTP_404"
+		code, _ = strconv.Atoi(strings.TrimPrefix(*e.Code, "HTTP_"))
+	}
+	if code < 100 || code > 599 {
+		return 0
+	}
+	return code
+}
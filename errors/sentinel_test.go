@@ -0,0 +1,115 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// TestCodes_Distinct tests that every error code is set and unique
+func TestCodes_Distinct(t *testing.T) {
+	codes := []string{
+		CodeAPIResponseParsingFailure, CodeSDKInitializationFailure, CodeRequestSerializationFailure,
+		CodeURLConstructionFailure, CodeContextCancellation, CodeHTTPRequestCreationFailure,
+		CodeAuthenticationHeaderGenerationFailure, CodeNetworkError, CodeNoResponseReceived,
+		CodeResponseBodyReadFailure, CodeResponseDeserializationFailure, CodeRetriesExhausted,
+		CodeCircuitOpen, CodeAPIError, CodeResponseTooLarge, CodeClockSkew, CodePanic,
+		CodePreRequestValidationFailure,
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if code == "" {
+			t.Fatalf("error code is empty")
+		}
+		if seen[code] {
+			t.Errorf("error code %q is not unique", code)
+		}
+		seen[code] = true
+	}
+}
+
+// TestCortexCloudSdkError_Is_Status tests matching sentinels from the HTTP status
+func TestCortexCloudSdkError_Is_Status(t *testing.T) {
+	for status, sentinel := range map[int]error{
+		http.StatusNotFound:        ErrNotFound,
+		http.StatusUnauthorized:    ErrUnauthorized,
+		http.StatusForbidden:       ErrForbidden,
+		http.StatusConflict:        ErrConflict,
+		http.StatusTooManyRequests: ErrRateLimited,
+		http.StatusGatewayTimeout:  ErrTimeout,
+	} {
+		err := fmt.Errorf("wrapped: %w", NewCortexCloudSdkError(CodeAPIError, "failed", nil, &status, nil))
+		if !errors.Is(err, sentinel) {
+			t.Errorf("HTTP %d error does not match %v", status, sentinel)
+		}
+		if errors.Is(err, ErrCanceled) {
+			t.Errorf("HTTP %d error matches %v", status, ErrCanceled)
+		}
+	}
+
+	status := http.StatusInternalServerError
+	err := NewCortexCloudSdkError(CodeAPIError, "failed", nil, &status, nil)
+	for _, sentinel := range []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrConflict, ErrRateLimited, ErrTimeout, ErrCanceled} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("HTTP 500 error matches %v", sentinel)
+		}
+	}
+}
+
+// TestCortexCloudSdkError_Is_Context tests matching timeouts and cancellations
+func TestCortexCloudSdkError_Is_Context(t *testing.T) {
+	canceled := NewInternalSDKError(CodeContextCancellation, "cancelled", context.Canceled)
+	if !errors.Is(canceled, ErrCanceled) || errors.Is(canceled, ErrTimeout) {
+		t.Errorf("cancellation error does not match only ErrCanceled")
+	}
+
+	deadline := NewInternalSDKError(CodeContextCancellation, "cancelled", context.DeadlineExceeded)
+	if !errors.Is(deadline, ErrTimeout) || errors.Is(deadline, ErrCanceled) {
+		t.Errorf("deadline error does not match only ErrTimeout")
+	}
+
+	netTimeout := &url.Error{Op: "Get", URL: "https://api.example.com", Err: &net.DNSError{IsTimeout: true}}
+	if !errors.Is(NewInternalSDKError(CodeNetworkError, "failed", netTimeout), ErrTimeout) {
+		t.Errorf("network timeout error does not match ErrTimeout")
+	}
+
+	refused := &url.Error{Op: "Get", URL: "https://api.example.com", Err: errors.New("connection refused")}
+	if errors.Is(NewInternalSDKError(CodeNetworkError, "failed", refused), ErrTimeout) {
+		t.Errorf("network error matches ErrTimeout")
+	}
+}
+
+// TestCortexCloudAPIError_Is tests matching sentinels from the error codes of each format
+func TestCortexCloudAPIError_Is(t *testing.T) {
+	for body, sentinel := range map[string]error{
+		`{"reply":{"err_code":404,"err_msg":"Not Found"}}`:                     ErrNotFound,
+		`{"data":{"err_msg":"Forbidden","metadata":{"err_code":403}}}`:         ErrForbidden,
+		`{"err_code":409,"err_msg":"Conflict"}`:                                ErrConflict,
+		`{"err_msg":"Unauthorized","metadata":{"err_code":401}}`:               ErrUnauthorized,
+		`{"errorCode":"HTTP_429","message":"API error (HTTP 429): slow down"}`: ErrRateLimited,
+	} {
+		var apiError CortexCloudAPIError
+		if err := json.Unmarshal([]byte(body), &apiError); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", body, err)
+		}
+		if !errors.Is(apiError, sentinel) || !errors.Is(&apiError, sentinel) {
+			t.Errorf("%s does not match %v", body, sentinel)
+		}
+		if errors.Is(apiError, ErrTimeout) {
+			t.Errorf("%s matches %v", body, ErrTimeout)
+		}
+	}
+
+	code := "VALIDATION_ERROR"
+	if errors.Is(CortexCloudAPIError{Code: &code}, ErrNotFound) {
+		t.Errorf("error without status matches ErrNotFound")
+	}
+}
@@ -3,8 +3,10 @@ module github.com/PaloAltoNetworks/cortex-cloud-go
 go 1.25.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/hashicorp/terraform-plugin-log v0.10.0
 	github.com/stretchr/testify v1.11.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -15,5 +17,4 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	golang.org/x/sys v0.39.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
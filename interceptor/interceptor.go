@@ -0,0 +1,70 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package interceptor defines the middleware chain that every attempt of an
+// SDK call passes through.
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request describes a single attempt of an SDK call. Interceptors may modify
+// it before passing it on. The request is authenticated once it leaves the
+// chain, so that its signature covers the changes of the interceptors.
+type Request struct {
+	Method     string      // The HTTP method.
+	Endpoint   string      // The SDK endpoint constant, e.g. "public_api/v1/health_check/".
+	PathParams []string    // The path parameters appended to the endpoint.
+	Query      url.Values  // The query parameters.
+	Body       []byte      // The marshaled request body, or nil if there is none.
+	Header     http.Header // The request headers, without authentication headers.
+	Attempt    int         // The zero-based attempt number.
+}
+
+// Response describes the response to a single attempt. Interceptors may
+// modify it before returning it, or return one of their own without calling
+// the next invoker.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Invoker performs the rest of the chain for an attempt.
+//
+// The error is a *errors.CortexCloudSdkError when the attempt failed. If the
+// tenant answered with an error status, the Response is returned alongside
+// the error, which then wraps the *errors.CortexCloudAPIError parsed from
+// the response body.
+type Invoker func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor is called for every attempt of every SDK call. It may inspect
+// or modify the request, call next to continue the chain, and inspect or
+// modify the response and error before returning them. Returning without
+// calling next short-circuits the attempt.
+//
+// Errors returned by an interceptor that did not come from next end the call
+// without retrying. Responses returned with a nil error are treated as
+// successful, whatever their status code.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*Response, error)
+
+// Chain returns an Interceptor that calls interceptors in order, the first
+// being the outermost.
+func Chain(interceptors ...Interceptor) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next = bind(interceptors[i], next)
+		}
+		return next(ctx, req)
+	}
+}
+
+// bind returns an Invoker that calls interceptor with next.
+func bind(interceptor Interceptor, next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		return interceptor(ctx, req, next)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+			calls = append(calls, name+" before")
+			resp, err := next(ctx, req)
+			calls = append(calls, name+" after")
+			return resp, err
+		}
+	}
+	final := func(ctx context.Context, req *Request) (*Response, error) {
+		calls = append(calls, "invoke")
+		return &Response{StatusCode: http.StatusOK}, nil
+	}
+
+	resp, err := Chain(record("first"), record("second"))(context.Background(), &Request{}, final)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"first before", "second before", "invoke", "second after", "first after"}, calls)
+
+	t.Run("should short-circuit", func(t *testing.T) {
+		calls = nil
+		cached := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+			return &Response{StatusCode: http.StatusNotModified}, nil
+		}
+		resp, err := Chain(record("first"), cached, record("third"))(context.Background(), &Request{}, final)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, []string{"first before", "first after"}, calls)
+	})
+
+	t.Run("should call the invoker directly without interceptors", func(t *testing.T) {
+		calls = nil
+		_, err := Chain()(context.Background(), &Request{}, final)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"invoke"}, calls)
+	})
+}
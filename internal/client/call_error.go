@@ -0,0 +1,36 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	stderrors "errors"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+)
+
+// annotateError records the call described by state in the SDK error err
+// returned by the call, along with the server code, message and details of
+// the API error it wraps, if any. Errors returned by interceptors that are
+// not SDK errors are left as is.
+func (s *callState) annotateError(err error, method, endpoint string) {
+	var sdkErr *errors.CortexCloudSdkError
+	if !stderrors.As(err, &sdkErr) {
+		return
+	}
+	sdkErr.Method = method
+	sdkErr.Endpoint = endpoint
+	sdkErr.RequestID = s.requestID
+	sdkErr.Attempts = s.attempts
+
+	var apiErr *errors.CortexCloudAPIError
+	if sdkErr.ServerCode != "" || !stderrors.As(sdkErr.Err, &apiErr) {
+		return
+	}
+	sdkErr.ServerCode = apiErr.ServerCode()
+	sdkErr.ServerMessage = apiErr.ServerMessage()
+	if len(sdkErr.Details) == 0 {
+		sdkErr.Details = apiErr.ErrorDetails()
+		sdkErr.InternalErrorsCount = len(sdkErr.Details)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_CallErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"errorCode":"Unavailable","message":"try again later"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reply":{"err_code":400,"err_msg":"Invalid","err_extra":[{"type":"missing","loc":["body","name"],"msg":"Field required"}]}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithLogLevel("quiet"),
+		config.WithRetryPolicy(retry.ExponentialJitter{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 2}),
+	))
+	require.NoError(t, err)
+
+	t.Run("should describe a rejected call", func(t *testing.T) {
+		ctx := WithCallOptions(context.Background(), WithCallRequestID("req-1"))
+		_, err := client.Do(ctx, http.MethodPost, "invalid", nil, nil, map[string]any{}, nil, nil)
+
+		var sdkErr *errors.CortexCloudSdkError
+		require.True(t, stderrors.As(err, &sdkErr), "error %v is not a CortexCloudSdkError", err)
+		assert.Equal(t, errors.CodeAPIError, sdkErr.Code)
+		require.NotNil(t, sdkErr.HTTPStatus)
+		assert.Equal(t, http.StatusBadRequest, *sdkErr.HTTPStatus)
+		assert.Equal(t, http.MethodPost, sdkErr.Method)
+		assert.Equal(t, "invalid", sdkErr.Endpoint)
+		assert.Equal(t, "req-1", sdkErr.RequestID)
+		assert.Equal(t, 1, sdkErr.Attempts)
+		assert.Equal(t, "400", sdkErr.ServerCode)
+		assert.Equal(t, "Invalid", sdkErr.ServerMessage)
+		assert.Equal(t, []errors.CortexCloudSdkErrorDetail{{Location: "body.name", Code: "missing", Message: "Field required"}}, sdkErr.Details)
+		assert.Contains(t, err.Error(), "method=POST, endpoint=invalid, request_id=req-1, attempts=1")
+
+		var apiErr *errors.CortexCloudAPIError
+		require.True(t, stderrors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.HTTPStatus)
+	})
+
+	t.Run("should describe a call that exhausted its retries", func(t *testing.T) {
+		_, err := client.Do(context.Background(), http.MethodGet, "busy", nil, nil, nil, nil, nil)
+
+		var sdkErr *errors.CortexCloudSdkError
+		require.True(t, stderrors.As(err, &sdkErr), "error %v is not a CortexCloudSdkError", err)
+		assert.Equal(t, errors.CodeRetriesExhausted, sdkErr.Code)
+		assert.Equal(t, http.MethodGet, sdkErr.Method)
+		assert.NotEmpty(t, sdkErr.RequestID)
+		assert.Equal(t, 3, sdkErr.Attempts)
+		assert.Equal(t, "Unavailable", sdkErr.ServerCode)
+		assert.Equal(t, "try again later", sdkErr.ServerMessage)
+	})
+
+	t.Run("should describe a call that failed before being sent", func(t *testing.T) {
+		_, err := client.Do(context.Background(), http.MethodGet, "%zz", nil, nil, nil, nil, nil)
+
+		var sdkErr *errors.CortexCloudSdkError
+		require.True(t, stderrors.As(err, &sdkErr), "error %v is not a CortexCloudSdkError", err)
+		assert.Nil(t, sdkErr.HTTPStatus)
+		assert.Equal(t, "%zz", sdkErr.Endpoint)
+		assert.Zero(t, sdkErr.Attempts)
+		assert.Empty(t, sdkErr.ServerCode)
+	})
+}
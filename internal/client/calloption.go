@@ -0,0 +1,136 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+)
+
+// callOptionsKey is the context key for per-call options.
+const callOptionsKey contextKey = "cortex-call-options"
+
+// CallOption overrides the client configuration for a single call.
+type CallOption func(*callOptions)
+
+// callOptions holds the overrides applied to a single call.
+type callOptions struct {
+	headers        map[string]string
+	timeout        time.Duration
+	attemptTimeout time.Duration
+	maxRetries     *int
+	retryPolicy    retry.Policy
+	requestID      string
+	responseMeta   *ResponseMeta
+}
+
+// WithCallOptions returns a context that applies opts to every call made
+// with it. Options are added to those already present in ctx, with later
+// options taking precedence.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	call := getCallOptions(ctx).clone()
+	for _, opt := range opts {
+		opt(&call)
+	}
+	return context.WithValue(ctx, callOptionsKey, &call)
+}
+
+// WithCallHeader returns a CallOption that sets an HTTP header on every
+// attempt of the call. Headers managed by the SDK, such as authentication
+// headers, cannot be overridden.
+func WithCallHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// WithCallTimeout returns a CallOption that bounds the whole call, including
+// every retry attempt and the waits between them.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithAttemptTimeout returns a CallOption that bounds each attempt of the
+// call. An attempt that times out is retried like any other network failure.
+func WithAttemptTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.attemptTimeout = d
+	}
+}
+
+// WithCallMaxRetries returns a CallOption that overrides the maximum number
+// of retries for the call, using the default exponential backoff.
+func WithCallMaxRetries(n int) CallOption {
+	return func(o *callOptions) {
+		o.maxRetries = &n
+	}
+}
+
+// WithCallRetryPolicy returns a CallOption that overrides the retry policy
+// for the call. It takes precedence over WithCallMaxRetries.
+func WithCallRetryPolicy(policy retry.Policy) CallOption {
+	return func(o *callOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithCallRequestID returns a CallOption that sets the X-Request-ID sent
+// with every attempt of the call instead of generating one.
+func WithCallRequestID(id string) CallOption {
+	return func(o *callOptions) {
+		o.requestID = id
+	}
+}
+
+// getCallOptions returns the call options attached to ctx, or an empty set
+// if there are none.
+func getCallOptions(ctx context.Context) *callOptions {
+	if call, ok := ctx.Value(callOptionsKey).(*callOptions); ok {
+		return call
+	}
+	return &callOptions{}
+}
+
+// clone returns a copy of o that can be modified without affecting o.
+func (o *callOptions) clone() callOptions {
+	c := *o
+	c.headers = maps.Clone(o.headers)
+	return c
+}
+
+// retryPolicy returns the retry policy to use for the call.
+func (c *Client) retryPolicy(call *callOptions) retry.Policy {
+	policy := call.retryPolicy
+	if policy == nil && call.maxRetries != nil {
+		policy = retry.ExponentialJitter{
+			BaseDelay:  time.Second,
+			MaxDelay:   maxRetryDelay(c.config),
+			MaxRetries: *call.maxRetries,
+		}
+	}
+	if policy == nil {
+		return c.retrier
+	}
+	if budget := c.config.RetryBudget(); budget != nil {
+		policy = retry.WithBudget(policy, budget)
+	}
+	return policy
+}
+
+// attemptContext returns the context for a single attempt of the call,
+// bounded by the per-attempt timeout if one is set.
+func attemptContext(ctx context.Context, call *callOptions) (context.Context, context.CancelFunc) {
+	if call.attemptTimeout > 0 {
+		return context.WithTimeout(ctx, call.attemptTimeout)
+	}
+	return context.WithCancel(ctx)
+}
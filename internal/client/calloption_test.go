@@ -0,0 +1,162 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCallOptions(t *testing.T) {
+	parent := WithCallOptions(context.Background(),
+		WithCallHeader("X-One", "1"),
+		WithCallMaxRetries(2),
+	)
+	child := WithCallOptions(parent,
+		WithCallHeader("X-Two", "2"),
+		WithCallRequestID("req_fixed"),
+	)
+
+	call := getCallOptions(child)
+	assert.Equal(t, map[string]string{"X-One": "1", "X-Two": "2"}, call.headers)
+	require.NotNil(t, call.maxRetries)
+	assert.Equal(t, 2, *call.maxRetries)
+	assert.Equal(t, "req_fixed", call.requestID)
+
+	// The parent is unaffected by options added to the child.
+	assert.Equal(t, map[string]string{"X-One": "1"}, getCallOptions(parent).headers)
+	assert.Empty(t, getCallOptions(context.Background()).headers)
+}
+
+func TestGenerateHeaders_CustomHeaders(t *testing.T) {
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL("https://testing.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithCortexAPIKeyType("standard"),
+		config.WithHeaders(map[string]string{
+			"X-Tenant":      "config",
+			"X-Team":        "platform",
+			"Authorization": "spoofed",
+		}),
+	))
+	require.NoError(t, err)
+
+	ctx := WithCallOptions(context.Background(), WithCallHeader("X-Tenant", "call"))
+	req := httptest.NewRequest(http.MethodGet, "https://testing.com/test", nil)
+	req.Header = client.generateHeaders(ctx, true)
+	require.NoError(t, client.authenticate(req))
+
+	assert.Equal(t, "call", req.Header.Get("X-Tenant"))
+	assert.Equal(t, "platform", req.Header.Get("X-Team"))
+	assert.Equal(t, "key", req.Header.Get("Authorization"))
+}
+
+func TestDo_CallOptions(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+		delay    atomic.Int64
+		status   atomic.Int32
+	)
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		time.Sleep(time.Duration(delay.Load()))
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(0),
+		config.WithHeaders(map[string]string{"X-Configured": "yes"}),
+		config.WithSkipLoggingTransport(true),
+	))
+	require.NoError(t, err)
+
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = nil
+	}
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(requests)
+	}
+
+	t.Run("should send headers and fixed request ID", func(t *testing.T) {
+		reset()
+		ctx := WithCallOptions(context.Background(),
+			WithCallHeader("X-Extra", "1"),
+			WithCallRequestID("req_fixed"),
+		)
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, count())
+		assert.Equal(t, "yes", requests[0].Header.Get("X-Configured"))
+		assert.Equal(t, "1", requests[0].Header.Get("X-Extra"))
+		assert.Equal(t, "req_fixed", requests[0].Header.Get("X-Request-ID"))
+	})
+
+	t.Run("should override retries", func(t *testing.T) {
+		reset()
+		status.Store(http.StatusBadGateway)
+		defer status.Store(http.StatusOK)
+
+		ctx := WithCallOptions(context.Background(), WithCallRetryPolicy(retry.Constant{MaxRetries: 2}))
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.Equal(t, 3, count())
+	})
+
+	t.Run("should retry attempts that time out", func(t *testing.T) {
+		reset()
+		delay.Store(int64(time.Second))
+		defer delay.Store(0)
+
+		ctx := WithCallOptions(context.Background(),
+			WithAttemptTimeout(20*time.Millisecond),
+			WithCallRetryPolicy(retry.Constant{MaxRetries: 1}),
+		)
+		start := time.Now()
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 2, count())
+	})
+
+	t.Run("should bound the whole call", func(t *testing.T) {
+		reset()
+		status.Store(http.StatusServiceUnavailable)
+		defer status.Store(http.StatusOK)
+
+		ctx := WithCallOptions(context.Background(),
+			WithCallTimeout(50*time.Millisecond),
+			WithCallRetryPolicy(retry.Constant{Delay: time.Minute, MaxRetries: 5}),
+		)
+		start := time.Now()
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/circuit"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+)
+
+const (
+	// healthCheckEndpoint is the path of the tenant health check endpoint,
+	// used to probe the tenant while the circuit breaker is half-open.
+	healthCheckEndpoint = "public_api/v1/health_check/"
+
+	// circuitProbeKey is the context key marking circuit breaker probes.
+	circuitProbeKey contextKey = "cortex-circuit-probe"
+)
+
+// isCircuitProbe reports whether ctx belongs to a circuit breaker probe.
+// Probes bypass the breaker and are never retried.
+func isCircuitProbe(ctx context.Context) bool {
+	probe, _ := ctx.Value(circuitProbeKey).(bool)
+	return probe
+}
+
+// admitCircuit returns an error if the circuit breaker rejects the next
+// attempt. If the breaker's cooldown has elapsed, the tenant is probed first
+// and the attempt only proceeds if the probe succeeds.
+func (c *Client) admitCircuit(ctx context.Context) error {
+	breaker := c.config.CircuitBreaker()
+	if breaker == nil || isCircuitProbe(ctx) {
+		return nil
+	}
+
+	probe, err := breaker.Allow()
+	if err != nil {
+		return circuitOpenError(err)
+	}
+	if probe {
+		c.probeCircuit(ctx, breaker)
+		if err := breaker.Err(); err != nil {
+			return circuitOpenError(err)
+		}
+	}
+	return nil
+}
+
+// probeCircuit sends the breaker's probe request. Its outcome is recorded by
+// Do like any other attempt, closing or reopening the breaker.
+//
+// The probe does not belong to the call that triggered it: it is neither
+// cancelled with it nor sent with its call options and request ID.
+func (c *Client) probeCircuit(ctx context.Context, breaker *circuit.Breaker) {
+	ctx = context.WithoutCancel(ctx)
+	ctx = context.WithValue(ctx, callOptionsKey, &callOptions{})
+	ctx = WithRequestID(ctx, "")
+	ctx = context.WithValue(ctx, circuitProbeKey, true)
+	switch breaker.Probe() {
+	case circuit.ProbeHealthCheck:
+		_, _ = c.Do(ctx, http.MethodGet, healthCheckEndpoint, nil, nil, nil, nil, nil)
+	default:
+		_, _ = c.ValidateAPIKey(ctx)
+	}
+
+	// The probe never reached the tenant (e.g. the request failed to be
+	// built); reopen the breaker rather than leaving it half-open.
+	if breaker.State() == circuit.StateHalfOpen {
+		breaker.Failure()
+	}
+}
+
+// recordCircuit reports the outcome of an attempt to the circuit breaker.
+// Network failures and 5xx responses count as failures; any other response
+// shows that the tenant is reachable.
+func (c *Client) recordCircuit(resp *http.Response) {
+	breaker := c.config.CircuitBreaker()
+	if breaker == nil {
+		return
+	}
+	if resp == nil || resp.StatusCode >= http.StatusInternalServerError {
+		breaker.Failure()
+		return
+	}
+	breaker.Success()
+}
+
+// circuitOpenError wraps the error returned by an open circuit breaker.
+func circuitOpenError(err error) *errors.CortexCloudSdkError {
+	return errors.NewInternalSDKError(
+		errors.CodeCircuitOpen,
+		"request rejected without being sent because the circuit breaker is open: "+err.Error(),
+		err,
+	)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/circuit"
+	sdkerrors "github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_CircuitBreakerFailsFast(t *testing.T) {
+	t.Run("should stop retrying and reject further calls once the breaker is open", func(t *testing.T) {
+		breaker := circuit.New(circuit.WithThreshold(2), circuit.WithCooldown(time.Hour))
+		client := newTestClient(t, "https://testing.com", config.WithMaxRetries(5), config.WithCircuitBreaker(breaker))
+
+		newResp := func() *http.Response {
+			return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader(""))}
+		}
+		client.testData = []*http.Response{newResp(), newResp(), newResp()}
+
+		// The breaker trips during the retries and stops them.
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, circuit.ErrOpen)
+		assert.Equal(t, 2, client.testIndex)
+
+		// Further calls are rejected without reaching the tenant.
+		_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		var openErr *circuit.OpenError
+		require.ErrorAs(t, err, &openErr)
+		assert.Equal(t, circuit.StateOpen, openErr.State)
+		assert.True(t, sdkerrors.IsCortexCloudSdkError(err))
+		assert.Equal(t, 2, client.testIndex)
+	})
+}
+
+func TestDo_CircuitBreakerProbes(t *testing.T) {
+	tests := []struct {
+		name     string
+		probe    circuit.Probe
+		endpoint string
+	}{
+		{"should probe the tenant by validating the API key", circuit.ProbeValidateAPIKey, "/" + ValidateAPIKeyEndpoint},
+		{"should probe the tenant with its health check", circuit.ProbeHealthCheck, "/" + healthCheckEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				paths []string
+				down  atomic.Bool
+			)
+			down.Store(true)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				paths = append(paths, r.URL.Path)
+				mu.Unlock()
+				if down.Load() {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				_, _ = w.Write([]byte(`true`))
+			}))
+			defer server.Close()
+
+			var transitions []string
+			breaker := circuit.New(
+				circuit.WithThreshold(1),
+				circuit.WithCooldown(0),
+				circuit.WithProbe(tt.probe),
+				circuit.WithStateChangeFunc(func(from, to circuit.State) {
+					transitions = append(transitions, from.String()+"->"+to.String())
+				}),
+			)
+			client := newTestClient(t, server.URL, config.WithCircuitBreaker(breaker))
+
+			_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+			require.Error(t, err)
+			assert.Equal(t, circuit.StateOpen, breaker.State())
+
+			// The probe fails, so the request is rejected without being sent.
+			_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+			assert.ErrorIs(t, err, circuit.ErrOpen)
+
+			// The probe succeeds, so the request goes through.
+			down.Store(false)
+			_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, circuit.StateClosed, breaker.State())
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, []string{"/test", tt.endpoint, tt.endpoint, "/test"}, paths)
+			assert.Equal(t, []string{
+				"closed->open",
+				"open->half-open", "half-open->open",
+				"open->half-open", "half-open->closed",
+			}, transitions)
+		})
+	}
+}
+
+func TestDo_CircuitBreakerProbeIsolated(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		probeIDs []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+healthCheckEndpoint {
+			mu.Lock()
+			probeIDs = append(probeIDs, r.Header.Get("X-Request-ID"))
+			mu.Unlock()
+			// Drop the connection, failing the probe with a network error
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breaker := circuit.New(circuit.WithThreshold(1), circuit.WithCooldown(20*time.Millisecond), circuit.WithProbe(circuit.ProbeHealthCheck))
+	budget := retry.NewBudget(10, time.Hour)
+	client := newTestClient(t, server.URL,
+		config.WithCircuitBreaker(breaker),
+		config.WithRetryPolicy(retry.ExponentialJitter{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 3}),
+		config.WithRetryBudget(budget),
+		config.WithLogLevel("quiet"),
+	)
+
+	_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	require.Equal(t, circuit.StateOpen, breaker.State())
+	remaining := budget.Remaining()
+
+	t.Run("should not spend the retry budget or reuse the call options", func(t *testing.T) {
+		time.Sleep(30 * time.Millisecond)
+		ctx, cancel := context.WithCancel(WithCallOptions(context.Background(), WithCallRequestID("caller")))
+		defer cancel()
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, circuit.ErrOpen)
+
+		assert.Equal(t, remaining, budget.Remaining())
+		mu.Lock()
+		defer mu.Unlock()
+		require.NotEmpty(t, probeIDs)
+		for _, id := range probeIDs {
+			assert.NotEmpty(t, id)
+			assert.NotEqual(t, "caller", id)
+		}
+	})
+}
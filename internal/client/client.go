@@ -5,34 +5,34 @@
 package client
 
 import (
+	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/sha256"
-	"crypto/tls"
-	"encoding/hex"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
-	mathRand "math/rand"
+	"maps"
 	"net/http"
 	"net/url"
+	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/auth"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/log"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
 	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/util"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/version"
 )
 
 const (
-	// NonceLength defines the length of the cryptographic nonce used in
-	// authentication headers.
-	NonceLength = 64
-	// AuthCharset is the character set used for generating the nonce.
-	AuthCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	// ValidateAPIKeyEndpoint is the path for the API key validation
 	// endpoint.
 	ValidateAPIKeyEndpoint = "api_keys/validate"
@@ -45,7 +45,11 @@ const (
 type Client struct {
 	config     *config.Config
 	httpClient *http.Client
-	apiKeyId   string // String representation of ApiKeyId for headers
+	transport  http.RoundTripper // The base transport, shared with derived clients
+	retrier    retry.Policy
+	module     string         // The SDK module the client serves, used to label telemetry
+	clock      *clock         // The offset to the tenant's clock, shared with derived clients
+	secrets    secretRedactor // The configured transport redacting the API keys, if any
 
 	// testData and testIndex are for internal testing/mocking purposes.
 	testData  []*http.Response
@@ -86,15 +90,74 @@ func (c *Client) CrashStackDir() string { return c.config.CrashStackDir() }
 // LogLevel returns the log level.
 func (c *Client) LogLevel() string { return c.config.LogLevel() }
 
-// Logger returns the logger.
-func (c *Client) Logger() log.Logger { return c.config.Logger() }
+// Logger returns the logger of the SDK lines: the configured logger, behind
+// the configured log level, adding the request ID and module to every line.
+func (c *Client) Logger() log.Logger { return c.logger() }
+
+// logger returns the logger of the lines of the client.
+func (c *Client) logger() sdkLogger {
+	return sdkLogger{logger: c.config.Logger(), level: c.config.LogLevel(), module: c.module}
+}
 
 // SkipLoggingTransport returns whether to skip logging transport.
 func (c *Client) SkipLoggingTransport() bool { return c.config.SkipLoggingTransport() }
 
+// Profile returns the name of the configuration file profile the settings
+// were loaded from, or an empty string if no profile was loaded.
+func (c *Client) Profile() string { return c.config.Profile() }
+
+// ConfigSources returns where each setting came from ("default", "profile",
+// "env" or "option"), keyed by configuration file key.
+func (c *Client) ConfigSources() map[string]string {
+	sources := make(map[string]string)
+	for setting, source := range c.config.Sources() {
+		sources[setting] = string(source)
+	}
+	return sources
+}
+
+// ForModule returns a client that shares the configuration and connections of
+// c, labeling the telemetry of its calls with the given SDK module name.
+func (c *Client) ForModule(module string) *Client {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.module = module
+	return &clone
+}
+
+// Derive returns a client whose configuration is that of c with the given
+// options applied, for the same SDK module. Unless the options set another
+// transport or TLS and proxy settings, the derived client shares the
+// connections of c; like every
+// client built from a copy of the configuration, it also shares its logger,
+// rate limiter, circuit breaker and retry budget unless they are overridden.
+func (c *Client) Derive(opts ...config.Option) (*Client, error) {
+	var transport http.RoundTripper
+	if !config.ChangesTransport(opts...) {
+		transport = c.transport
+	}
+	derived, err := newClient(c.config.With(opts...), transport)
+	if err != nil {
+		return nil, err
+	}
+	derived.module = c.module
+	if derived.APIURL() == c.APIURL() {
+		derived.clock = c.clock
+	}
+	return derived, nil
+}
+
 // NewClientFromConfig creates and initializes a new core HTTP client from a config object.
 // It takes a pointer to a Config, which should be fully configured.
 func NewClientFromConfig(cfg *config.Config) (*Client, error) {
+	return newClient(cfg, nil)
+}
+
+// newClient creates a client from cfg on top of the given base transport, or
+// of one built from the transport settings of cfg if it is nil.
+func newClient(cfg *config.Config, transport http.RoundTripper) (*Client, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("received nil Config")
 	}
@@ -107,13 +170,10 @@ func NewClientFromConfig(cfg *config.Config) (*Client, error) {
 	cfg.SetDefaults()
 
 	// Set up the HTTP transport based on config
-	transport := cfg.Transport()
 	if transport == nil {
-		transport = &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: cfg.SkipSSLVerify(),
-			},
+		var err error
+		if transport, err = newTransport(cfg); err != nil {
+			return nil, fmt.Errorf("invalid API configuration: %w", err)
 		}
 	}
 
@@ -123,15 +183,43 @@ func NewClientFromConfig(cfg *config.Config) (*Client, error) {
 		Timeout:   time.Duration(cfg.Timeout()) * time.Second,
 	}
 
+	// Compress request bodies and decompress responses below the logging
+	// transport, so that debug dumps show readable bodies
+	if cfg.Compression() {
+		httpClient.Transport = NewCompressionTransport(httpClient.Transport, cfg.UncompressedEndpoints())
+	}
+
 	// Wrap transport with logging if not skipped
 	if !cfg.SkipLoggingTransport() {
-		httpClient.Transport = NewTransport(httpClient.Transport, &internalClientAdapter{cfg})
+		dump, err := newDumpPolicy(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API configuration: %w", err)
+		}
+		logging := NewTransport(httpClient.Transport, &internalClientAdapter{cfg})
+		logging.dump = dump
+		httpClient.Transport = logging
+	}
+
+	// Admit requests through the client-side rate limiter, if configured
+	if limiter := cfg.RateLimiter(); limiter != nil {
+		httpClient.Transport = NewRateLimitTransport(httpClient.Transport, limiter)
+	}
+
+	// Hand the API key to a configured transport that redacts it from what
+	// it keeps; those of a credentials provider are handed as they are
+	// retrieved
+	secrets, _ := cfg.Transport().(secretRedactor)
+	if secrets != nil && cfg.CortexAPIKey() != "" {
+		secrets.RedactSecrets(cfg.CortexAPIKey())
 	}
 
 	return &Client{
 		config:     cfg,
 		httpClient: httpClient,
-		apiKeyId:   strconv.Itoa(cfg.CortexAPIKeyID()),
+		transport:  transport,
+		retrier:    newRetryPolicy(cfg),
+		clock:      &clock{},
+		secrets:    secrets,
 	}, nil
 }
 
@@ -142,56 +230,21 @@ type internalClientAdapter struct {
 	cfg *config.Config
 }
 
-// logLevelStringToInt maps string log levels to an integer for comparison.
-// Higher integer means higher severity.
-// This helper is internal to the logging logic.
-func logLevelStringToInt(level string) int {
-	switch strings.ToLower(level) {
-	case "quiet":
-		return -1 // Represents "off"
-	case "error":
-		return 0
-	case "warn":
-		return 1
-	case "info":
-		return 2
-	case "debug":
-		return 3
-	default:
-		return -1 // Default to "off" for unknown configured levels
-	}
-}
-
 // LogLevelIsSetTo checks if the client's configured log level allows for a given specific level.
 // This method is primarily used by the transport layer to decide whether to dump detailed request/response.
 func (a *internalClientAdapter) LogLevelIsSetTo(v string) bool {
-	return logLevelStringToInt(a.cfg.LogLevel()) >= logLevelStringToInt(v)
+	return a.logger(context.Background()).enabled(v)
 }
 
 // Log writes the given message to the logger according to the configured LogLevel.
 func (a *internalClientAdapter) Log(ctx context.Context, level, msg string) {
-	if a.cfg.Logger() == nil {
-		return
-	}
-
-	configuredLevelInt := logLevelStringToInt(a.cfg.LogLevel())
-	msgLevelInt := logLevelStringToInt(level)
-
-	// Only log if the message's severity is greater than or equal to the configured minimum level
-	if msgLevelInt >= configuredLevelInt {
-		switch strings.ToLower(level) {
-		case "debug":
-			a.cfg.Logger().Debug(ctx, msg)
-		case "info":
-			a.cfg.Logger().Info(ctx, msg)
-		case "warn":
-			a.cfg.Logger().Warn(ctx, msg)
-		case "error":
-			a.cfg.Logger().Error(ctx, msg)
-		default:
-			a.cfg.Logger().Info(ctx, msg)
-		}
-	}
+	a.logger(ctx).log(ctx, strings.ToLower(level), msg, nil)
+}
+
+// logger returns the logger of the lines of the request made with ctx,
+// labeled with the module recorded in ctx.
+func (a *internalClientAdapter) logger(ctx context.Context) sdkLogger {
+	return sdkLogger{logger: a.cfg.Logger(), level: a.cfg.LogLevel(), module: moduleFromContext(ctx)}
 }
 
 // ValidateAPIKey validates the configured API Key against the target
@@ -204,21 +257,31 @@ func (c *Client) ValidateAPIKey(ctx context.Context) (bool, error) {
 	return (validateResp == "true"), nil
 }
 
-// generateHeaders creates all header key-value pairs for the current request
-// using the client's configuration.
-func (c *Client) generateHeaders(ctx context.Context, setContentType bool) (map[string]string, error) {
-	headers := make(map[string]string)
+// generateHeaders creates the headers of the current request attempt using
+// the client's configuration. The request is authenticated once it is built,
+// by authenticate.
+func (c *Client) generateHeaders(ctx context.Context, setContentType bool) http.Header {
+	headers := make(http.Header)
+
+	// Custom headers from the configuration and the call options; the
+	// headers set below take precedence over them
+	for k, v := range c.config.Headers() {
+		headers.Set(k, v)
+	}
+	for k, v := range getCallOptions(ctx).headers {
+		headers.Set(k, v)
+	}
 
 	if setContentType {
-		headers["Content-Type"] = "application/json"
+		headers.Set("Content-Type", "application/json")
 	}
 
 	// User-Agent: Use configured or generate default
 	if c.config.Agent() != "" {
-		headers["User-Agent"] = c.config.Agent()
+		headers.Set("User-Agent", c.config.Agent())
 	} else {
 		// Fallback to basic SDK User-Agent
-		headers["User-Agent"] = version.UserAgent("sdk")
+		headers.Set("User-Agent", version.UserAgent("sdk"))
 	}
 
 	// X-Request-ID: Generate or retrieve from context
@@ -226,62 +289,42 @@ func (c *Client) generateHeaders(ctx context.Context, setContentType bool) (map[
 	if requestID == "" {
 		requestID = generateRequestID()
 	}
-	headers["X-Request-ID"] = requestID
+	headers.Set("X-Request-ID", requestID)
 
-	headers["x-xdr-auth-id"] = c.apiKeyId
-
-	switch strings.ToLower(c.config.CortexAPIKeyType()) {
-	case "standard":
-		headers["Authorization"] = c.config.CortexAPIKey()
-	default:
-		// Generate nonce
-		nonceBytes := make([]byte, NonceLength)
-		if _, err := rand.Read(nonceBytes); err != nil {
-			return nil, fmt.Errorf("failed to generate nonce: %w", err)
-		}
-
-		var nonceBuilder strings.Builder
-		for _, b := range nonceBytes {
-			nonceBuilder.WriteByte(AuthCharset[b%byte(len(AuthCharset))])
-		}
-		nonce := nonceBuilder.String()
-
-		// Calculate Authorization hash
-		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-		authKey := fmt.Sprintf("%s%s%s", c.config.CortexAPIKey(), nonce, timestamp)
-		hasher := sha256.New()
-		hasher.Write([]byte(authKey))
-		apiKeyHash := hex.EncodeToString(hasher.Sum(nil))
-
-		// Set XDR authentication headers
-		headers["x-xdr-nonce"] = nonce
-		headers["x-xdr-timestamp"] = timestamp
-		headers["Authorization"] = apiKeyHash
-	}
-
-	return headers, nil
+	return headers
 }
 
-// calculateRetryDelay determines the sleep duration for retries using
-// exponential backoff with jitter, based on the client's configuration.
-func (c *Client) calculateRetryDelay(attempt int) time.Duration {
-	// Apply default if not configured
-	retryMaxDelay := c.config.RetryMaxDelay()
-	if retryMaxDelay == 0 {
-		retryMaxDelay = 60 // seconds
+// authenticate signs req, the HTTP request of the current attempt, with the
+// client's authenticator. The method, URL, body and other headers of req must
+// be final, since the authenticator may sign them.
+func (c *Client) authenticate(req *http.Request) error {
+	authenticator, err := c.authenticator()
+	if err != nil {
+		return err
 	}
+	return authenticator.Authenticate(req)
+}
 
-	// Exponential backoff: 2^attempt seconds, with jitter
-	baseDelay := time.Duration(1<<uint(attempt)) * time.Second
-	maxDelay := time.Duration(retryMaxDelay) * time.Second
-
-	if baseDelay > maxDelay {
-		baseDelay = maxDelay // Cap the delay at RetryMaxDelay
+// authenticator returns the authenticator of the next request attempt: the
+// configured one, or the one of the API key of the credentials provider or
+// of the configuration, signing with the tenant's time, which the local
+// clock may be off. Credentials without a key type have the one of the
+// configuration.
+func (c *Client) authenticator() (auth.Authenticator, error) {
+	if authenticator := c.config.Authenticator(); authenticator != nil {
+		return authenticator, nil
+	}
+	opts := []auth.Option{auth.WithClock(c.clock.now), auth.WithDefaultKeyType(c.config.CortexAPIKeyType())}
+	if provider := c.config.CredentialsProvider(); provider != nil {
+		if c.secrets != nil {
+			provider = redactingProvider{Provider: provider, redactor: c.secrets}
+		}
+		return auth.NewFromProvider(provider, opts...), nil
 	}
-
-	// Add jitter (±25% randomization) to prevent thundering herd problem
-	jitter := time.Duration(mathRand.Int63n(int64(baseDelay/2))) - baseDelay/4
-	return baseDelay + jitter
+	return auth.New(credentials.Credentials{
+		APIKey:   c.config.CortexAPIKey(),
+		APIKeyID: c.config.CortexAPIKeyID(),
+	}, opts...)
 }
 
 // buildRequestURL constructs and validates the complete API URL from
@@ -334,18 +377,10 @@ func (c *Client) buildRequestURL(endpoint string, pathParams *[]string, queryPar
 	return finalURLString, nil
 }
 
-// isRetryableHTTPStatus checks if the given HTTP status code indicates a retryable error.
+// isRetryableHTTPStatus checks if the given HTTP status code is retried by the
+// default retry policy.
 func isRetryableHTTPStatus(statusCode int) bool {
-	switch statusCode {
-	case http.StatusUnauthorized, // 401: Might be temporary token issue, retry once
-		http.StatusTooManyRequests,    // 429
-		http.StatusBadGateway,         // 502
-		http.StatusServiceUnavailable, // 503
-		http.StatusGatewayTimeout:     // 504
-		return true
-	default:
-		return false
-	}
+	return retry.IsRetryableStatus(statusCode)
 }
 
 // handleResponseStatus processes HTTP response status codes and returns a structured
@@ -359,6 +394,7 @@ func (c *Client) handleResponseStatus(ctx context.Context, statusCode int, body
 	unmarshalErr := json.Unmarshal(body, &apiError)
 
 	if unmarshalErr == nil && apiError.HasContent() {
+		apiError.HTTPStatus = statusCode
 		return &apiError
 	}
 
@@ -368,17 +404,21 @@ func (c *Client) handleResponseStatus(ctx context.Context, statusCode int, body
 	// status code and response body so the user always sees actionable
 	// diagnostic information.
 	if unmarshalErr != nil {
-		c.config.Logger().Error(ctx, fmt.Sprintf("Failed to unmarshal API error response (HTTP %d): %v, raw body: %s", statusCode, unmarshalErr, string(body)))
+		c.logger().Error(ctx, fmt.Sprintf("Failed to unmarshal API error response (HTTP %d): %v, raw body: %s", statusCode, unmarshalErr, string(body)))
 	} else {
-		c.config.Logger().Error(ctx, fmt.Sprintf("API error response (HTTP %d) did not match any known error format, raw body: %s", statusCode, string(body)))
+		c.logger().Error(ctx, fmt.Sprintf("API error response (HTTP %d) did not match any known error format, raw body: %s", statusCode, string(body)))
 	}
 	return &errors.CortexCloudAPIError{
-		Code:    types.ToPointer(fmt.Sprintf("HTTP_%d", statusCode)),
-		Message: types.ToPointer(fmt.Sprintf("API error (HTTP %d): %s", statusCode, string(body))),
+		Code:       types.ToPointer(fmt.Sprintf("HTTP_%d", statusCode)),
+		Message:    types.ToPointer(fmt.Sprintf("API error (HTTP %d): %s", statusCode, string(body))),
+		HTTPStatus: statusCode,
 	}
 }
 
 type DoOptions struct {
+	// Operation is the name of the SDK method making the call, e.g.
+	// "CreatePolicy". It names the call's span together with the module.
+	Operation           string
 	RequestWrapperKeys  []string
 	ResponseWrapperKeys []string
 }
@@ -386,9 +426,44 @@ type DoOptions struct {
 // Do performs the given API request.
 //
 // This is the core method for making authenticated HTTP calls to the Cortex Cloud
-// API. It returns the raw response body and a structured SDK error if any
-// error occurs.
+// API. It returns the raw response body and, if any error occurs, a
+// *errors.CortexCloudSdkError describing the call that failed. Unless
+// interceptors are configured, the body of a successful response is decoded
+// into output as it is read, and is not returned.
+//
+// The endpoint labels the telemetry of the call, so it must be the endpoint
+// constant: identifiers belong in pathParams.
 func (c *Client) Do(ctx context.Context, method string, endpoint string, pathParams *[]string, queryParams *url.Values, input, output any, opts *DoOptions) ([]byte, error) {
+	state := &callState{op: operation{module: c.module}}
+	if opts != nil {
+		state.op.name = opts.Operation
+	}
+
+	ctx, span := c.startCallSpan(ctx, state, method, endpoint)
+	start := time.Now()
+	body, err := c.doRecovering(ctx, state, method, endpoint, pathParams, queryParams, input, output, opts)
+	state.annotateError(err, method, endpoint)
+	endCallSpan(span, state, err)
+	if meta := getCallOptions(ctx).responseMeta; meta != nil {
+		*meta = state.responseMeta(time.Since(start))
+	}
+	return body, err
+}
+
+// doRecovering performs do, recovering from panics in the request and decode
+// paths, e.g. in the custom JSON methods of the input and output types. A
+// panic is reported in a diagnostic bundle and returned as an error.
+func (c *Client) doRecovering(ctx context.Context, state *callState, method string, endpoint string, pathParams *[]string, queryParams *url.Values, input, output any, opts *DoOptions) (body []byte, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			body, err = nil, c.panicError(ctx, state, method, endpoint, value, debug.Stack())
+		}
+	}()
+	return c.do(ctx, state, method, endpoint, pathParams, queryParams, input, output, opts)
+}
+
+// do performs the call for Do, recording its progress in state.
+func (c *Client) do(ctx context.Context, state *callState, method string, endpoint string, pathParams *[]string, queryParams *url.Values, input, output any, opts *DoOptions) ([]byte, error) {
 	if c.httpClient == nil {
 		return nil, errors.NewInternalSDKError(
 			errors.CodeSDKInitializationFailure,
@@ -397,21 +472,33 @@ func (c *Client) Do(ctx context.Context, method string, endpoint string, pathPar
 		)
 	}
 
+	// Apply the per-call overrides
+	call := getCallOptions(ctx)
+	if call.requestID != "" {
+		ctx = WithRequestID(ctx, call.requestID)
+	}
+	if call.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, call.timeout)
+		defer cancel()
+	}
+	retrier := c.retryPolicy(call)
+
 	// Ensure request ID is in context
 	ctx, requestID := GetOrGenerateRequestID(ctx)
+	state.requestID = requestID
 
 	// Log request start with request ID
-	c.config.Logger().Info(ctx, "API request started", map[string]any{
-		"request_id": requestID,
-		"method":     method,
-		"endpoint":   endpoint,
+	c.logger().Info(ctx, "API request started", map[string]any{
+		"method":   method,
+		"endpoint": endpoint,
 	})
 
 	var (
-		err  error
-		body []byte
-		data []byte
-		resp *http.Response
+		err        error
+		body       []byte
+		data       []byte
+		statusCode int
 	)
 
 	// Marshal input into JSON if present
@@ -433,11 +520,11 @@ func (c *Client) Do(ctx context.Context, method string, endpoint string, pathPar
 				err,
 			)
 		}
+		state.requestBody = data
 	}
 
-	// Build and validate the complete URL
-	requestURL, err := c.buildRequestURL(endpoint, pathParams, queryParams)
-	if err != nil {
+	// Validate the complete URL before the first attempt
+	if _, err := c.buildRequestURL(endpoint, pathParams, queryParams); err != nil {
 		return nil, errors.NewInternalSDKError(
 			errors.CodeURLConstructionFailure,
 			fmt.Sprintf("failed to build request URL: %v", err),
@@ -445,7 +532,31 @@ func (c *Client) Do(ctx context.Context, method string, endpoint string, pathPar
 		)
 	}
 
-	for attempt := 0; attempt <= c.config.MaxRetries(); attempt++ {
+	// Resolve the idempotency key once so that every attempt of this logical
+	// call carries the same value.
+	idempotencyKey := resolveIdempotencyKey(ctx)
+
+	// retryStart marks the first attempt so that the total time spent
+	// retrying can be reported if the request ultimately fails.
+	retryStart := time.Now()
+
+	// Circuit breaker probes report the tenant's state in a single attempt
+	probe := isCircuitProbe(ctx)
+
+	var wrapperKeys []string
+	if opts != nil {
+		wrapperKeys = opts.ResponseWrapperKeys
+	}
+
+	// refreshed records whether the credentials were already refreshed
+	// after the tenant rejected them
+	refreshed := false
+
+	// clockSkew is the signature timestamp error of the attempt rejected
+	// because of clock skew, which is signed again once with the tenant's time
+	var clockSkew time.Duration
+
+	for attempt := 0; ; attempt++ {
 		select {
 		case <-ctx.Done():
 			return nil, errors.NewInternalSDKError(
@@ -457,113 +568,169 @@ func (c *Client) Do(ctx context.Context, method string, endpoint string, pathPar
 			// Continue
 		}
 
-		// Handle test data if available (for internal SDK testing)
-		if len(c.testData) != 0 {
-			resp = c.testData[c.testIndex%len(c.testData)]
-			c.testIndex++
-		} else {
-			// Create new HTTP request with context
-			req, err := http.NewRequestWithContext(ctx, method, requestURL, strings.NewReader(string(data)))
-			if err != nil {
-				return nil, errors.NewInternalSDKError(
-					errors.CodeHTTPRequestCreationFailure,
-					fmt.Sprintf("failed to create HTTP request: %v", err),
-					err,
-				)
-			}
+		// Fail fast while the tenant is considered unavailable
+		if err := c.admitCircuit(ctx); err != nil {
+			return nil, err
+		}
 
-			// Generate authentication headers
-			authHeaders, err := c.generateHeaders(ctx, input != nil)
-			if err != nil {
-				return nil, errors.NewInternalSDKError(
-					errors.CodeAuthenticationHeaderGenerationFailure,
-					fmt.Sprintf("failed to generate request headers: %v", err),
-					err,
-				)
+		header := c.generateHeaders(ctx, input != nil)
+		if idempotencyKey != "" {
+			header.Set(IdempotencyKeyHeader, idempotencyKey)
+		}
+
+		// Send the attempt through the interceptor chain
+		req := &interceptor.Request{
+			Method:   method,
+			Endpoint: endpoint,
+			Body:     data,
+			Header:   header,
+			Attempt:  attempt,
+		}
+		if pathParams != nil {
+			req.PathParams = slices.Clone(*pathParams)
+		}
+		if queryParams != nil {
+			req.Query = maps.Clone(*queryParams)
+		}
+		var result attemptResult
+		if output != nil && len(c.config.Interceptors()) == 0 {
+			// Nothing else needs the body of a successful response; decode
+			// it as it is read, keeping its start for a panic's bundle
+			result.decode = func(resp *http.Response, body io.Reader) *errors.CortexCloudSdkError {
+				state.statusCode, state.header, state.responseBody = resp.StatusCode, resp.Header, nil
+				head := &headWriter{buf: &state.responseBody, max: crashSnippetSize}
+				return decodeResponse(io.TeeReader(body, head), wrapperKeys, output)
 			}
+		}
+		attemptCtx, attemptSpan := c.startAttemptSpan(ctx, req, requestID)
+		labels := metrics.Labels{Module: c.module, Endpoint: endpoint, Method: method}
+		c.config.Metrics().AddInFlight(labels, 1)
+		attemptStart := time.Now()
+		state.attempts++
+		resp, err := c.intercept(attemptCtx, req, c.send(call, &result))
+		c.config.Metrics().AddInFlight(labels, -1)
+		state.lastAttemptDuration = time.Since(attemptStart)
+		observeAttempt(c.config.Metrics(), labels, resp, state.lastAttemptDuration)
+		endAttemptSpan(attemptSpan, resp, err)
+		if resp != nil {
+			state.statusCode, state.header = resp.StatusCode, resp.Header
+		}
 
-			// Attach headers to request
-			for k, v := range authHeaders {
-				req.Header.Set(k, v)
+		if err != nil && resp == nil {
+			if err != result.transportErr {
+				// The attempt was cancelled, or an interceptor ended the call
+				return nil, err
 			}
 
-			// Execute HTTP request
-			resp, err = c.httpClient.Do(req)
-			if err != nil {
-				// Check for context cancellation after Do() call
-				if ctx.Err() != nil {
-					return nil, errors.NewInternalSDKError(
-						errors.CodeContextCancellation,
-						"request cancelled by context after HTTP client call",
-						ctx.Err(),
-					)
-				}
-				// Network or client-side errors (e.g., connection refused, timeout) are generally retryable
-				c.config.Logger().Debug(ctx, fmt.Sprintf("[ERROR] HTTP request failed (attempt %d/%d): %v", attempt+1, c.config.MaxRetries()+1, err), map[string]any{
-					"request_id":  requestID,
-					"attempt":     attempt + 1,
-					"max_retries": c.config.MaxRetries() + 1,
-				})
-				if attempt < c.config.MaxRetries() {
-					sleepDelay := c.calculateRetryDelay(attempt)
-					c.config.Logger().Debug(ctx, fmt.Sprintf("[INFO] Sleeping %v before retry", sleepDelay), map[string]any{
-						"request_id": requestID,
-						"delay_ms":   sleepDelay.Milliseconds(),
+			// Network or client-side errors (e.g., connection refused, timeout) are
+			// retryable, unless a non-idempotent request may already have been applied
+			c.logger().Debug(ctx, fmt.Sprintf("[ERROR] HTTP request failed (attempt %d): %v", attempt+1, result.err), map[string]any{
+				"attempt": attempt + 1,
+			})
+			failed := retry.Attempt{Number: attempt, Method: method, Err: result.err}
+			if !isSafeToRetry(failed, result.requestSent.Load(), idempotencyKey != "") {
+				return nil, errors.NewInternalSDKError(
+					errors.CodeNetworkError,
+					fmt.Sprintf("HTTP %s request failed after it was sent and was not retried because it is not idempotent: %v", method, result.err),
+					result.err,
+				)
+			}
+			// Probes must not spend the retry budget
+			if !probe {
+				if sleepDelay, ok := c.nextRetry(retrier, failed); ok {
+					c.logger().Debug(ctx, fmt.Sprintf("[INFO] Sleeping %v before retry", sleepDelay), map[string]any{
+						"delay_ms": sleepDelay.Milliseconds(),
 					})
-					if len(c.testData) == 0 { // Only sleep if not in test mode
-						time.Sleep(sleepDelay)
+					c.config.Metrics().ObserveRetry(labels, metrics.RetryReasonNetwork)
+					if err := c.waitForRetry(ctx, sleepDelay); err != nil {
+						return nil, retryCancelledError(err, attempt, retryStart)
 					}
 					continue
-				} else {
-					return nil, errors.NewInternalSDKError(
-						errors.CodeNetworkError,
-						fmt.Sprintf("HTTP request failed after %d retries: %v", c.config.MaxRetries(), err),
-						err,
-					)
 				}
 			}
-			if resp == nil {
-				return nil, errors.NewInternalSDKError(
-					errors.CodeNoResponseReceived,
-					"no HTTP response received",
-					nil,
-				)
-			}
+			return nil, errors.NewInternalSDKError(
+				errors.CodeNetworkError,
+				fmt.Sprintf("HTTP request failed after %d retries (%s spent retrying): %v", attempt, time.Since(retryStart).Round(time.Millisecond), result.err),
+				result.err,
+			)
 		}
-
-		// Read the response body content
-		body, err = io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
+		if resp == nil {
 			return nil, errors.NewInternalSDKError(
-				errors.CodeResponseBodyReadFailure,
-				fmt.Sprintf("failed to read response body: %v", err),
-				err,
+				errors.CodeNoResponseReceived,
+				"no HTTP response received",
+				nil,
 			)
 		}
+		body, statusCode = resp.Body, resp.StatusCode
+		if result.decode == nil || body != nil {
+			state.responseBody = body
+		}
 
-		// Handle the response status code and determine if a retry is needed
-		apiError := c.handleResponseStatus(ctx, resp.StatusCode, body)
-		if apiError != nil {
-			if isRetryableHTTPStatus(resp.StatusCode) && attempt < c.config.MaxRetries() {
-				c.config.Logger().Debug(ctx, fmt.Sprintf("[INFO] API returned retryable status %d; sleeping %v before retry (attempt %d/%d)", resp.StatusCode, c.calculateRetryDelay(attempt), attempt+1, c.config.MaxRetries()+1))
-				sleepDelay := c.calculateRetryDelay(attempt)
-				c.config.Logger().Debug(ctx, fmt.Sprintf("[INFO] API returned retryable status %d", resp.StatusCode), map[string]any{
-					"request_id":  requestID,
-					"status_code": resp.StatusCode,
-					"attempt":     attempt + 1,
-					"delay_ms":    sleepDelay.Milliseconds(),
-				})
+		// Handle the API error and determine if a retry is needed
+		if err != nil {
+			var apiError *errors.CortexCloudAPIError
+			if !stderrors.As(err, &apiError) {
+				// An interceptor replaced the API error, or the response could
+				// not be decoded as it was read; return the error as is
+				return body, err
+			}
 
-				// Skip sleeping between retries if we're in a test
-				if len(c.testData) == 0 {
-					time.Sleep(sleepDelay)
+			// The signature may have been rejected because the local clock is
+			// off; the response updated the offset, so sign again once. Only a
+			// signature still off the tenant's time after that is blamed on
+			// the clock: other 401s are handled like any other
+			if statusCode == http.StatusUnauthorized {
+				if skew := c.clock.signatureSkew(result.header); skew != 0 {
+					if clockSkew != 0 {
+						return body, clockSkewError(apiError, skew)
+					}
+					clockSkew = skew
+					c.logger().Warn(ctx, fmt.Sprintf("API rejected the request signature; the local clock is %v off the tenant's clock, signing again with the tenant's time", clockSkew.Round(time.Second)), map[string]any{
+						"attempt": attempt + 1,
+					})
+					c.config.Metrics().ObserveRetry(labels, strconv.Itoa(statusCode))
+					continue
 				}
+			}
+
+			// The credentials may have been rotated since they were loaded;
+			// expire them and retry once with fresh ones
+			if provider := c.config.CredentialsProvider(); provider != nil && statusCode == http.StatusUnauthorized && !refreshed {
+				refreshed = true
+				provider.Expire()
+				c.logger().Debug(ctx, "[INFO] API rejected the credentials; refreshing them before retry", map[string]any{
+					"attempt": attempt + 1,
+				})
+				c.config.Metrics().ObserveRetry(labels, strconv.Itoa(statusCode))
 				continue
-			} else {
-				// Non-retryable API error or max retries reached for a retryable status
-				return body, apiError
 			}
+
+			failed := retry.Attempt{
+				Number:   attempt,
+				Method:   method,
+				Response: &http.Response{StatusCode: resp.StatusCode, Header: resp.Header},
+			}
+			if !probe && isSafeToRetry(failed, true, idempotencyKey != "") {
+				if sleepDelay, ok := c.nextRetry(retrier, failed); ok {
+					c.logger().Debug(ctx, fmt.Sprintf("[INFO] API returned retryable status %d; sleeping %v before retry (attempt %d)", statusCode, sleepDelay, attempt+1), map[string]any{
+						"status_code": statusCode,
+						"attempt":     attempt + 1,
+						"delay_ms":    sleepDelay.Milliseconds(),
+					})
+
+					c.config.Metrics().ObserveRetry(labels, strconv.Itoa(statusCode))
+					if err := c.waitForRetry(ctx, sleepDelay); err != nil {
+						return body, retryCancelledError(err, attempt, retryStart)
+					}
+					continue
+				}
+			}
+			if attempt > 0 {
+				// The retry policy declined any further attempts
+				return body, retriesExhaustedError(apiError, statusCode, attempt, retryStart)
+			}
+			// Non-retryable API error
+			return body, err
 		}
 
 		// Exit the retry loop on success
@@ -571,44 +738,16 @@ func (c *Client) Do(ctx context.Context, method string, endpoint string, pathPar
 	}
 
 	// Log successful completion
-	c.config.Logger().Info(ctx, "API request completed", map[string]any{
-		"request_id":  requestID,
-		"status_code": resp.StatusCode,
+	c.logger().Info(ctx, "API request completed", map[string]any{
+		"status_code": statusCode,
 	})
 
-	// Unmarshal the response data into output if output is provided and response data exists
+	// Decode the response data into output if output is provided and response
+	// data exists, unless it was decoded as it was read
 	if output != nil && len(body) > 0 {
-		var dataToUnmarshal []byte = body
-		if opts != nil && len(opts.ResponseWrapperKeys) > 0 {
-			var currentData json.RawMessage = body
-			for _, key := range opts.ResponseWrapperKeys {
-				var wrapper map[string]json.RawMessage
-				if err := json.Unmarshal(currentData, &wrapper); err != nil {
-					return body, errors.NewInternalSDKError(
-						errors.CodeResponseDeserializationFailure,
-						fmt.Sprintf("failed to unmarshal response wrapper for key '%s': %v", key, err),
-						err,
-					)
-				}
-				var ok bool
-				currentData, ok = wrapper[key]
-				if !ok {
-					return body, errors.NewInternalSDKError(
-						errors.CodeResponseDeserializationFailure,
-						fmt.Sprintf("response wrapper key '%s' not found", key),
-						nil,
-					)
-				}
-			}
-			dataToUnmarshal = currentData
-		}
-		if err = json.Unmarshal(dataToUnmarshal, output); err != nil {
-			// If unmarshaling fails, return the raw body and a structured unmarshaling error
-			return body, errors.NewInternalSDKError(
-				errors.CodeResponseDeserializationFailure,
-				fmt.Sprintf("failed to unmarshal response body into output type: %v", err),
-				err,
-			)
+		if err := decodeResponse(bytes.NewReader(body), wrapperKeys, output); err != nil {
+			// If decoding fails, return the raw body and a structured decoding error
+			return body, err
 		}
 	}
 
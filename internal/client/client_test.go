@@ -8,12 +8,16 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewClient(t *testing.T) {
@@ -50,6 +54,33 @@ func TestNewClient(t *testing.T) {
 	})
 }
 
+func TestDerive(t *testing.T) {
+	base, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL("https://api.example.com"),
+		config.WithCortexAPIKey("test-key"),
+		config.WithCortexAPIKeyID(123),
+		config.WithTransport(nil),
+		config.WithHeaders(map[string]string{"X-Base": "1"}),
+	))
+	assert.NoError(t, err)
+	base = base.ForModule("cloudsec")
+
+	derived, err := base.Derive(
+		config.WithTimeout(120),
+		config.WithHeaders(map[string]string{"X-Derived": "1"}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "cloudsec", derived.module)
+	assert.Same(t, base.transport, derived.transport)
+	assert.Equal(t, 120*time.Second, derived.Timeout())
+	assert.Equal(t, 30*time.Second, base.Timeout())
+	assert.Equal(t, map[string]string{"X-Base": "1", "X-Derived": "1"}, derived.config.Headers())
+	assert.Equal(t, map[string]string{"X-Base": "1"}, base.config.Headers())
+
+	_, err = base.Derive(config.WithCortexAPIKey(""))
+	assert.ErrorContains(t, err, "API key not set")
+}
+
 func TestGenerateHeaders(t *testing.T) {
 	cfg := config.NewConfig(
 		config.WithCortexAPIURL("https://api.example.com"),
@@ -62,25 +93,30 @@ func TestGenerateHeaders(t *testing.T) {
 
 	t.Run("should generate headers with content type", func(t *testing.T) {
 		ctx := context.Background()
-		headers, err := client.generateHeaders(ctx, true)
-		assert.NoError(t, err)
-		assert.Equal(t, "application/json", headers["Content-Type"])
-		assert.Equal(t, "test-agent", headers["User-Agent"])
-		assert.Equal(t, "1", headers["x-xdr-auth-id"])
-		assert.NotEmpty(t, headers["x-xdr-nonce"])
-		assert.NotEmpty(t, headers["x-xdr-timestamp"])
-		assert.NotEmpty(t, headers["Authorization"])
-		assert.NotEmpty(t, headers["X-Request-ID"])
-		assert.True(t, strings.HasPrefix(headers["X-Request-ID"], "req_"))
+		headers := client.generateHeaders(ctx, true)
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+		assert.Equal(t, "test-agent", headers.Get("User-Agent"))
+		assert.NotEmpty(t, headers.Get("X-Request-ID"))
+		assert.True(t, strings.HasPrefix(headers.Get("X-Request-ID"), "req_"))
+	})
+
+	t.Run("should authenticate the request with the generated headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/test", nil)
+		req.Header = client.generateHeaders(context.Background(), true)
+		require.NoError(t, client.authenticate(req))
+		assert.Equal(t, "1", req.Header.Get("x-xdr-auth-id"))
+		assert.NotEmpty(t, req.Header.Get("x-xdr-nonce"))
+		assert.NotEmpty(t, req.Header.Get("x-xdr-timestamp"))
+		assert.NotEmpty(t, req.Header.Get("Authorization"))
+		assert.Equal(t, "test-agent", req.Header.Get("User-Agent"))
 	})
 
 	t.Run("should generate headers without content type", func(t *testing.T) {
 		ctx := context.Background()
-		headers, err := client.generateHeaders(ctx, false)
-		assert.NoError(t, err)
-		assert.NotContains(t, headers, "Content-Type")
-		assert.Equal(t, "test-agent", headers["User-Agent"])
-		assert.NotEmpty(t, headers["X-Request-ID"])
+		headers := client.generateHeaders(ctx, false)
+		assert.Empty(t, headers.Get("Content-Type"))
+		assert.Equal(t, "test-agent", headers.Get("User-Agent"))
+		assert.NotEmpty(t, headers.Get("X-Request-ID"))
 	})
 
 	t.Run("should use request ID from context if present", func(t *testing.T) {
@@ -88,9 +124,8 @@ func TestGenerateHeaders(t *testing.T) {
 		expectedID := "req_test123"
 		ctx = WithRequestID(ctx, expectedID)
 
-		headers, err := client.generateHeaders(ctx, true)
-		assert.NoError(t, err)
-		assert.Equal(t, expectedID, headers["X-Request-ID"])
+		headers := client.generateHeaders(ctx, true)
+		assert.Equal(t, expectedID, headers.Get("X-Request-ID"))
 	})
 
 	t.Run("should use default User-Agent when not configured", func(t *testing.T) {
@@ -102,10 +137,9 @@ func TestGenerateHeaders(t *testing.T) {
 		clientNoAgent, _ := NewClientFromConfig(cfgNoAgent)
 		ctx := context.Background()
 
-		headers, err := clientNoAgent.generateHeaders(ctx, true)
-		assert.NoError(t, err)
-		assert.Contains(t, headers["User-Agent"], "cortex-cloud-go/")
-		assert.Contains(t, headers["User-Agent"], "(sdk;")
+		headers := clientNoAgent.generateHeaders(ctx, true)
+		assert.Contains(t, headers.Get("User-Agent"), "cortex-cloud-go/")
+		assert.Contains(t, headers.Get("User-Agent"), "(sdk;")
 	})
 }
 
@@ -153,7 +187,7 @@ func TestBuildRequestURL(t *testing.T) {
 }
 
 func TestIsRetryableHTTPStatus(t *testing.T) {
-	assert.True(t, isRetryableHTTPStatus(http.StatusUnauthorized))
+	assert.False(t, isRetryableHTTPStatus(http.StatusUnauthorized))
 	assert.True(t, isRetryableHTTPStatus(http.StatusTooManyRequests))
 	assert.True(t, isRetryableHTTPStatus(http.StatusBadGateway))
 	assert.True(t, isRetryableHTTPStatus(http.StatusServiceUnavailable))
@@ -335,3 +369,39 @@ func TestDo(t *testing.T) {
 		assert.Equal(t, expectedID, GetRequestID(ctx))
 	})
 }
+
+func TestDo_SentinelErrors(t *testing.T) {
+	cfg := config.NewConfig(
+		config.WithCortexAPIURL("https://testing.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(0),
+	)
+
+	for status, sentinel := range map[int]error{
+		http.StatusNotFound:        errors.ErrNotFound,
+		http.StatusForbidden:       errors.ErrForbidden,
+		http.StatusConflict:        errors.ErrConflict,
+		http.StatusTooManyRequests: errors.ErrRateLimited,
+	} {
+		client, _ := NewClientFromConfig(cfg)
+		client.testData = []*http.Response{{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(`{"reply":{"err_code":500,"err_msg":"failed"}}`)),
+		}}
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, sentinel, "HTTP %d", status)
+	}
+
+	client, _ := NewClientFromConfig(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.ErrCanceled)
+	assert.NotErrorIs(t, err, errors.ErrTimeout)
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+	assert.ErrorIs(t, err, errors.ErrTimeout)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/auth"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+)
+
+const (
+	// minClockSkew is the smallest offset applied to the local clock. The
+	// Date header only has a precision of one second, so smaller offsets
+	// are measurement noise.
+	minClockSkew = 2 * time.Second
+
+	// clockSkewThreshold is the signature timestamp error beyond which a
+	// 401 response is attributed to clock skew rather than to the key.
+	clockSkewThreshold = time.Minute
+)
+
+// clock tracks the offset between the local clock and the tenant's, as
+// measured from the Date header of its responses, so that advanced API key
+// signatures carry the tenant's time even on hosts whose clock drifts.
+type clock struct {
+	offset atomic.Int64 // The duration added to the local time
+}
+
+// now returns the current time of the tenant's clock.
+func (c *clock) now() time.Time {
+	return time.Now().Add(time.Duration(c.offset.Load()))
+}
+
+// observe updates the offset from the Date header of a response to a request
+// sent at sent and received at received.
+func (c *clock) observe(date string, sent, received time.Time) {
+	if date == "" {
+		return
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// The tenant stamped the response somewhere between sending and
+	// receiving, within the second truncated by the Date header
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := serverTime.Add(500 * time.Millisecond).Sub(local)
+	if offset.Abs() < minClockSkew {
+		offset = 0
+	}
+	c.offset.Store(int64(offset))
+}
+
+// signatureSkew returns how far the timestamp an advanced API key signature
+// was computed with is off the tenant's current time, or 0 if the request
+// was not signed with a timestamp or its error is below clockSkewThreshold.
+func (c *clock) signatureSkew(header http.Header) time.Duration {
+	signed, err := strconv.ParseInt(header.Get(auth.HeaderTimestamp), 10, 64)
+	if err != nil {
+		return 0
+	}
+	skew := c.now().Sub(time.UnixMilli(signed))
+	if skew.Abs() < clockSkewThreshold {
+		return 0
+	}
+	return skew
+}
+
+// clockSkewError returns the error of a call whose signature the tenant
+// rejected again after it was signed again with the corrected time, when
+// that signature was still skew away from the tenant's time.
+func clockSkewError(apiError error, skew time.Duration) *errors.CortexCloudSdkError {
+	statusCode := http.StatusUnauthorized
+	return errors.NewCortexCloudSdkError(
+		errors.CodeClockSkew,
+		fmt.Sprintf("API rejected the request signature; the local clock is %v off the tenant's clock, synchronize it (e.g. with NTP)", skew.Round(time.Second)),
+		nil,
+		&statusCode,
+		fmt.Errorf("%w: %w", errors.ErrClockSkew, apiError),
+	)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/auth"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClock_Observe(t *testing.T) {
+	sent := time.Now()
+	received := sent.Add(200 * time.Millisecond)
+
+	t.Run("should measure the offset to the tenant's clock", func(t *testing.T) {
+		var c clock
+		c.observe(received.Add(10*time.Minute).UTC().Format(http.TimeFormat), sent, received)
+		assert.InDelta(t, float64(10*time.Minute), float64(time.Duration(c.offset.Load())), float64(time.Second))
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), c.now(), 2*time.Second)
+	})
+
+	t.Run("should ignore offsets below the Date precision", func(t *testing.T) {
+		var c clock
+		c.offset.Store(int64(time.Hour))
+		c.observe(received.UTC().Format(http.TimeFormat), sent, received)
+		assert.Zero(t, c.offset.Load())
+	})
+
+	t.Run("should ignore missing or invalid dates", func(t *testing.T) {
+		var c clock
+		c.offset.Store(int64(time.Hour))
+		c.observe("", sent, received)
+		c.observe("yesterday", sent, received)
+		assert.Equal(t, int64(time.Hour), c.offset.Load())
+	})
+}
+
+// newSkewedServer returns a server whose clock is offset from the local one,
+// rejecting the requests whose signature timestamp is more than 30 seconds
+// off its time unless accept is false, in which case it rejects them all.
+func newSkewedServer(t *testing.T, offset time.Duration, accept bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		now := time.Now().Add(offset)
+		w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+
+		signed, _ := strconv.ParseInt(r.Header.Get("x-xdr-timestamp"), 10, 64)
+		if !accept || now.Sub(time.UnixMilli(signed)).Abs() > 30*time.Second {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"reply":{"err_code":401,"err_msg":"Public API request unauthorized"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"reply":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestDo_ClockSkew(t *testing.T) {
+	t.Run("should sign again with the tenant's time", func(t *testing.T) {
+		server, requests := newSkewedServer(t, 10*time.Minute, true)
+		c := newTestClient(t, server.URL, config.WithRetryMaxDelay(0))
+
+		_, err := c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+
+		// The offset is now known, so the next call is signed right away
+		_, err = c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), requests.Load())
+
+		derived, err := c.Derive(config.WithTimeout(5))
+		require.NoError(t, err)
+		assert.Same(t, c.clock, derived.clock)
+	})
+
+	t.Run("should return a clock skew error if the signature stays off the tenant's time", func(t *testing.T) {
+		server, requests := newSkewedServer(t, -time.Hour, true)
+		// The authenticator signs with the local time, which is never corrected
+		c := newTestClient(t, server.URL, config.WithRetryMaxDelay(0), config.WithAuthenticator(auth.Advanced{APIKey: "key", APIKeyID: 1}))
+
+		_, err := c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, errors.ErrClockSkew)
+		assert.ErrorContains(t, err, "the local clock is -1h0m0s off the tenant's clock")
+		var apiError *errors.CortexCloudAPIError
+		assert.True(t, stderrors.As(err, &apiError))
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("should return the 401 if signing with the tenant's time fails", func(t *testing.T) {
+		server, requests := newSkewedServer(t, -time.Hour, false)
+		c := newTestClient(t, server.URL, config.WithRetryMaxDelay(0))
+
+		_, err := c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, errors.ErrUnauthorized)
+		assert.NotErrorIs(t, err, errors.ErrClockSkew)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("should not retry a 401 without clock skew", func(t *testing.T) {
+		server, requests := newSkewedServer(t, 0, false)
+		c := newTestClient(t, server.URL, config.WithRetryMaxDelay(0))
+
+		_, err := c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, errors.ErrClockSkew)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
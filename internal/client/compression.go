@@ -0,0 +1,178 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// gzipMinSize is the smallest request body worth compressing.
+	gzipMinSize = 1024
+
+	// endpointKey is the context key for the SDK endpoint of a request.
+	endpointKey contextKey = "cortex-endpoint"
+)
+
+// withEndpoint returns a context that records the SDK endpoint of the
+// request made with it, for the transport layers.
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey, endpoint)
+}
+
+// endpointFromContext returns the SDK endpoint recorded in ctx, if any.
+func endpointFromContext(ctx context.Context) string {
+	endpoint, _ := ctx.Value(endpointKey).(string)
+	return endpoint
+}
+
+// compressionTransport gzips request bodies and negotiates gzip-encoded
+// responses, decompressing them before they reach the outer transports.
+//
+// Endpoints that answer a compressed request with 415 Unsupported Media Type
+// are retried once uncompressed and never compressed again.
+type compressionTransport struct {
+	transport    http.RoundTripper
+	uncompressed map[string]bool // endpoints configured not to be compressed
+	rejected     sync.Map        // endpoints that rejected compressed bodies
+}
+
+// NewCompressionTransport wraps t so that request bodies are gzipped, except
+// for the given endpoints, and gzip-encoded responses are decompressed.
+func NewCompressionTransport(t http.RoundTripper, uncompressedEndpoints []string) *compressionTransport {
+	uncompressed := make(map[string]bool, len(uncompressedEndpoints))
+	for _, endpoint := range uncompressedEndpoints {
+		uncompressed[normalizeEndpoint(endpoint)] = true
+	}
+	return &compressionTransport{transport: t, uncompressed: uncompressed}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.roundTrip(req, true)
+}
+
+// roundTrip sends req, gzipping its body if compress is true and its endpoint
+// accepts compressed bodies.
+func (t *compressionTransport) roundTrip(req *http.Request, compress bool) (*http.Response, error) {
+	endpoint := normalizeEndpoint(endpointFromContext(req.Context()))
+
+	out := req.Clone(req.Context())
+	compressed := false
+	if compress && t.shouldCompress(req, endpoint) {
+		if err := gzipRequestBody(out); err != nil {
+			return nil, err
+		}
+		compressed = true
+	}
+
+	negotiated := out.Header.Get("Accept-Encoding") == ""
+	if negotiated {
+		out.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := t.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if compressed && resp.StatusCode == http.StatusUnsupportedMediaType && req.GetBody != nil {
+		// The endpoint does not accept compressed bodies; remember it, if
+		// the request names it, and send the request again as is
+		if endpoint != "" {
+			t.rejected.Store(endpoint, true)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry := req.Clone(req.Context())
+		retry.Body = body
+		return t.roundTrip(retry, false)
+	}
+
+	if negotiated && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if err := gunzipResponseBody(resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// shouldCompress reports whether the body of req should be gzipped.
+func (t *compressionTransport) shouldCompress(req *http.Request, endpoint string) bool {
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength < gzipMinSize {
+		return false
+	}
+	if req.Header.Get("Content-Encoding") != "" || t.uncompressed[endpoint] {
+		return false
+	}
+	_, rejected := t.rejected.Load(endpoint)
+	return !rejected
+}
+
+// gzipRequestBody replaces the body of req with its gzipped form.
+func gzipRequestBody(req *http.Request) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, req.Body)
+	req.Body.Close()
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	compressed := buf.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// gunzipResponseBody replaces the body of resp with its decompressed form,
+// mirroring what net/http does for transparently negotiated gzip.
+func gunzipResponseBody(resp *http.Response) error {
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("failed to decompress response body: %w", err)
+	}
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody reads a decompressed response body and closes the underlying one.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// normalizeEndpoint strips the slashes around endpoint so that endpoint
+// constants and configured values compare equal.
+func normalizeEndpoint(endpoint string) string {
+	return strings.Trim(endpoint, "/")
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compressionServer records the requests it receives, decompressing their
+// bodies, and answers with a gzip-encoded body when the client accepts it.
+type compressionServer struct {
+	mu       sync.Mutex
+	encoding []string
+	bodies   []string
+	reject   bool
+}
+
+func (s *compressionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		if s.reject {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	data, _ := io.ReadAll(body)
+
+	s.mu.Lock()
+	s.encoding = append(s.encoding, r.Header.Get("Content-Encoding"))
+	s.bodies = append(s.bodies, string(data))
+	s.mu.Unlock()
+
+	reply := []byte(`{"reply":"` + strings.Repeat("y", 2048) + `"}`)
+	if r.Header.Get("Accept-Encoding") == "gzip" {
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write(reply)
+		_ = zw.Close()
+		return
+	}
+	_, _ = w.Write(reply)
+}
+
+func doCompressed(t *testing.T, rt http.RoundTripper, url, endpoint, body string) string {
+	t.Helper()
+	ctx := withEndpoint(context.Background(), endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/"+endpoint, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCompressionTransport(t *testing.T) {
+	large := `{"ids":"` + strings.Repeat("x", 4096) + `"}`
+
+	t.Run("should compress large bodies and decompress responses", func(t *testing.T) {
+		srv := &compressionServer{}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+		rt := NewCompressionTransport(http.DefaultTransport, nil)
+
+		reply := doCompressed(t, rt, server.URL, "public_api/v1/search", large)
+		assert.True(t, strings.HasPrefix(reply, `{"reply":"yyy`))
+
+		doCompressed(t, rt, server.URL, "public_api/v1/search", `{"small":true}`)
+		assert.Equal(t, []string{"gzip", ""}, srv.encoding)
+		assert.Equal(t, []string{large, `{"small":true}`}, srv.bodies)
+	})
+
+	t.Run("should not compress excluded endpoints", func(t *testing.T) {
+		srv := &compressionServer{}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+		rt := NewCompressionTransport(http.DefaultTransport, []string{"/public_api/v1/plain/"})
+
+		doCompressed(t, rt, server.URL, "public_api/v1/plain", large)
+		assert.Equal(t, []string{""}, srv.encoding)
+	})
+
+	t.Run("should fall back to uncompressed bodies on 415", func(t *testing.T) {
+		srv := &compressionServer{reject: true}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+		rt := NewCompressionTransport(http.DefaultTransport, nil)
+
+		doCompressed(t, rt, server.URL, "public_api/v1/search", large)
+		doCompressed(t, rt, server.URL, "public_api/v1/search", large)
+		assert.Equal(t, []string{"", ""}, srv.encoding)
+		assert.Equal(t, []string{large, large}, srv.bodies)
+	})
+
+	t.Run("should not remember a 415 for requests without an endpoint", func(t *testing.T) {
+		rejecting := &compressionServer{reject: true}
+		rejectingServer := httptest.NewServer(rejecting)
+		defer rejectingServer.Close()
+		accepting := &compressionServer{}
+		acceptingServer := httptest.NewServer(accepting)
+		defer acceptingServer.Close()
+		rt := NewCompressionTransport(http.DefaultTransport, nil)
+
+		doCompressed(t, rt, rejectingServer.URL, "", large)
+		assert.Equal(t, []string{""}, rejecting.encoding)
+		assert.Equal(t, []string{large}, rejecting.bodies)
+
+		doCompressed(t, rt, acceptingServer.URL, "", large)
+		assert.Equal(t, []string{"gzip"}, accepting.encoding)
+	})
+}
+
+func TestCompressionTransport_DebugDumpShowsDecompressedBodies(t *testing.T) {
+	srv := &compressionServer{}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	logs := &fakeInternalClient{debug: true}
+	rt := NewTransport(NewCompressionTransport(http.DefaultTransport, nil), logs)
+
+	large := `{"ids":"` + strings.Repeat("x", 4096) + `"}`
+	reply := doCompressed(t, rt, server.URL, "public_api/v1/search", large)
+	assert.True(t, strings.HasPrefix(reply, `{"reply":"yyy`))
+
+	// The logging transport pretty-prints the readable JSON bodies.
+	dump := logs.joined()
+	assert.True(t, strings.Contains(dump, `"ids": "xxx`), "request body is not readable in the dump")
+	assert.True(t, strings.Contains(dump, `"reply": "yyy`), "response body is not readable in the dump")
+	assert.Equal(t, []string{large}, srv.bodies)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/version"
+)
+
+// crashSnippetSize is the maximum size of the request and response bodies in
+// a diagnostic bundle, in bytes.
+const crashSnippetSize = 4096
+
+// crashBundle is the diagnostic bundle written to the crash stack directory
+// when a call panics.
+type crashBundle struct {
+	Time     time.Time         `json:"time"`
+	Panic    string            `json:"panic"`
+	Stack    string            `json:"stack"`
+	Version  map[string]string `json:"version"`
+	Request  crashRequest      `json:"request"`
+	Response *crashResponse    `json:"response,omitempty"`
+	Config   map[string]any    `json:"config"`
+}
+
+type crashRequest struct {
+	ID       string `json:"id"`
+	Module   string `json:"module,omitempty"`
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	Attempts int    `json:"attempts"`
+	Body     string `json:"body,omitempty"`
+}
+
+type crashResponse struct {
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body,omitempty"`
+}
+
+// panicError returns the error of a call that panicked with value, after
+// writing its diagnostic bundle to the crash stack directory, unless crash
+// bundles are skipped.
+func (c *Client) panicError(ctx context.Context, state *callState, method, endpoint string, value any, stack []byte) *errors.CortexCloudSdkError {
+	panicErr := &errors.PanicError{Value: value, Stack: stack}
+	if c.config.SkipCrashBundle() {
+		c.logger().Error(ctx, fmt.Sprintf("Recovered from SDK panic: %v", value))
+	} else if path, err := c.writeCrashBundle(state, method, endpoint, value, stack); err != nil {
+		c.logger().Error(ctx, fmt.Sprintf("Recovered from SDK panic: %v; failed to write diagnostic bundle: %v", value, err))
+	} else {
+		panicErr.BundlePath = path
+		c.logger().Error(ctx, fmt.Sprintf("Recovered from SDK panic: %v; diagnostic bundle written to %s", value, path))
+	}
+	return errors.NewInternalSDKError(
+		errors.CodePanic,
+		fmt.Sprintf("%s %s failed: %v", method, endpoint, panicErr),
+		panicErr,
+	)
+}
+
+// writeCrashBundle writes the diagnostic bundle of a panic to the crash
+// stack directory and returns its path. Bodies are redacted as in debug
+// dumps, and the configuration summary holds no credentials.
+func (c *Client) writeCrashBundle(state *callState, method, endpoint string, value any, stack []byte) (string, error) {
+	policy, err := newDumpPolicy(c.config)
+	if err != nil {
+		policy = defaultDumpPolicy()
+	}
+	policy.maxSize, policy.sampler = crashSnippetSize, nil
+
+	bundle := crashBundle{
+		Time:    time.Now().UTC(),
+		Panic:   fmt.Sprint(value),
+		Stack:   string(stack),
+		Version: version.Info(),
+		Request: crashRequest{
+			ID:       state.requestID,
+			Module:   c.module,
+			Method:   method,
+			Endpoint: endpoint,
+			Attempts: state.attempts,
+		},
+		Config: c.configSummary(),
+	}
+	if len(state.requestBody) > 0 {
+		bundle.Request.Body = policy.formatBody(state.requestBody)
+	}
+	if state.statusCode != 0 {
+		bundle.Response = &crashResponse{StatusCode: state.statusCode}
+		if len(state.responseBody) > 0 {
+			bundle.Response.Body = policy.formatBody(state.responseBody)
+		}
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	dir := c.config.CrashStackDir()
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	// CreateTemp creates the file readable by its owner only
+	file, err := os.CreateTemp(dir, fmt.Sprintf("cortex-cloud-go-crash-%s-*.json", bundle.Time.Format("20060102T150405")))
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return "", err
+	}
+	return file.Name(), file.Close()
+}
+
+// configSummary returns the settings of the client relevant to diagnosing a
+// panic, with their sources. Credentials, headers and the proxy URL, which
+// may hold credentials, are left out.
+func (c *Client) configSummary() map[string]any {
+	cfg := c.config
+	return map[string]any{
+		"api_url":           cfg.CortexAPIURL(),
+		"api_key_type":      cfg.CortexAPIKeyType(),
+		"profile":           cfg.Profile(),
+		"config_file":       cfg.ConfigFile(),
+		"timeout":           cfg.Timeout(),
+		"max_retries":       cfg.MaxRetries(),
+		"retry_max_delay":   cfg.RetryMaxDelay(),
+		"max_response_size": cfg.MaxResponseSize(),
+		"compression":       cfg.Compression(),
+		"skip_ssl_verify":   cfg.SkipSSLVerify(),
+		"log_level":         cfg.LogLevel(),
+		"interceptors":      len(cfg.Interceptors()),
+		"sources":           c.ConfigSources(),
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// panickingInput panics when it is marshaled.
+type panickingInput struct{}
+
+func (panickingInput) MarshalJSON() ([]byte, error) { panic("marshal boom") }
+
+// panickingOutput panics when it is unmarshaled.
+type panickingOutput struct{}
+
+func (*panickingOutput) UnmarshalJSON([]byte) error { panic("unmarshal boom") }
+
+// newCrashServer returns the URL of a server answering with a body holding a
+// redacted field.
+func newCrashServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"reply":{"name":"syslog","certificate_content":"-----BEGIN CERTIFICATE-----"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func readCrashBundle(t *testing.T, err error) (*errors.PanicError, crashBundle, string) {
+	t.Helper()
+	var panicErr *errors.PanicError
+	require.True(t, stderrors.As(err, &panicErr), "error %v is not a PanicError", err)
+	require.NotEmpty(t, panicErr.BundlePath)
+	assert.Contains(t, err.Error(), panicErr.BundlePath)
+
+	data, readErr := os.ReadFile(panicErr.BundlePath)
+	require.NoError(t, readErr)
+	var bundle crashBundle
+	require.NoError(t, json.Unmarshal(data, &bundle))
+	return panicErr, bundle, string(data)
+}
+
+func TestDo_RecoversPanics(t *testing.T) {
+	t.Run("should recover a panic marshaling the input", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "crashes")
+		client := newTestClient(t, newCrashServer(t), config.WithCrashStackDir(dir), config.WithLogLevel("quiet")).ForModule("platform")
+
+		_, err := client.Do(context.Background(), http.MethodPost, "settings", nil, nil, panickingInput{}, nil, nil)
+		panicErr, bundle, _ := readCrashBundle(t, err)
+
+		assert.Equal(t, "marshal boom", panicErr.Value)
+		assert.Equal(t, dir, filepath.Dir(panicErr.BundlePath))
+		assert.Equal(t, "marshal boom", bundle.Panic)
+		assert.Contains(t, bundle.Stack, "MarshalJSON")
+		assert.Equal(t, "settings", bundle.Request.Endpoint)
+		assert.Equal(t, "platform", bundle.Request.Module)
+		assert.Zero(t, bundle.Request.Attempts)
+		assert.Nil(t, bundle.Response)
+	})
+
+	t.Run("should recover a panic decoding the response", func(t *testing.T) {
+		client := newTestClient(t, newCrashServer(t), config.WithCortexAPIKey("super-secret-api-key"), config.WithCrashStackDir(t.TempDir()), config.WithLogLevel("quiet"))
+
+		var output panickingOutput
+		body, err := client.Do(context.Background(), http.MethodPost, "settings", nil, nil, map[string]any{"password": "hunter2"}, &output, nil)
+		assert.Nil(t, body)
+		_, bundle, raw := readCrashBundle(t, err)
+
+		assert.Equal(t, 1, bundle.Request.Attempts)
+		assert.NotEmpty(t, bundle.Request.ID)
+		require.NotNil(t, bundle.Response)
+		assert.Equal(t, http.StatusOK, bundle.Response.StatusCode)
+		assert.Contains(t, bundle.Response.Body, "syslog")
+		assert.Equal(t, version.SDKVersion, bundle.Version["sdk_version"])
+		assert.Equal(t, "option", bundle.Config["sources"].(map[string]any)["crash_stack_dir"])
+
+		// Credentials and sensitive fields are redacted
+		assert.NotContains(t, raw, "hunter2")
+		assert.NotContains(t, raw, "BEGIN CERTIFICATE")
+		assert.NotContains(t, raw, "super-secret-api-key")
+	})
+
+	t.Run("should return the panic when the bundle cannot be written", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+		client := newTestClient(t, newCrashServer(t), config.WithCrashStackDir(file), config.WithLogLevel("quiet"))
+
+		_, err := client.Do(context.Background(), http.MethodPost, "settings", nil, nil, panickingInput{}, nil, nil)
+		var panicErr *errors.PanicError
+		require.True(t, stderrors.As(err, &panicErr))
+		assert.Empty(t, panicErr.BundlePath)
+		assert.Contains(t, err.Error(), "SDK panic: marshal boom")
+	})
+
+	t.Run("should write the bundle to the temporary directory by default", func(t *testing.T) {
+		client := newTestClient(t, newCrashServer(t), config.WithLogLevel("quiet"))
+		require.Equal(t, os.TempDir(), client.CrashStackDir())
+
+		_, err := client.Do(context.Background(), http.MethodPost, "settings", nil, nil, panickingInput{}, nil, nil)
+		panicErr, _, _ := readCrashBundle(t, err)
+		t.Cleanup(func() { os.Remove(panicErr.BundlePath) })
+		assert.Equal(t, os.TempDir(), filepath.Dir(panicErr.BundlePath))
+	})
+
+	t.Run("should not write a bundle when crash bundles are skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		client := newTestClient(t, newCrashServer(t), config.WithCrashStackDir(dir), config.WithSkipCrashBundle(true), config.WithLogLevel("quiet"))
+
+		_, err := client.Do(context.Background(), http.MethodPost, "settings", nil, nil, panickingInput{}, nil, nil)
+		var panicErr *errors.PanicError
+		require.True(t, stderrors.As(err, &panicErr))
+		assert.Equal(t, "marshal boom", panicErr.Value)
+		assert.Empty(t, panicErr.BundlePath)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/auth"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingProvider returns the credentials in keys, moving on to the next
+// key each time it is expired.
+type rotatingProvider struct {
+	mu        sync.Mutex
+	keys      []credentials.Credentials
+	current   int
+	retrieved int
+	expired   int
+}
+
+func (p *rotatingProvider) Retrieve(context.Context) (credentials.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retrieved++
+	return p.keys[p.current], nil
+}
+
+func (p *rotatingProvider) Expire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expired++
+	p.current = min(p.current+1, len(p.keys)-1)
+}
+
+// newCredentialsServer returns the URL of a tenant that only accepts
+// requests signed with validKey and key ID 7.
+func newCredentialsServer(t *testing.T, validKey string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != validKey || r.Header.Get("x-xdr-auth-id") != "7" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"reply":{"err_code":401,"err_msg":"Public API request unauthorized"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"reply":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestDo_CredentialsProvider(t *testing.T) {
+	t.Run("should sign every request with the provider's credentials", func(t *testing.T) {
+		provider := &rotatingProvider{keys: []credentials.Credentials{{APIKey: "key", APIKeyID: 7}}}
+		client := newTestClient(t, newCredentialsServer(t, "key"),
+			config.WithCortexAPIKeyType("standard"), config.WithCredentialsProvider(provider), config.WithMaxRetries(0))
+
+		for range 2 {
+			_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, provider.retrieved)
+		assert.Equal(t, 0, provider.expired)
+	})
+
+	t.Run("should refresh and retry once on 401", func(t *testing.T) {
+		provider := &rotatingProvider{keys: []credentials.Credentials{
+			{APIKey: "old-key", APIKeyID: 7},
+			{APIKey: "new-key", APIKeyID: 7},
+		}}
+		client := newTestClient(t, newCredentialsServer(t, "new-key"),
+			config.WithCortexAPIKeyType("standard"), config.WithCredentialsProvider(provider), config.WithMaxRetries(0))
+
+		var output string
+		_, err := client.Do(context.Background(), http.MethodPost, "test", nil, nil, map[string]string{}, &output, &DoOptions{ResponseWrapperKeys: []string{"reply"}})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", output)
+		assert.Equal(t, 1, provider.expired)
+	})
+
+	t.Run("should return the 401 if the refreshed credentials are rejected", func(t *testing.T) {
+		provider := &rotatingProvider{keys: []credentials.Credentials{{APIKey: "revoked", APIKeyID: 7}}}
+		client := newTestClient(t, newCredentialsServer(t, "key"),
+			config.WithCortexAPIKeyType("standard"), config.WithCredentialsProvider(provider), config.WithMaxRetries(0))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorContains(t, err, "unauthorized")
+		assert.Equal(t, 1, provider.expired)
+		assert.Equal(t, 2, provider.retrieved)
+	})
+
+	t.Run("should reload expired credentials before signing", func(t *testing.T) {
+		provider := &rotatingProvider{keys: []credentials.Credentials{
+			{APIKey: "old-key", APIKeyID: 7, Expires: time.Now().Add(-time.Minute)},
+			{APIKey: "key", APIKeyID: 7},
+		}}
+		client := newTestClient(t, newCredentialsServer(t, "key"),
+			config.WithCortexAPIKeyType("standard"), config.WithCredentialsProvider(provider), config.WithMaxRetries(0))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, provider.expired)
+	})
+
+	t.Run("should fail if the provider only has expired credentials", func(t *testing.T) {
+		provider := &rotatingProvider{keys: []credentials.Credentials{
+			{APIKey: "key", APIKeyID: 7, Expires: time.Now().Add(-time.Minute)},
+		}}
+		client := newTestClient(t, newCredentialsServer(t, "key"),
+			config.WithCortexAPIKeyType("standard"), config.WithCredentialsProvider(provider), config.WithMaxRetries(0))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, credentials.ErrExpired)
+	})
+}
+
+func TestDo_Authenticator(t *testing.T) {
+	t.Run("should sign requests with the configured authenticator", func(t *testing.T) {
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"reply":"ok"}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(t, server.URL, config.WithAuthenticator(auth.Bearer{Token: "token"}))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token", authorization)
+	})
+
+	t.Run("should sign the request as it is sent", func(t *testing.T) {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received = string(body)
+			assert.Equal(t, "POST /test/abc?page=2 "+received, r.Header.Get("X-Signature"))
+			_, _ = w.Write([]byte(`{"reply":"ok"}`))
+		}))
+		defer server.Close()
+
+		signer := auth.AuthenticatorFunc(func(req *http.Request) error {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("X-Signature", req.Method+" "+req.URL.RequestURI()+" "+string(body))
+			return nil
+		})
+		client := newTestClient(t, server.URL, config.WithAuthenticator(signer))
+
+		_, err := client.Do(context.Background(), http.MethodPost, "test", &[]string{"abc"}, &url.Values{"page": []string{"2"}},
+			map[string]string{"name": "x"}, nil, nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"x"}`, received)
+	})
+}
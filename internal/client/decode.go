@@ -0,0 +1,240 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+)
+
+// readResponseBody reads the whole response body in a single buffer, sized
+// from the Content-Length header when known. If limit is positive and the
+// body is larger, a *errors.ResponseTooLargeError is returned without
+// reading more than limit+1 bytes.
+func readResponseBody(resp *http.Response, limit int64) ([]byte, error) {
+	if limit > 0 && resp.ContentLength > limit {
+		return nil, &errors.ResponseTooLargeError{Limit: limit, ContentLength: resp.ContentLength}
+	}
+
+	var buf bytes.Buffer
+	if resp.ContentLength > 0 {
+		// Leave room for the final read that detects EOF, so the buffer
+		// never has to grow
+		buf.Grow(int(resp.ContentLength) + bytes.MinRead)
+	}
+
+	var r io.Reader = resp.Body
+	if limit > 0 {
+		r = io.LimitReader(resp.Body, limit+1)
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	if limit > 0 && int64(buf.Len()) > limit {
+		return nil, &errors.ResponseTooLargeError{Limit: limit, ContentLength: -1}
+	}
+	return buf.Bytes(), nil
+}
+
+// streamResponseBody decodes the response body with decode as it is read,
+// without buffering it. The body is bounded by limit as in readResponseBody;
+// an empty body is not decoded.
+func streamResponseBody(resp *http.Response, limit int64, decode func(io.Reader) *errors.CortexCloudSdkError) *errors.CortexCloudSdkError {
+	if limit > 0 && resp.ContentLength > limit {
+		return responseReadError(resp, &errors.ResponseTooLargeError{Limit: limit, ContentLength: resp.ContentLength})
+	}
+
+	body := &boundedReader{r: resp.Body, limit: limit}
+	r := bufio.NewReader(body)
+	if _, err := r.Peek(1); err == io.EOF {
+		return nil
+	}
+	err := decode(r)
+	if body.err != nil {
+		// The decoder failed because the body could not be read
+		return responseReadError(resp, body.err)
+	}
+	return err
+}
+
+// responseReadError returns the error of a response body that could not be
+// read, either because it is larger than the maximum response size or
+// because reading it failed.
+func responseReadError(resp *http.Response, err error) *errors.CortexCloudSdkError {
+	if tooLarge, ok := err.(*errors.ResponseTooLargeError); ok {
+		statusCode := resp.StatusCode
+		return errors.NewCortexCloudSdkError(
+			errors.CodeResponseTooLarge,
+			fmt.Sprintf("failed to read response body: %v", tooLarge),
+			nil,
+			&statusCode,
+			tooLarge,
+		)
+	}
+	return errors.NewInternalSDKError(
+		errors.CodeResponseBodyReadFailure,
+		fmt.Sprintf("failed to read response body: %v", err),
+		err,
+	)
+}
+
+// boundedReader reads from r, failing with a *errors.ResponseTooLargeError
+// once more than limit bytes were read if limit is positive. It records the
+// first error other than io.EOF returned by r.
+type boundedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+	err   error
+}
+
+// Read implements io.Reader.
+func (b *boundedReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	if b.limit > 0 && b.n > b.limit {
+		err = &errors.ResponseTooLargeError{Limit: b.limit, ContentLength: -1}
+	}
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// headWriter keeps what is written to it in *buf as long as it fits in max
+// bytes, and drops it otherwise.
+type headWriter struct {
+	buf *[]byte
+	max int
+	n   int
+}
+
+// Write implements io.Writer.
+func (w *headWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	if w.n > w.max {
+		*w.buf = nil
+	} else {
+		*w.buf = append(*w.buf, p...)
+	}
+	return len(p), nil
+}
+
+// decodeResponse decodes the body read from r into output in a single pass,
+// descending through the given wrapper keys first. Sibling values of the
+// wrapper keys are skipped token by token rather than unmarshaled. Like
+// json.Unmarshal, it rejects data after the JSON document.
+func decodeResponse(r io.Reader, wrapperKeys []string, output any) *errors.CortexCloudSdkError {
+	dec := json.NewDecoder(r)
+	for _, key := range wrapperKeys {
+		found, err := seekKey(dec, key)
+		if err != nil {
+			return errors.NewInternalSDKError(
+				errors.CodeResponseDeserializationFailure,
+				fmt.Sprintf("failed to unmarshal response wrapper for key '%s': %v", key, err),
+				err,
+			)
+		}
+		if !found {
+			return errors.NewInternalSDKError(
+				errors.CodeResponseDeserializationFailure,
+				fmt.Sprintf("response wrapper key '%s' not found", key),
+				nil,
+			)
+		}
+	}
+
+	if err := dec.Decode(output); err != nil {
+		return errors.NewInternalSDKError(
+			errors.CodeResponseDeserializationFailure,
+			fmt.Sprintf("failed to unmarshal response body into output type: %v", err),
+			err,
+		)
+	}
+	if err := finishDocument(dec, len(wrapperKeys)); err != nil {
+		return errors.NewInternalSDKError(
+			errors.CodeResponseDeserializationFailure,
+			fmt.Sprintf("failed to unmarshal response body: %v", err),
+			err,
+		)
+	}
+	return nil
+}
+
+// finishDocument advances dec past the rest of the depth objects it is
+// nested in, and returns an error unless the JSON document then ends.
+func finishDocument(dec *json.Decoder, depth int) error {
+	for range depth {
+		for dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = fmt.Errorf("invalid data after the JSON document")
+		}
+		return err
+	}
+	return nil
+}
+
+// seekKey advances dec, which must be positioned at the start of a JSON
+// object, to the value of key. It reports whether the key was found.
+func seekKey(dec *json.Decoder, key string) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	if tok != json.Delim('{') {
+		return false, fmt.Errorf("expected JSON object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return false, err
+		}
+		if name, _ := tok.(string); name == key {
+			return true, nil
+		}
+		if err := skipValue(dec); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// skipValue advances dec past the next JSON value.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
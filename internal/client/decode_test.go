@@ -0,0 +1,215 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkerrors "github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	type item struct {
+		ID string `json:"id"`
+	}
+
+	t.Run("should descend through wrapper keys skipping siblings", func(t *testing.T) {
+		body := `{"meta":{"a":[1,{"b":[]}]},"reply":{"total":2,"data":[{"id":"x"},{"id":"y"}],"next":null}}`
+		var out []item
+		require.Nil(t, decodeResponse(strings.NewReader(body), []string{"reply", "data"}, &out))
+		assert.Equal(t, []item{{"x"}, {"y"}}, out)
+	})
+
+	t.Run("should decode the whole body without wrapper keys", func(t *testing.T) {
+		var out bool
+		require.Nil(t, decodeResponse(strings.NewReader(`true`), nil, &out))
+		assert.True(t, out)
+	})
+
+	t.Run("should report a missing wrapper key", func(t *testing.T) {
+		var out []item
+		err := decodeResponse(strings.NewReader(`{"reply":{"total":0}}`), []string{"reply", "data"}, &out)
+		require.NotNil(t, err)
+		assert.Contains(t, err.Message, "response wrapper key 'data' not found")
+	})
+
+	t.Run("should report a wrapper that is not an object", func(t *testing.T) {
+		var out []item
+		err := decodeResponse(strings.NewReader(`{"reply":[]}`), []string{"reply", "data"}, &out)
+		require.NotNil(t, err)
+		assert.Contains(t, err.Message, "failed to unmarshal response wrapper for key 'data'")
+	})
+
+	t.Run("should report output type mismatches", func(t *testing.T) {
+		var out []item
+		err := decodeResponse(strings.NewReader(`{"reply":"oops"}`), []string{"reply"}, &out)
+		require.NotNil(t, err)
+		assert.Contains(t, err.Message, "failed to unmarshal response body into output type")
+	})
+
+	t.Run("should reject data after the document", func(t *testing.T) {
+		var out bool
+		require.NotNil(t, decodeResponse(strings.NewReader(`true false`), nil, &out))
+
+		var items []item
+		require.NotNil(t, decodeResponse(strings.NewReader(`{"reply":{"data":[],"next":null}}{}`), []string{"reply", "data"}, &items))
+		require.NotNil(t, decodeResponse(strings.NewReader(`{"reply":{"data":[]}`), []string{"reply", "data"}, &items))
+		require.Nil(t, decodeResponse(strings.NewReader(`{"reply":{"data":[],"next":null},"meta":{}}`+"\n"), []string{"reply", "data"}, &items))
+	})
+}
+
+func TestReadResponseBody(t *testing.T) {
+	newResp := func(body string, contentLength int64) *http.Response {
+		return &http.Response{Body: io.NopCloser(strings.NewReader(body)), ContentLength: contentLength}
+	}
+
+	t.Run("should read bodies within the limit", func(t *testing.T) {
+		body, err := readResponseBody(newResp(`{"a":1}`, 7), 7)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+
+		body, err = readResponseBody(newResp(`{"a":1}`, -1), 0)
+		require.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+	})
+
+	t.Run("should reject announced oversized bodies", func(t *testing.T) {
+		_, err := readResponseBody(newResp(`{"a":1}`, 7), 4)
+		var tooLarge *sdkerrors.ResponseTooLargeError
+		require.ErrorAs(t, err, &tooLarge)
+		assert.Equal(t, int64(7), tooLarge.ContentLength)
+	})
+
+	t.Run("should reject oversized bodies of unknown length", func(t *testing.T) {
+		_, err := readResponseBody(newResp(`{"a":1}`, -1), 4)
+		var tooLarge *sdkerrors.ResponseTooLargeError
+		require.ErrorAs(t, err, &tooLarge)
+		assert.Equal(t, int64(-1), tooLarge.ContentLength)
+		assert.Equal(t, int64(4), tooLarge.Limit)
+	})
+}
+
+func TestDo_MaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"reply":"` + strings.Repeat("x", 1024) + `"}`))
+		if r.URL.Path == "/chunked" {
+			// Send the end of the body in another chunk, without announcing its length
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, config.WithMaxResponseSize(512))
+
+	for _, endpoint := range []string{"announced", "chunked"} {
+		t.Run("should reject oversized "+endpoint+" responses", func(t *testing.T) {
+			var out string
+			_, err := client.Do(context.Background(), http.MethodGet, endpoint, nil, nil, nil, &out, nil)
+			require.Error(t, err)
+
+			var tooLarge *sdkerrors.ResponseTooLargeError
+			require.ErrorAs(t, err, &tooLarge)
+			assert.Equal(t, int64(512), tooLarge.Limit)
+			assert.Equal(t, sdkerrors.CodeResponseTooLarge, err.(*sdkerrors.CortexCloudSdkError).Code)
+			assert.Empty(t, out)
+		})
+	}
+}
+
+func TestDo_StreamsResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/trailing":
+			_, _ = w.Write([]byte(`{"reply":"ok"} {"reply":"again"}`))
+		case "/empty":
+		default:
+			_, _ = w.Write([]byte(`{"reply":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	opts := &DoOptions{ResponseWrapperKeys: []string{"reply"}}
+
+	t.Run("should decode the response as it is read without returning it", func(t *testing.T) {
+		client := newTestClient(t, server.URL)
+		var out string
+		body, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, &out, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+		assert.Nil(t, body)
+	})
+
+	t.Run("should buffer the response for interceptors", func(t *testing.T) {
+		var seen []byte
+		client := newTestClient(t, server.URL, config.WithInterceptors(
+			func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+				resp, err := next(ctx, req)
+				seen = resp.Body
+				return resp, err
+			},
+		))
+		var out string
+		body, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, &out, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+		assert.JSONEq(t, `{"reply":"ok"}`, string(body))
+		assert.Equal(t, body, seen)
+	})
+
+	t.Run("should reject data after the document", func(t *testing.T) {
+		client := newTestClient(t, server.URL)
+		var out string
+		_, err := client.Do(context.Background(), http.MethodGet, "trailing", nil, nil, nil, &out, opts)
+		var sdkErr *sdkerrors.CortexCloudSdkError
+		require.ErrorAs(t, err, &sdkErr)
+		assert.Equal(t, sdkerrors.CodeResponseDeserializationFailure, sdkErr.Code)
+	})
+
+	t.Run("should not decode an empty response", func(t *testing.T) {
+		client := newTestClient(t, server.URL)
+		var out string
+		_, err := client.Do(context.Background(), http.MethodGet, "empty", nil, nil, nil, &out, opts)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+}
+
+func BenchmarkDecodeResponse(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString(`{"reply":{"total":5000,"data":[`)
+	for i := range 5000 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `{"id":"%d","name":"asset group %d","description":"benchmark"}`, i, i)
+	}
+	sb.WriteString(`]}}`)
+	body := []byte(sb.String())
+
+	type group struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var out []group
+		if err := decodeResponse(bytes.NewReader(body), []string{"reply", "data"}, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
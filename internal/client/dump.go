@@ -0,0 +1,281 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/redact"
+)
+
+// dumpSamplingInterval is the period after which the sampling of the dumps
+// of each endpoint starts over.
+const dumpSamplingInterval = time.Minute
+
+// dumpPolicy controls what the bodies of debug dumps show, and which
+// exchanges are dumped.
+type dumpPolicy struct {
+	redacted []jsonPath
+	maxSize  int          // The maximum size of a formatted body, or 0
+	sampler  *dumpSampler // nil if every exchange is dumped
+}
+
+// builtinRedactedPaths are the paths of the body fields redacted in every
+// debug dump: the shared redacted fields, wherever they appear.
+var builtinRedactedPaths = func() []jsonPath {
+	paths := make([]jsonPath, len(redact.Fields))
+	for i, field := range redact.Fields {
+		path, err := parseJSONPath("$.." + field)
+		if err != nil {
+			panic(err)
+		}
+		paths[i] = path
+	}
+	return paths
+}()
+
+// defaultDumpPolicy returns the policy of a transport built without a
+// configuration: built-in redaction and size limit, without sampling.
+func defaultDumpPolicy() *dumpPolicy {
+	return &dumpPolicy{redacted: builtinRedactedPaths, maxSize: config.DefaultLogDumpMaxSize}
+}
+
+// newDumpPolicy returns the dump policy set up by cfg.
+func newDumpPolicy(cfg *config.Config) (*dumpPolicy, error) {
+	policy := &dumpPolicy{
+		redacted: slices.Clone(builtinRedactedPaths),
+		maxSize:  cfg.LogDumpMaxSize(),
+	}
+	for _, raw := range cfg.LogRedactedFields() {
+		path, err := parseJSONPath(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redacted field %q: %w", raw, err)
+		}
+		policy.redacted = append(policy.redacted, path)
+	}
+	if first, thereafter := cfg.LogDumpSampling(); first > 0 || thereafter > 0 {
+		policy.sampler = &dumpSampler{first: first, thereafter: thereafter}
+	}
+	return policy, nil
+}
+
+// formatDump returns dump, an HTTP/1 request or response dump whose headers
+// were already redacted, with its body redacted, pretty-printed and
+// truncated.
+func (p *dumpPolicy) formatDump(dump []byte) string {
+	const headerEnd = "\r\n\r\n"
+
+	bodyStart := bytes.Index(dump, []byte(headerEnd))
+	if bodyStart < 0 {
+		return string(dump)
+	}
+	bodyStart += len(headerEnd)
+	return string(dump[:bodyStart]) + p.formatBody(dump[bodyStart:])
+}
+
+// formatBody redacts and pretty-prints body if it is JSON, or each of its
+// lines that is, then truncates it to the maximum size.
+func (p *dumpPolicy) formatBody(body []byte) string {
+	var formatted string
+	if json.Valid(body) {
+		formatted = p.formatJSON(body)
+	} else {
+		lines := strings.Split(string(body), "\n")
+		for i, line := range lines {
+			if json.Valid([]byte(line)) {
+				lines[i] = p.formatJSON([]byte(line))
+			}
+		}
+		formatted = strings.Join(lines, "\n")
+	}
+
+	if p.maxSize <= 0 || len(formatted) <= p.maxSize {
+		return formatted
+	}
+	cut := p.maxSize
+	for cut > 0 && !utf8.RuneStart(formatted[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n...[TRUNCATED %d of %d bytes]", formatted[:cut], len(formatted)-cut, len(formatted))
+}
+
+// formatJSON returns the JSON document data pretty-printed, with the values
+// of the redacted fields replaced. Documents without redacted fields keep the
+// order of their keys.
+func (p *dumpPolicy) formatJSON(data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return string(data)
+	}
+
+	redacted := false
+	for _, path := range p.redacted {
+		if path.redact(document) {
+			redacted = true
+		}
+	}
+
+	var out bytes.Buffer
+	if !redacted {
+		_ = json.Indent(&out, data, "", " ")
+		return out.String()
+	}
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", " ")
+	if err := encoder.Encode(document); err != nil {
+		return redactedValue
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// pathStep is a step of a JSON path: a key or index, "*" for any, matched
+// either on the current value or, for descendant steps, at any depth below it.
+type pathStep struct {
+	name       string
+	descendant bool
+}
+
+// matches reports whether the step matches the object key or array index.
+func (s pathStep) matches(key string) bool {
+	return s.name == "*" || strings.EqualFold(s.name, key)
+}
+
+// jsonPath is a parsed JSON path, e.g. "$.settings..token" or "$.items[*].key".
+type jsonPath []pathStep
+
+// parseJSONPath parses a JSON path of ".key", "..key", "[index]" and
+// "['key']" steps, starting at the root "$".
+func parseJSONPath(raw string) (jsonPath, error) {
+	rest, ok := strings.CutPrefix(raw, "$")
+	if !ok {
+		return nil, fmt.Errorf(`path must start with "$"`)
+	}
+
+	var path jsonPath
+	for rest != "" {
+		var step pathStep
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.descendant = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		if inner, ok := strings.CutPrefix(rest, "["); ok {
+			end := strings.IndexByte(inner, ']')
+			if end < 0 {
+				return nil, fmt.Errorf(`missing "]"`)
+			}
+			step.name = strings.Trim(inner[:end], `'"`)
+			rest = inner[end+1:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			step.name, rest = rest[:end], rest[end:]
+		}
+		if step.name == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+		path = append(path, step)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("path selects the whole document")
+	}
+	return path, nil
+}
+
+// redact replaces the values the path selects in document with
+// redactedValue, and reports whether it replaced any.
+func (p jsonPath) redact(document any) bool {
+	return redactSteps(document, p)
+}
+
+// redactSteps redacts the values the steps select in value.
+func redactSteps(value any, steps []pathStep) bool {
+	step, last := steps[0], len(steps) == 1
+	redacted := false
+	visit := func(key string, child any, set func(any)) {
+		if step.matches(key) {
+			if last {
+				set(redactedValue)
+				redacted = true
+				return
+			}
+			if redactSteps(child, steps[1:]) {
+				redacted = true
+			}
+		}
+		if step.descendant && redactSteps(child, steps) {
+			redacted = true
+		}
+	}
+
+	switch node := value.(type) {
+	case map[string]any:
+		for key, child := range node {
+			visit(key, child, func(v any) { node[key] = v })
+		}
+	case []any:
+		for i, child := range node {
+			visit(strconv.Itoa(i), child, func(v any) { node[i] = v })
+		}
+	}
+	return redacted
+}
+
+// dumpSampler samples the dumps of the exchanges with each endpoint: within
+// each dumpSamplingInterval, the first exchanges are dumped, then one in
+// thereafter.
+type dumpSampler struct {
+	first, thereafter int
+
+	mu      sync.Mutex
+	start   time.Time
+	counts  map[string]int // The exchanges seen in the current interval
+	skipped map[string]int // The exchanges not dumped since the last dump
+}
+
+// sample reports whether the exchange identified by key is dumped and, if it
+// is, how many exchanges with the same key were not dumped since the last
+// one.
+func (s *dumpSampler) sample(key string) (dump bool, skipped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); s.counts == nil || now.Sub(s.start) >= dumpSamplingInterval {
+		s.start = now
+		s.counts = make(map[string]int)
+		if s.skipped == nil {
+			s.skipped = make(map[string]int)
+		}
+	}
+
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.first || (s.thereafter > 0 && (n-s.first)%s.thereafter == 0) {
+		skipped = s.skipped[key]
+		delete(s.skipped, key)
+		return true, skipped
+	}
+	s.skipped[key]++
+	return false, 0
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	for raw, expected := range map[string]jsonPath{
+		"$.a":             {{name: "a"}},
+		"$..token":        {{name: "token", descendant: true}},
+		"$.items[*].key":  {{name: "items"}, {name: "*"}, {name: "key"}},
+		"$['a.b'][0]":     {{name: "a.b"}, {name: "0"}},
+		"$.settings..key": {{name: "settings"}, {name: "key", descendant: true}},
+	} {
+		path, err := parseJSONPath(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, expected, path, raw)
+	}
+
+	for _, raw := range []string{"", "a.b", "$", "$.", "$.a[0", "$a"} {
+		_, err := parseJSONPath(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestDumpPolicy_FormatBody(t *testing.T) {
+	policy, err := newDumpPolicy(config.NewConfig(config.WithLogRedactedFields("$.items[*].value")))
+	require.NoError(t, err)
+
+	t.Run("should redact the built-in fields at any depth", func(t *testing.T) {
+		got := policy.formatBody([]byte(`{"request_data":{"name":"syslog","certificate_content":"-----BEGIN CERTIFICATE-----","auth":{"Password":"hunter2"}}}`))
+		assert.NotContains(t, got, "BEGIN CERTIFICATE")
+		assert.NotContains(t, got, "hunter2")
+		assert.Contains(t, got, `"name": "syslog"`)
+		assert.Equal(t, 2, strings.Count(got, redactedValue))
+	})
+
+	t.Run("should redact the configured fields", func(t *testing.T) {
+		got := policy.formatBody([]byte(`{"items":[{"key":"a","value":"secret-a"},{"key":"b","value":{"nested":"secret-b"}}]}`))
+		assert.NotContains(t, got, "secret-a")
+		assert.NotContains(t, got, "secret-b")
+		assert.Contains(t, got, `"key": "b"`)
+	})
+
+	t.Run("should keep bodies without redacted fields as they are", func(t *testing.T) {
+		assert.Equal(t, "{\n \"b\": 1.50,\n \"a\": \"<x>\"\n}", policy.formatBody([]byte(`{"b":1.50,"a":"<x>"}`)))
+		assert.Equal(t, "not json", policy.formatBody([]byte("not json")))
+	})
+
+	t.Run("should redact each JSON line", func(t *testing.T) {
+		got := policy.formatBody([]byte("{\"token\":\"t1\"}\n{\"token\":\"t2\"}"))
+		assert.NotContains(t, got, "t1")
+		assert.NotContains(t, got, "t2")
+	})
+}
+
+func TestDumpPolicy_Truncation(t *testing.T) {
+	policy := &dumpPolicy{maxSize: 10}
+	assert.Equal(t, "short", policy.formatBody([]byte("short")))
+	assert.Equal(t, "0123456789\n...[TRUNCATED 5 of 15 bytes]", policy.formatBody([]byte("0123456789abcde")))
+
+	// Multi-byte characters are never split
+	assert.Equal(t, "012345678\n...[TRUNCATED 3 of 12 bytes]", policy.formatBody([]byte("012345678éé")[:12]))
+
+	unlimited := &dumpPolicy{}
+	assert.Equal(t, strings.Repeat("x", 100), unlimited.formatBody([]byte(strings.Repeat("x", 100))))
+}
+
+func TestDumpSampler(t *testing.T) {
+	sampler := &dumpSampler{first: 2, thereafter: 3}
+	var dumped []int
+	var skippedTotal int
+	for i := 1; i <= 8; i++ {
+		dump, skipped := sampler.sample("GET /a")
+		if dump {
+			dumped = append(dumped, i)
+			skippedTotal += skipped
+		}
+	}
+	assert.Equal(t, []int{1, 2, 5, 8}, dumped)
+	assert.Equal(t, 4, skippedTotal)
+
+	// Each endpoint is sampled on its own
+	dump, skipped := sampler.sample("GET /b")
+	assert.True(t, dump)
+	assert.Zero(t, skipped)
+}
+
+func TestTransport_RoundTrip_DumpPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"reply":{"idp_certificate":"MIIC-secret"}}`)
+	}))
+	defer server.Close()
+
+	fake := &fakeInternalClient{debug: true}
+	rt := NewTransport(http.DefaultTransport, fake)
+	rt.dump.sampler = &dumpSampler{first: 1}
+	httpClient := &http.Client{Transport: rt}
+
+	for range 3 {
+		resp, err := httpClient.Post(server.URL+"/settings", "application/json", strings.NewReader(`{"request_data":{"client_secret":"shh"}}`))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	logs := fake.joined()
+	assert.NotContains(t, logs, "shh")
+	assert.NotContains(t, logs, "MIIC-secret")
+	assert.Len(t, fake.messages, 2, "only the first exchange is dumped")
+
+	// The next dump reports the exchanges left out
+	rt.dump.sampler.thereafter = 1
+	resp, err := httpClient.Post(server.URL+"/settings", "application/json", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Contains(t, fake.joined(), "2 similar exchanges with POST /settings were not dumped")
+}
+
+func TestNewClient_InvalidRedactedField(t *testing.T) {
+	cfg := config.NewConfig(
+		config.WithCortexAPIURL("https://api.example.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithLogRedactedFields("token"),
+	)
+	_, err := NewClientFromConfig(cfg)
+	assert.ErrorContains(t, err, `invalid redacted field "token"`)
+}
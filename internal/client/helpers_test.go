@@ -0,0 +1,31 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns the configuration of a client of the tenant at url,
+// authenticated with a test API key and without the logging transport, with
+// opts applied on top.
+func testConfig(url string, opts ...config.Option) *config.Config {
+	return config.NewConfig(append([]config.Option{
+		config.WithCortexAPIURL(url),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithSkipLoggingTransport(true),
+	}, opts...)...)
+}
+
+// newTestClient returns a client with the configuration of testConfig.
+func newTestClient(t *testing.T, url string, opts ...config.Option) *Client {
+	t.Helper()
+	client, err := NewClientFromConfig(testConfig(url, opts...))
+	require.NoError(t, err)
+	return client
+}
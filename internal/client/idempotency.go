@@ -0,0 +1,116 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header used to carry the
+	// idempotency key of a logical call.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotencyKeyKey is the context key for the idempotency key.
+	idempotencyKeyKey contextKey = "cortex-idempotency-key"
+)
+
+// generateIdempotencyKey creates a unique idempotency key.
+// Format: idem_<32-char-hex>
+func generateIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Fallback to timestamp-based key if random fails
+		return fmt.Sprintf("idem_fallback_%d", time.Now().UnixNano())
+	}
+	return "idem_" + hex.EncodeToString(b)
+}
+
+// WithIdempotencyKey returns a context that marks calls made with it as safe
+// to retry, attaching key in the Idempotency-Key header of every attempt.
+//
+// If key is empty, a unique key is generated for each logical call made with
+// the returned context and reused across that call's retry attempts.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// GetIdempotencyKey retrieves the idempotency key from context. The boolean
+// result reports whether the caller opted in to idempotent retries, even if
+// the key itself is empty and still has to be generated.
+func GetIdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey).(string)
+	return key, ok
+}
+
+// resolveIdempotencyKey returns the idempotency key to use for a single
+// logical call, generating one if the caller opted in without providing a
+// key. An empty string is returned if the caller did not opt in.
+func resolveIdempotencyKey(ctx context.Context) string {
+	key, ok := GetIdempotencyKey(ctx)
+	if !ok {
+		return ""
+	}
+	if key == "" {
+		key = generateIdempotencyKey()
+	}
+	return key
+}
+
+// isIdempotentMethod reports whether repeating a request with the given
+// method is safe by definition (RFC 9110, section 9.2.2).
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodTrace,
+		http.MethodPut,
+		http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRejectedBeforeProcessing reports whether resp shows that the tenant
+// refused the request without applying it: only a rate limiting 429 telling
+// when to try again does. Other statuses, such as a 503 from a proxy or load
+// balancer, say nothing about whether the request was applied.
+func isRejectedBeforeProcessing(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") != ""
+}
+
+// isSafeToRetry reports whether repeating the failed attempt cannot apply
+// its side effects twice. Idempotent methods and calls carrying an
+// idempotency key are always safe. Other methods are only safe when the
+// server rejected the request before processing it, or when a network
+// failure happened before the request left the client.
+func isSafeToRetry(attempt retry.Attempt, requestSent bool, hasIdempotencyKey bool) bool {
+	if isIdempotentMethod(attempt.Method) || hasIdempotencyKey {
+		return true
+	}
+	if attempt.Response != nil {
+		return isRejectedBeforeProcessing(attempt.Response)
+	}
+	return !requestSent
+}
+
+// withSendTrace returns a context that records in sent whether any part of
+// the request was written to the connection.
+func withSendTrace(ctx context.Context, sent *atomic.Bool) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaderField: func(string, []string) { sent.Store(true) },
+		WroteHeaders:     func() { sent.Store(true) },
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyContext(t *testing.T) {
+	t.Run("should report no key when caller did not opt in", func(t *testing.T) {
+		_, ok := GetIdempotencyKey(context.Background())
+		assert.False(t, ok)
+		assert.Empty(t, resolveIdempotencyKey(context.Background()))
+	})
+
+	t.Run("should use explicit key", func(t *testing.T) {
+		ctx := WithIdempotencyKey(context.Background(), "idem_custom")
+		assert.Equal(t, "idem_custom", resolveIdempotencyKey(ctx))
+	})
+
+	t.Run("should generate a new key per logical call when empty", func(t *testing.T) {
+		ctx := WithIdempotencyKey(context.Background(), "")
+		first := resolveIdempotencyKey(ctx)
+		second := resolveIdempotencyKey(ctx)
+		assert.True(t, strings.HasPrefix(first, "idem_"))
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestIsSafeToRetry_Status(t *testing.T) {
+	tests := []struct {
+		method     string
+		status     int
+		retryAfter string
+		hasKey     bool
+		want       bool
+	}{
+		{http.MethodGet, http.StatusBadGateway, "", false, true},
+		{http.MethodPut, http.StatusGatewayTimeout, "", false, true},
+		{http.MethodDelete, http.StatusServiceUnavailable, "", false, true},
+		{http.MethodPost, http.StatusTooManyRequests, "1", false, true},
+		{http.MethodPost, http.StatusTooManyRequests, "", false, false},
+		{http.MethodPost, http.StatusServiceUnavailable, "1", false, false},
+		{http.MethodPost, http.StatusUnauthorized, "", false, false},
+		{http.MethodPost, http.StatusBadGateway, "", false, false},
+		{http.MethodPatch, http.StatusGatewayTimeout, "", false, false},
+		{http.MethodPost, http.StatusBadGateway, "", true, true},
+		{http.MethodPost, http.StatusServiceUnavailable, "", true, true},
+		{http.MethodPost, http.StatusInternalServerError, "", false, false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.retryAfter != "" {
+			header.Set("Retry-After", tt.retryAfter)
+		}
+		attempt := retry.Attempt{Method: tt.method, Response: &http.Response{StatusCode: tt.status, Header: header}}
+		assert.Equal(t, tt.want, isSafeToRetry(attempt, true, tt.hasKey), "%s %d Retry-After=%q key=%v", tt.method, tt.status, tt.retryAfter, tt.hasKey)
+	}
+}
+
+func TestIsSafeToRetry_NetworkError(t *testing.T) {
+	attempt := func(method string) retry.Attempt {
+		return retry.Attempt{Method: method, Err: io.ErrUnexpectedEOF}
+	}
+	assert.True(t, isSafeToRetry(attempt(http.MethodGet), true, false))
+	assert.True(t, isSafeToRetry(attempt(http.MethodPost), false, false))
+	assert.True(t, isSafeToRetry(attempt(http.MethodPost), true, true))
+	assert.False(t, isSafeToRetry(attempt(http.MethodPost), true, false))
+	assert.False(t, isSafeToRetry(attempt(http.MethodPatch), true, false))
+}
+
+func TestDo_MethodAwareRetries(t *testing.T) {
+	cfg := config.NewConfig(
+		config.WithCortexAPIURL("https://testing.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(1),
+	)
+	newResp := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	t.Run("should not retry POST on ambiguous gateway error", func(t *testing.T) {
+		client, _ := NewClientFromConfig(cfg)
+		client.testData = []*http.Response{newResp(http.StatusBadGateway, ""), newResp(http.StatusOK, `{}`)}
+
+		_, err := client.Do(context.Background(), http.MethodPost, "test", nil, nil, map[string]string{}, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, client.testIndex)
+	})
+
+	t.Run("should retry POST on gateway error with idempotency key", func(t *testing.T) {
+		client, _ := NewClientFromConfig(cfg)
+		client.testData = []*http.Response{newResp(http.StatusBadGateway, ""), newResp(http.StatusOK, `{}`)}
+
+		ctx := WithIdempotencyKey(context.Background(), "")
+		_, err := client.Do(ctx, http.MethodPost, "test", nil, nil, map[string]string{}, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, client.testIndex)
+	})
+
+	t.Run("should not retry POST on service unavailable", func(t *testing.T) {
+		client, _ := NewClientFromConfig(cfg)
+		client.testData = []*http.Response{newResp(http.StatusServiceUnavailable, ""), newResp(http.StatusOK, `{}`)}
+
+		_, err := client.Do(context.Background(), http.MethodPost, "test", nil, nil, map[string]string{}, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, client.testIndex)
+	})
+
+	t.Run("should retry POST when rate limited", func(t *testing.T) {
+		client, _ := NewClientFromConfig(cfg)
+		limited := newResp(http.StatusTooManyRequests, "")
+		limited.Header = http.Header{"Retry-After": {"0"}}
+		client.testData = []*http.Response{limited, newResp(http.StatusOK, `{}`)}
+
+		_, err := client.Do(context.Background(), http.MethodPost, "test", nil, nil, map[string]string{}, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, client.testIndex)
+	})
+}
+
+func TestDo_IdempotencyKeyReusedAcrossAttempts(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		n := len(keys)
+		mu.Unlock()
+		if n == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(1),
+		config.WithSkipLoggingTransport(true),
+	))
+	require.NoError(t, err)
+
+	ctx := WithIdempotencyKey(context.Background(), "")
+	_, err = client.Do(ctx, http.MethodPost, "test", nil, nil, map[string]string{}, nil, nil)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestDo_DoesNotRetrySentPOSTAfterNetworkError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.ReadAll(r.Body)
+		// Drop the connection after the request was received.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(2),
+		config.WithSkipLoggingTransport(true),
+	))
+	require.NoError(t, err)
+
+	_, err = client.Do(context.Background(), http.MethodPost, "test", nil, nil, map[string]string{"name": "x"}, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not idempotent")
+	assert.Equal(t, int32(1), calls.Load())
+}
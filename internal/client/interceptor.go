@@ -0,0 +1,162 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
+)
+
+// attemptResult records what happened on the wire during an attempt, so Do
+// can decide whether a failed attempt may be retried.
+type attemptResult struct {
+	// requestSent reports whether any part of the request was written.
+	requestSent atomic.Bool
+	// err is the network error that ended the attempt, if any.
+	err error
+	// transportErr is the error handed to the interceptor chain for err.
+	transportErr error
+	// header holds the headers the request was sent with, once signed.
+	header http.Header
+	// decode, if set, decodes the body of a successful response as it is
+	// read instead of buffering it in the Response. It is only set when no
+	// interceptor may need the body.
+	decode func(resp *http.Response, body io.Reader) *errors.CortexCloudSdkError
+}
+
+// intercept runs req through the configured interceptors, ending with send.
+func (c *Client) intercept(ctx context.Context, req *interceptor.Request, send interceptor.Invoker) (*interceptor.Response, error) {
+	interceptors := c.config.Interceptors()
+	if len(interceptors) == 0 {
+		return send(ctx, req)
+	}
+	return interceptor.Chain(interceptors...)(ctx, req, send)
+}
+
+// send returns the innermost invoker of the interceptor chain, which sends
+// the attempt to the tenant and reads the response.
+func (c *Client) send(call *callOptions, result *attemptResult) interceptor.Invoker {
+	return func(ctx context.Context, req *interceptor.Request) (*interceptor.Response, error) {
+		var (
+			resp *http.Response
+			sent time.Time
+		)
+
+		// Handle test data if available (for internal SDK testing)
+		if len(c.testData) != 0 {
+			resp = c.testData[c.testIndex%len(c.testData)]
+			c.testIndex++
+		} else {
+			requestURL, err := c.buildRequestURL(req.Endpoint, &req.PathParams, &req.Query)
+			if err != nil {
+				return nil, errors.NewInternalSDKError(
+					errors.CodeURLConstructionFailure,
+					fmt.Sprintf("failed to build request URL: %v", err),
+					err,
+				)
+			}
+
+			// Create new HTTP request with the attempt's context, tracing
+			// whether it was written to the wire so network failures can be
+			// classified
+			attemptCtx, cancelAttempt := attemptContext(ctx, call)
+			defer cancelAttempt()
+			httpReq, err := http.NewRequestWithContext(withSendTrace(withModule(withEndpoint(attemptCtx, req.Endpoint), c.module), &result.requestSent), req.Method, requestURL, bytes.NewReader(req.Body))
+			if err != nil {
+				return nil, errors.NewInternalSDKError(
+					errors.CodeHTTPRequestCreationFailure,
+					fmt.Sprintf("failed to create HTTP request: %v", err),
+					err,
+				)
+			}
+			httpReq.Header = req.Header.Clone()
+
+			// Sign the request as it is sent, then rewind its body, which the
+			// authenticator may have read to sign it
+			if err := c.authenticate(httpReq); err != nil {
+				return nil, errors.NewInternalSDKError(
+					errors.CodeAuthenticationHeaderGenerationFailure,
+					fmt.Sprintf("failed to authenticate request: %v", err),
+					err,
+				)
+			}
+			if httpReq.GetBody != nil {
+				httpReq.Body, _ = httpReq.GetBody()
+			}
+			result.header = httpReq.Header
+
+			// Execute HTTP request
+			sent = time.Now()
+			resp, err = c.httpClient.Do(httpReq)
+			if err != nil {
+				// Check for context cancellation after Do() call
+				if ctx.Err() != nil {
+					return nil, errors.NewInternalSDKError(
+						errors.CodeContextCancellation,
+						"request cancelled by context after HTTP client call",
+						ctx.Err(),
+					)
+				}
+				c.recordCircuit(nil)
+				result.err = err
+				result.transportErr = errors.NewInternalSDKError(
+					errors.CodeNetworkError,
+					fmt.Sprintf("HTTP request failed: %v", err),
+					err,
+				)
+				return nil, result.transportErr
+			}
+			if resp == nil {
+				return nil, errors.NewInternalSDKError(
+					errors.CodeNoResponseReceived,
+					"no HTTP response received",
+					nil,
+				)
+			}
+		}
+
+		c.recordCircuit(resp)
+		if !sent.IsZero() {
+			c.clock.observe(resp.Header.Get("Date"), sent, time.Now())
+		}
+
+		// Decode a successful response as it is read if its body is not
+		// needed otherwise
+		if result.decode != nil && resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+			defer resp.Body.Close()
+			response := &interceptor.Response{StatusCode: resp.StatusCode, Header: resp.Header}
+			if err := streamResponseBody(resp, c.config.MaxResponseSize(), func(body io.Reader) *errors.CortexCloudSdkError {
+				return result.decode(resp, body)
+			}); err != nil {
+				return response, err
+			}
+			return response, nil
+		}
+
+		// Read the response body content, bounded by the maximum response size
+		body, err := readResponseBody(resp, c.config.MaxResponseSize())
+		resp.Body.Close()
+		if err != nil {
+			return nil, responseReadError(resp, err)
+		}
+
+		response := &interceptor.Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		}
+		if apiError := c.handleResponseStatus(ctx, resp.StatusCode, body); apiError != nil {
+			return response, errors.NewAPIError(apiError)
+		}
+		return response, nil
+	}
+}
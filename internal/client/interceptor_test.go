@@ -0,0 +1,121 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	sdkerrors "github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_InterceptorsSeeEveryAttempt(t *testing.T) {
+	t.Run("should run the interceptors around every attempt", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+			assert.Equal(t, "/test/abc", r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"request_data":{"name":"x"}}`, string(body))
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"err_code":503,"err_msg":"unavailable"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"reply":"ok"}`))
+		}))
+		defer server.Close()
+
+		var seen []string
+		audit := func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+			resp, err := next(ctx, req)
+			seen = append(seen, fmt.Sprintf("%s %s %v attempt=%d status=%d sdkErr=%v",
+				req.Method, req.Endpoint, req.PathParams, req.Attempt, resp.StatusCode, sdkerrors.IsCortexCloudSdkError(err)))
+			return resp, err
+		}
+		tenantHeader := func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+			req.Header.Set("X-Tenant", "tenant-a")
+			return next(ctx, req)
+		}
+		client := newTestClient(t, server.URL, config.WithMaxRetries(1), config.WithInterceptors(audit, tenantHeader))
+
+		var out string
+		_, err := client.Do(context.Background(), http.MethodGet, "test", &[]string{"abc"}, nil,
+			map[string]string{"name": "x"}, &out, &DoOptions{
+				RequestWrapperKeys:  []string{"request_data"},
+				ResponseWrapperKeys: []string{"reply"},
+			})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+		assert.Equal(t, []string{
+			"GET test [abc] attempt=0 status=503 sdkErr=true",
+			"GET test [abc] attempt=1 status=200 sdkErr=false",
+		}, seen)
+	})
+}
+
+func TestDo_InterceptorShortCircuits(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	t.Run("should return the response of an interceptor without sending the request", func(t *testing.T) {
+		cached := func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+			return &interceptor.Response{StatusCode: http.StatusOK, Body: []byte(`"cached"`)}, nil
+		}
+		client := newTestClient(t, server.URL, config.WithInterceptors(cached))
+
+		var out string
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, &out, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "cached", out)
+	})
+
+	t.Run("should return the error of an interceptor without sending the request", func(t *testing.T) {
+		denied := fmt.Errorf("denied by policy")
+		deny := func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+			return nil, denied
+		}
+		client := newTestClient(t, server.URL, config.WithInterceptors(deny))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, denied)
+	})
+
+	assert.Zero(t, calls.Load())
+}
+
+func TestDo_InterceptorRewritesRequest(t *testing.T) {
+	t.Run("should send the request as rewritten by an interceptor", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		client := newTestClient(t, server.URL, config.WithInterceptors(
+			func(ctx context.Context, req *interceptor.Request, next interceptor.Invoker) (*interceptor.Response, error) {
+				req.Query.Set("page", "2")
+				return next(ctx, req)
+			},
+		))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, &url.Values{"page": []string{"1"}}, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2", query.Get("page"))
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/log"
+)
+
+const (
+	// LogFieldRequestID is the field holding the request ID of the call a
+	// log line is about.
+	LogFieldRequestID = "request_id"
+	// LogFieldModule is the field holding the SDK module a log line comes
+	// from.
+	LogFieldModule = "module"
+)
+
+// logLevelStringToInt maps string log levels to an integer for comparison.
+// Higher integer means more verbose; unknown levels turn logging off.
+func logLevelStringToInt(level string) int {
+	switch strings.ToLower(level) {
+	case "quiet":
+		return -1 // Represents "off"
+	case "error":
+		return 0
+	case "warn":
+		return 1
+	case "info":
+		return 2
+	case "debug":
+		return 3
+	default:
+		return -1 // Default to "off" for unknown configured levels
+	}
+}
+
+// moduleKey is the context key for the SDK module of a request.
+const moduleKey contextKey = "cortex-module"
+
+// withModule returns a context that records the SDK module of the request
+// made with it, for the lines the transport layers log.
+func withModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, moduleKey, module)
+}
+
+// moduleFromContext returns the SDK module recorded in ctx, if any.
+func moduleFromContext(ctx context.Context) string {
+	module, _ := ctx.Value(moduleKey).(string)
+	return module
+}
+
+// sdkLogger is the single place where the configured log level is enforced
+// on the lines the SDK logs, whatever the Logger they go to. It adds the
+// request ID of the context and the module of the client to the fields of
+// every line.
+type sdkLogger struct {
+	logger log.Logger
+	level  string // The configured log level
+	module string
+}
+
+// enabled reports whether lines of the given level are logged.
+func (l sdkLogger) enabled(level string) bool {
+	msgLevel := logLevelStringToInt(level)
+	return l.logger != nil && msgLevel >= 0 && logLevelStringToInt(l.level) >= msgLevel
+}
+
+func (l sdkLogger) Debug(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, "debug", msg, args)
+}
+
+func (l sdkLogger) Info(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, "info", msg, args)
+}
+
+func (l sdkLogger) Warn(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, "warn", msg, args)
+}
+
+func (l sdkLogger) Error(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, "error", msg, args)
+}
+
+func (l sdkLogger) log(ctx context.Context, level, msg string, args []map[string]any) {
+	if !l.enabled(level) {
+		return
+	}
+
+	fields := make(map[string]any)
+	if requestID := GetRequestID(ctx); requestID != "" {
+		fields[LogFieldRequestID] = requestID
+	}
+	if l.module != "" {
+		fields[LogFieldModule] = l.module
+	}
+	for _, arg := range args {
+		for key, value := range arg {
+			fields[key] = value
+		}
+	}
+
+	switch level {
+	case "debug":
+		l.logger.Debug(ctx, msg, fields)
+	case "info":
+		l.logger.Info(ctx, msg, fields)
+	case "warn":
+		l.logger.Warn(ctx, msg, fields)
+	default:
+		l.logger.Error(ctx, msg, fields)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logLine is a line logged to a recordingLogger.
+type logLine struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// recordingLogger is a log.Logger recording the lines it is given.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []logLine
+}
+
+func (l *recordingLogger) record(level, msg string, args []map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fields := make(map[string]any)
+	for _, arg := range args {
+		for key, value := range arg {
+			fields[key] = value
+		}
+	}
+	l.lines = append(l.lines, logLine{level, msg, fields})
+}
+
+func (l *recordingLogger) Debug(_ context.Context, msg string, args ...map[string]any) {
+	l.record("debug", msg, args)
+}
+
+func (l *recordingLogger) Info(_ context.Context, msg string, args ...map[string]any) {
+	l.record("info", msg, args)
+}
+
+func (l *recordingLogger) Warn(_ context.Context, msg string, args ...map[string]any) {
+	l.record("warn", msg, args)
+}
+
+func (l *recordingLogger) Error(_ context.Context, msg string, args ...map[string]any) {
+	l.record("error", msg, args)
+}
+
+func (l *recordingLogger) levels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var levels []string
+	for _, line := range l.lines {
+		levels = append(levels, line.level)
+	}
+	return levels
+}
+
+func TestSDKLogger_Enabled(t *testing.T) {
+	for configured, expected := range map[string][]bool{
+		// debug, info, warn, error
+		"debug": {true, true, true, true},
+		"INFO":  {false, true, true, true},
+		"warn":  {false, false, true, true},
+		"error": {false, false, false, true},
+		"quiet": {false, false, false, false},
+		"":      {false, false, false, false},
+	} {
+		logger := sdkLogger{logger: &recordingLogger{}, level: configured}
+		for i, level := range []string{"debug", "info", "warn", "error"} {
+			assert.Equal(t, expected[i], logger.enabled(level), "%s line at level %q", level, configured)
+		}
+	}
+
+	assert.False(t, sdkLogger{level: "debug"}.enabled("error"), "no logger")
+}
+
+func TestSDKLogger_Fields(t *testing.T) {
+	recorder := &recordingLogger{}
+	logger := sdkLogger{logger: recorder, level: "info", module: "platform"}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.Info(ctx, "started", map[string]any{"method": "GET"})
+	logger.Warn(context.Background(), "no request")
+
+	require.Len(t, recorder.lines, 2)
+	assert.Equal(t, map[string]any{"request_id": "req-1", "module": "platform", "method": "GET"}, recorder.lines[0].fields)
+	assert.Equal(t, map[string]any{"module": "platform"}, recorder.lines[1].fields)
+}
+
+func TestDo_LogLevel(t *testing.T) {
+	newClient := func(level string) (*Client, *recordingLogger) {
+		recorder := &recordingLogger{}
+		client, err := NewClientFromConfig(config.NewConfig(
+			config.WithCortexAPIURL("https://testing.com"),
+			config.WithCortexAPIKey("key"),
+			config.WithCortexAPIKeyID(1),
+			config.WithLogLevel(level),
+			config.WithLogger(recorder),
+			config.WithSkipLoggingTransport(true),
+		))
+		require.NoError(t, err)
+		client.testData = []*http.Response{{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+		}}
+		return client.ForModule("platform"), recorder
+	}
+
+	t.Run("should not log below the configured level", func(t *testing.T) {
+		client, recorder := newClient("warn")
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, recorder.levels())
+	})
+
+	t.Run("should label lines with the request ID and module", func(t *testing.T) {
+		client, recorder := newClient("info")
+		ctx := WithRequestID(context.Background(), "req-1")
+		_, err := client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"info", "info"}, recorder.levels())
+		for _, line := range recorder.lines {
+			assert.Equal(t, "req-1", line.fields[LogFieldRequestID], line.msg)
+			assert.Equal(t, "platform", line.fields[LogFieldModule], line.msg)
+		}
+		assert.Equal(t, "test", recorder.lines[0].fields["endpoint"])
+	})
+
+	t.Run("should apply the level to the lines of the modules", func(t *testing.T) {
+		client, recorder := newClient("info")
+		client.Logger().Debug(context.Background(), "hidden")
+		client.Logger().Info(context.Background(), "shown")
+		assert.Equal(t, []string{"info"}, recorder.levels())
+	})
+
+	t.Run("should label the lines of the transport with the module", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder := &recordingLogger{}
+		client := newTestClient(t, server.URL,
+			config.WithLogLevel("debug"),
+			config.WithLogger(recorder),
+			config.WithSkipLoggingTransport(false),
+		).ForModule("platform")
+		_, err := client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		var dumps int
+		for _, line := range recorder.lines {
+			assert.Equal(t, "platform", line.fields[LogFieldModule], line.msg)
+			if strings.Contains(line.msg, "GET /test") {
+				dumps++
+			}
+		}
+		assert.Equal(t, 1, dumps)
+	})
+}
@@ -0,0 +1,21 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+)
+
+// observeAttempt records a completed attempt in m. The status class of an
+// attempt that received no response is metrics.StatusClassError.
+func observeAttempt(m metrics.Metrics, labels metrics.Labels, resp *interceptor.Response, d time.Duration) {
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	m.ObserveRequest(labels, metrics.StatusClass(statusCode), d)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics records the measurements made by the client.
+type recordingMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	requests []string
+	retries  []string
+	labels   []metrics.Labels
+}
+
+func (m *recordingMetrics) AddInFlight(_ metrics.Labels, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight += delta
+	m.peak = max(m.peak, m.inFlight)
+}
+
+func (m *recordingMetrics) ObserveRequest(labels metrics.Labels, statusClass string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, statusClass)
+	m.labels = append(m.labels, labels)
+}
+
+func (m *recordingMetrics) ObserveRetry(_ metrics.Labels, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, reason)
+}
+
+func TestDo_Metrics(t *testing.T) {
+	t.Run("should measure every attempt and retry of the call", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder := &recordingMetrics{}
+		client := newTestClient(t, server.URL, config.WithRetryPolicy(retry.Constant{MaxRetries: 1}), config.WithMetrics(recorder))
+
+		_, err := client.ForModule("cloudsec").Do(context.Background(), http.MethodGet, "public_api/v1/test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"4xx", "2xx"}, recorder.requests)
+		assert.Equal(t, []string{"429"}, recorder.retries)
+		assert.Equal(t, 0, recorder.inFlight)
+		assert.Equal(t, 1, recorder.peak)
+		assert.Equal(t, metrics.Labels{Module: "cloudsec", Endpoint: "public_api/v1/test", Method: http.MethodGet}, recorder.labels[0])
+	})
+
+	t.Run("should label the endpoint without its path parameters", func(t *testing.T) {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder := &recordingMetrics{}
+		client := newTestClient(t, server.URL, config.WithMetrics(recorder))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "public_api/v1/test", &[]string{"id-1"}, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "/public_api/v1/test/id-1", path)
+		require.Len(t, recorder.labels, 1)
+		assert.Equal(t, "public_api/v1/test", recorder.labels[0].Endpoint)
+	})
+
+	t.Run("should measure attempts that fail on the network", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		serverURL := server.URL
+		server.Close()
+
+		recorder := &recordingMetrics{}
+		client := newTestClient(t, serverURL, config.WithRetryPolicy(retry.Constant{MaxRetries: 1}), config.WithMetrics(recorder))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "public_api/v1/test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+
+		assert.Equal(t, []string{metrics.StatusClassError, metrics.StatusClassError}, recorder.requests)
+		assert.Equal(t, []string{metrics.RetryReasonNetwork}, recorder.retries)
+		assert.Equal(t, 0, recorder.inFlight)
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+// operation identifies the SDK method that started a call, such as
+// CreatePolicy in the cloudsec module.
+type operation struct {
+	module string
+	name   string
+}
+
+// String returns the qualified name of the operation, e.g.
+// "cloudsec.CreatePolicy", or an empty string if it is unknown.
+func (o operation) String() string {
+	if o.module == "" || o.name == "" {
+		return ""
+	}
+	return o.module + "." + o.name
+}
@@ -0,0 +1,22 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperation_String(t *testing.T) {
+	t.Run("should qualify the operation with its module", func(t *testing.T) {
+		assert.Equal(t, "cloudsec.CreatePolicy", operation{module: "cloudsec", name: "CreatePolicy"}.String())
+	})
+
+	t.Run("should be empty when the module or the name is unknown", func(t *testing.T) {
+		assert.Empty(t, operation{}.String())
+		assert.Empty(t, operation{module: "cloudsec"}.String())
+		assert.Empty(t, operation{name: "CreatePolicy"}.String())
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/ratelimit"
+)
+
+// rateLimitTransport holds every request until the shared limiter admits it
+// and feeds each response back to the limiter so it can adapt.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *ratelimit.Limiter
+}
+
+// RoundTrip implements http.RoundTripper. The in-flight slot is held until
+// the response body is closed.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	t.limiter.Observe(resp)
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// NewRateLimitTransport wraps t so that requests are admitted by limiter.
+func NewRateLimitTransport(t http.RoundTripper, limiter *ratelimit.Limiter) *rateLimitTransport {
+	return &rateLimitTransport{transport: t, limiter: limiter}
+}
+
+// releasingBody releases the limiter slot when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_RateLimiter(t *testing.T) {
+	t.Run("should bound the requests in flight across clients sharing the limiter", func(t *testing.T) {
+		var current, peak atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		limiter := ratelimit.New(ratelimit.WithMaxInFlight(1))
+		clients := []*Client{
+			newTestClient(t, server.URL, config.WithRateLimiter(limiter)),
+			newTestClient(t, server.URL, config.WithRateLimiter(limiter)),
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func(c *Client) {
+				defer wg.Done()
+				_, err := c.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+				assert.NoError(t, err)
+			}(clients[i%2])
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), peak.Load())
+		assert.Zero(t, limiter.InFlight())
+	})
+
+	t.Run("should stop waiting for the limiter when the context ends", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		limiter := ratelimit.New(ratelimit.WithMaxInFlight(1))
+		release, err := limiter.Wait(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		client := newTestClient(t, server.URL, config.WithRateLimiter(limiter))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
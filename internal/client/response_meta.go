@@ -0,0 +1,66 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// serverRequestIDHeaders are the response headers the tenant may return its
+// own correlation ID in, by order of preference.
+var serverRequestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id"}
+
+// ResponseMeta describes how a call went on the wire, for correlating it
+// with the tenant's logs. It is filled by the WithCallResponseMeta call
+// option whether the call succeeds or fails.
+type ResponseMeta struct {
+	// StatusCode is the HTTP status of the last response, or 0 if no
+	// response was received.
+	StatusCode int
+	// Header holds the headers of the last response, or nil if no response
+	// was received.
+	Header http.Header
+	// RequestID is the X-Request-ID sent with every attempt of the call.
+	RequestID string
+	// ServerRequestID is the correlation ID returned by the tenant with
+	// the last response, or an empty string if it returned none.
+	ServerRequestID string
+	// Attempts is the number of attempts made, retries included.
+	Attempts int
+	// Duration is the time the whole call took, retries and the waits
+	// between them included.
+	Duration time.Duration
+	// LastAttemptDuration is the time the last attempt took.
+	LastAttemptDuration time.Duration
+}
+
+// WithCallResponseMeta returns a CallOption that fills meta with the
+// metadata of the call once it returns. Since call options apply to every
+// call made with the context, meta describes the last of them.
+func WithCallResponseMeta(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.responseMeta = meta
+	}
+}
+
+// responseMeta returns the metadata of the call recorded in state, which
+// took duration.
+func (s *callState) responseMeta(duration time.Duration) ResponseMeta {
+	meta := ResponseMeta{
+		StatusCode:          s.statusCode,
+		Header:              s.header,
+		RequestID:           s.requestID,
+		Attempts:            s.attempts,
+		Duration:            duration,
+		LastAttemptDuration: s.lastAttemptDuration,
+	}
+	for _, name := range serverRequestIDHeaders {
+		if id := s.header.Get(name); id != "" {
+			meta.ServerRequestID = id
+			break
+		}
+	}
+	return meta
+}
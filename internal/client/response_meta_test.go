@@ -0,0 +1,83 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCallResponseMeta(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Correlation-Id", "srv"+r.URL.Path)
+		if r.URL.Path == "/flaky" && calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"reply":{"err_code":404,"err_msg":"Not Found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"reply":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithRetryPolicy(retry.ExponentialJitter{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxRetries: 2}),
+	))
+	require.NoError(t, err)
+
+	t.Run("should describe a retried call", func(t *testing.T) {
+		var meta ResponseMeta
+		ctx := WithCallOptions(context.Background(), WithCallResponseMeta(&meta), WithCallRequestID("req-1"))
+		_, err := client.Do(ctx, http.MethodGet, "flaky", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, meta.StatusCode)
+		assert.Equal(t, "req-1", meta.RequestID)
+		assert.Equal(t, "srv/flaky", meta.ServerRequestID)
+		assert.Equal(t, "srv/flaky", meta.Header.Get("X-Correlation-Id"))
+		assert.Equal(t, 2, meta.Attempts)
+		assert.Positive(t, meta.LastAttemptDuration)
+		assert.GreaterOrEqual(t, meta.Duration, meta.LastAttemptDuration)
+	})
+
+	t.Run("should describe a failed call", func(t *testing.T) {
+		var meta ResponseMeta
+		ctx := WithCallOptions(context.Background(), WithCallResponseMeta(&meta))
+		_, err := client.Do(ctx, http.MethodGet, "missing", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+
+		assert.Equal(t, http.StatusNotFound, meta.StatusCode)
+		assert.NotEmpty(t, meta.RequestID)
+		assert.Equal(t, "srv/missing", meta.ServerRequestID)
+		assert.Equal(t, 1, meta.Attempts)
+	})
+
+	t.Run("should describe a call without response", func(t *testing.T) {
+		var meta ResponseMeta
+		ctx := WithCallOptions(context.Background(), WithCallResponseMeta(&meta))
+		_, err := client.Do(ctx, http.MethodGet, "%zz", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+
+		assert.Zero(t, meta.StatusCode)
+		assert.Nil(t, meta.Header)
+		assert.Empty(t, meta.ServerRequestID)
+		assert.Zero(t, meta.Attempts)
+	})
+}
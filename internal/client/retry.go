@@ -0,0 +1,158 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+)
+
+// retryAfterHeaders lists the response headers consulted, in order, for a
+// server-provided retry delay.
+var retryAfterHeaders = []string{
+	"Retry-After",
+	"RateLimit-Reset",
+	"X-RateLimit-Reset-After",
+}
+
+// parseRetryAfter returns the delay requested by the server via the
+// Retry-After header (in either delta-seconds or HTTP-date form) or one of the
+// common rate-limit reset headers. The boolean result is false if no usable
+// value was found.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	for _, name := range retryAfterHeaders {
+		value := strings.TrimSpace(header.Get(name))
+		if value == "" {
+			continue
+		}
+
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			if seconds < 0 {
+				continue
+			}
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+
+		// Only Retry-After may carry an HTTP-date.
+		if name != "Retry-After" {
+			continue
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			delay := date.Sub(now)
+			if delay < 0 {
+				delay = 0
+			}
+			return delay, true
+		}
+	}
+
+	return 0, false
+}
+
+// newRetryPolicy returns the retry policy configured in cfg, defaulting to
+// exponential backoff with jitter bounded by MaxRetries and RetryMaxDelay.
+// If a retry budget is configured, it is applied on top of the policy.
+func newRetryPolicy(cfg *config.Config) retry.Policy {
+	policy := cfg.RetryPolicy()
+	if policy == nil {
+		policy = retry.ExponentialJitter{
+			BaseDelay:  time.Second,
+			MaxDelay:   maxRetryDelay(cfg),
+			MaxRetries: cfg.MaxRetries(),
+		}
+	}
+	if budget := cfg.RetryBudget(); budget != nil {
+		policy = retry.WithBudget(policy, budget)
+	}
+	return policy
+}
+
+// maxRetryDelay returns the configured RetryMaxDelay, or 60 seconds if unset.
+func maxRetryDelay(cfg *config.Config) time.Duration {
+	retryMaxDelay := cfg.RetryMaxDelay()
+	if retryMaxDelay <= 0 {
+		retryMaxDelay = 60 // seconds
+	}
+	return time.Duration(retryMaxDelay) * time.Second
+}
+
+// nextRetry consults the given retry policy for the failed attempt and
+// returns how long to wait before the next one. A delay requested by the
+// server on a 429 or 503 response takes precedence over the policy's delay,
+// but is never allowed to exceed RetryMaxDelay.
+func (c *Client) nextRetry(policy retry.Policy, attempt retry.Attempt) (time.Duration, bool) {
+	ok, delay := policy.ShouldRetry(attempt)
+	if !ok {
+		return 0, false
+	}
+
+	if resp := attempt.Response; resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if serverDelay, found := parseRetryAfter(resp.Header, time.Now()); found {
+			delay = min(serverDelay, maxRetryDelay(c.config))
+		}
+	}
+	return delay, true
+}
+
+// sleepContext waits for the given duration or until ctx is done, whichever
+// happens first. It returns ctx.Err() if the context ended the wait early.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitForRetry blocks for the given retry delay, returning early with the
+// context error if ctx is cancelled. Sleeping is skipped entirely when the
+// client is serving canned test responses.
+func (c *Client) waitForRetry(ctx context.Context, d time.Duration) error {
+	if len(c.testData) != 0 {
+		return ctx.Err()
+	}
+	return sleepContext(ctx, d)
+}
+
+// retryCancelledError builds the error returned when the context ends while
+// the client is waiting between attempts.
+func retryCancelledError(err error, attempt int, retryStart time.Time) *errors.CortexCloudSdkError {
+	return errors.NewInternalSDKError(
+		errors.CodeContextCancellation,
+		fmt.Sprintf("request cancelled by context while waiting to retry (after %d attempt(s), %s spent retrying)", attempt+1, time.Since(retryStart).Round(time.Millisecond)),
+		err,
+	)
+}
+
+// retriesExhaustedError wraps the last API error once the retry policy has
+// declined any further attempts, recording how long was spent retrying.
+func retriesExhaustedError(apiErr *errors.CortexCloudAPIError, statusCode, attempt int, retryStart time.Time) *errors.CortexCloudSdkError {
+	return errors.NewCortexCloudSdkError(
+		errors.CodeRetriesExhausted,
+		fmt.Sprintf("API request failed with HTTP %d after %d attempt(s) (%s spent retrying)", statusCode, attempt+1, time.Since(retryStart).Round(time.Millisecond)),
+		nil,
+		&statusCode,
+		apiErr,
+	)
+}
@@ -0,0 +1,233 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/PaloAltoNetworks/cortex-cloud-go/errors"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should parse delta seconds", func(t *testing.T) {
+		h := http.Header{"Retry-After": []string{"7"}}
+		d, ok := parseRetryAfter(h, now)
+		assert.True(t, ok)
+		assert.Equal(t, 7*time.Second, d)
+	})
+
+	t.Run("should parse HTTP date", func(t *testing.T) {
+		h := http.Header{"Retry-After": []string{now.Add(30 * time.Second).Format(http.TimeFormat)}}
+		d, ok := parseRetryAfter(h, now)
+		assert.True(t, ok)
+		assert.Equal(t, 30*time.Second, d)
+	})
+
+	t.Run("should clamp HTTP date in the past to zero", func(t *testing.T) {
+		h := http.Header{"Retry-After": []string{now.Add(-time.Minute).Format(http.TimeFormat)}}
+		d, ok := parseRetryAfter(h, now)
+		assert.True(t, ok)
+		assert.Zero(t, d)
+	})
+
+	t.Run("should fall back to rate limit reset header", func(t *testing.T) {
+		h := http.Header{"Ratelimit-Reset": []string{"3"}}
+		d, ok := parseRetryAfter(h, now)
+		assert.True(t, ok)
+		assert.Equal(t, 3*time.Second, d)
+	})
+
+	t.Run("should ignore missing and invalid values", func(t *testing.T) {
+		_, ok := parseRetryAfter(nil, now)
+		assert.False(t, ok)
+		_, ok = parseRetryAfter(http.Header{"Retry-After": []string{"soon"}}, now)
+		assert.False(t, ok)
+		_, ok = parseRetryAfter(http.Header{"Retry-After": []string{"-1"}}, now)
+		assert.False(t, ok)
+	})
+}
+
+func TestNextRetry(t *testing.T) {
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL("https://api.example.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithRetryMaxDelay(5),
+	))
+	require.NoError(t, err)
+
+	attempt := func(resp *http.Response) retry.Attempt {
+		return retry.Attempt{Method: http.MethodGet, Response: resp}
+	}
+
+	t.Run("should honor Retry-After on 429", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"2"}},
+		}
+		d, ok := client.nextRetry(client.retrier, attempt(resp))
+		assert.True(t, ok)
+		assert.Equal(t, 2*time.Second, d)
+	})
+
+	t.Run("should cap Retry-After at RetryMaxDelay", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Retry-After": []string{"120"}},
+		}
+		d, ok := client.nextRetry(client.retrier, attempt(resp))
+		assert.True(t, ok)
+		assert.Equal(t, 5*time.Second, d)
+	})
+
+	t.Run("should ignore Retry-After on other statuses", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Header:     http.Header{"Retry-After": []string{"0"}},
+		}
+		d, ok := client.nextRetry(client.retrier, attempt(resp))
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, d, 750*time.Millisecond)
+		assert.LessOrEqual(t, d, 1250*time.Millisecond)
+	})
+
+	t.Run("should not retry statuses the policy rejects", func(t *testing.T) {
+		_, ok := client.nextRetry(client.retrier, attempt(&http.Response{StatusCode: http.StatusInternalServerError}))
+		assert.False(t, ok)
+	})
+}
+
+func TestDo_CustomRetryPolicy(t *testing.T) {
+	newResp := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`))}
+	}
+	baseOpts := []config.Option{
+		config.WithCortexAPIURL("https://testing.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+	}
+
+	t.Run("should retry 500 when policy allows it", func(t *testing.T) {
+		client, err := NewClientFromConfig(config.NewConfig(append(baseOpts, config.WithRetryPolicy(retry.Constant{
+			MaxRetries:        3,
+			RetryableStatuses: []int{http.StatusInternalServerError},
+		}))...))
+		require.NoError(t, err)
+		client.testData = []*http.Response{newResp(500), newResp(500), newResp(200)}
+
+		_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, client.testIndex)
+	})
+
+	t.Run("should fail fast with NoRetry", func(t *testing.T) {
+		client, err := NewClientFromConfig(config.NewConfig(append(baseOpts, config.WithRetryPolicy(retry.NoRetry{}))...))
+		require.NoError(t, err)
+		client.testData = []*http.Response{newResp(503), newResp(200)}
+
+		_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, client.testIndex)
+	})
+
+	t.Run("should stop retrying once the budget is spent", func(t *testing.T) {
+		client, err := NewClientFromConfig(config.NewConfig(append(baseOpts,
+			config.WithMaxRetries(5),
+			config.WithRetryBudget(retry.NewBudget(1, time.Minute)),
+		)...))
+		require.NoError(t, err)
+		client.testData = []*http.Response{newResp(503), newResp(503), newResp(503), newResp(200)}
+
+		_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 2, client.testIndex)
+	})
+}
+
+func TestSleepContext(t *testing.T) {
+	t.Run("should return nil after the delay elapses", func(t *testing.T) {
+		assert.NoError(t, sleepContext(context.Background(), time.Millisecond))
+	})
+
+	t.Run("should return early when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := sleepContext(ctx, time.Minute)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func TestDo_RetryBackoffHonorsContext(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL(server.URL),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(3),
+		config.WithRetryMaxDelay(60),
+		config.WithSkipLoggingTransport(true),
+	))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Contains(t, err.Error(), "request cancelled by context while waiting to retry")
+	assert.Contains(t, err.Error(), "spent retrying")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDo_RetriesExhaustedReportsElapsedTime(t *testing.T) {
+	client, err := NewClientFromConfig(config.NewConfig(
+		config.WithCortexAPIURL("https://testing.com"),
+		config.WithCortexAPIKey("key"),
+		config.WithCortexAPIKeyID(1),
+		config.WithMaxRetries(1),
+	))
+	require.NoError(t, err)
+
+	newResp := func() *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       io.NopCloser(strings.NewReader(`{"err_code":503,"err_msg":"unavailable"}`)),
+		}
+	}
+	client.testData = []*http.Response{newResp(), newResp()}
+
+	_, err = client.Do(context.Background(), http.MethodGet, "test", nil, nil, nil, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 2 attempt(s)")
+	assert.Contains(t, err.Error(), "spent retrying")
+
+	var apiErr *sdkerrors.CortexCloudAPIError
+	assert.ErrorAs(t, err, &apiErr)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+)
+
+// secretRedactor is implemented by the configured transports that keep what
+// they are sent, such as vcr.Recorder, to redact the API keys of the client
+// from it.
+type secretRedactor interface {
+	RedactSecrets(secrets ...string)
+}
+
+// redactingProvider is a credentials provider handing the API keys it
+// retrieves to a secretRedactor before they sign a request.
+type redactingProvider struct {
+	credentials.Provider
+	redactor secretRedactor
+}
+
+func (p redactingProvider) Retrieve(ctx context.Context) (credentials.Credentials, error) {
+	creds, err := p.Provider.Retrieve(ctx)
+	if err == nil {
+		p.redactor.RedactSecrets(creds.APIKey)
+	}
+	return creds, err
+}
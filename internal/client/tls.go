@@ -0,0 +1,194 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+)
+
+// newTransport returns the base transport of a client built from cfg: the
+// configured transport, or a clone of http.DefaultTransport, set up with the
+// TLS and proxy settings of cfg. A configured transport other than an
+// *http.Transport is used as is, and cannot be combined with those settings.
+func newTransport(cfg *config.Config) (http.RoundTripper, error) {
+	var transport *http.Transport
+	switch base := cfg.Transport().(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		// SkipSSLVerify alone never applied to a configured transport
+		if !cfg.HasTLSSettings() {
+			return base, nil
+		}
+		transport = base.Clone()
+	default:
+		if cfg.HasTLSSettings() {
+			return nil, fmt.Errorf("TLS and proxy settings cannot be applied to a transport of type %T", base)
+		}
+		return base, nil
+	}
+
+	tlsConfig, err := newTLSConfig(cfg, transport.TLSClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if transport.Proxy, err = newProxy(cfg, transport.Proxy); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// newTLSConfig returns a copy of base, which may be nil, with the TLS
+// settings of cfg applied.
+func newTLSConfig(cfg *config.Config, base *tls.Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if base != nil {
+		tlsConfig = base.Clone()
+	}
+	if cfg.SkipSSLVerify() {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if version := cfg.MinTLSVersion(); version != 0 {
+		tlsConfig.MinVersion = version
+	}
+
+	bundle := cfg.CABundle()
+	if path := cfg.CABundleFile(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		bundle = append(append([]byte{}, bundle...), data...)
+	}
+	if len(bundle) > 0 {
+		roots := tlsConfig.RootCAs
+		if roots == nil {
+			var err error
+			if roots, err = x509.SystemCertPool(); err != nil {
+				roots = x509.NewCertPool()
+			}
+		} else {
+			roots = roots.Clone()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle holds no PEM-encoded certificate")
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	certFile, keyFile := cfg.ClientCertificateFiles()
+	switch {
+	case cfg.ClientCertificate() != nil:
+		tlsConfig.Certificates = []tls.Certificate{*cfg.ClientCertificate()}
+	case certFile != "" || keyFile != "":
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if pins := cfg.PinnedPublicKeys(); len(pins) > 0 {
+		digests, err := parsePins(pins)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.VerifyConnection = verifyPins(digests)
+	}
+	return tlsConfig, nil
+}
+
+// parsePins decodes the base64-encoded SHA-256 digests of the pinned public
+// keys.
+func parsePins(pins []string) (map[[sha256.Size]byte]bool, error) {
+	digests := make(map[[sha256.Size]byte]bool, len(pins))
+	for _, pin := range pins {
+		digest, ok := decodePin(pin)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key pin %q: expected a base64-encoded SHA-256 digest", pin)
+		}
+		digests[digest] = true
+	}
+	return digests, nil
+}
+
+// decodePin decodes a pin, trying the "sha256/" prefix and the "sha256//"
+// prefix used by curl; base64 digests may themselves start with a slash.
+func decodePin(pin string) ([sha256.Size]byte, bool) {
+	for _, prefix := range []string{"sha256//", "sha256/", ""} {
+		encoded, ok := strings.CutPrefix(pin, prefix)
+		if !ok {
+			continue
+		}
+		if digest, err := base64.StdEncoding.DecodeString(encoded); err == nil && len(digest) == sha256.Size {
+			return [sha256.Size]byte(digest), true
+		}
+	}
+	return [sha256.Size]byte{}, false
+}
+
+// verifyPins returns a tls.Config.VerifyConnection function failing unless
+// the certificate chain has one of the pinned public keys. Only the verified
+// chains are trusted, since the peer may send any certificate along with its
+// own; without verification, only the peer's own certificate is.
+func verifyPins(digests map[[sha256.Size]byte]bool) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		var certs []*x509.Certificate
+		for _, chain := range cs.VerifiedChains {
+			certs = append(certs, chain...)
+		}
+		if len(cs.VerifiedChains) == 0 && len(cs.PeerCertificates) > 0 {
+			certs = cs.PeerCertificates[:1]
+		}
+		for _, cert := range certs {
+			if digests[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+		return fmt.Errorf("tenant certificate does not match any pinned public key")
+	}
+}
+
+// newProxy returns the proxy function of a transport: base, or the proxy set
+// by WithProxy, authenticated with the credentials set by WithProxyAuth.
+func newProxy(cfg *config.Config, base func(*http.Request) (*url.URL, error)) (func(*http.Request) (*url.URL, error), error) {
+	proxy := base
+	if raw := cfg.Proxy(); raw != "" {
+		proxyURL, err := url.Parse(raw)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			// The URL may hold credentials, so it is not part of the error
+			return nil, fmt.Errorf("invalid proxy URL: expected scheme://host:port")
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	username, password := cfg.ProxyAuth()
+	if proxy == nil || username == "" {
+		return proxy, nil
+	}
+	return func(r *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(r)
+		if proxyURL == nil || err != nil {
+			return proxyURL, err
+		}
+		authenticated := *proxyURL
+		authenticated.User = url.UserPassword(username, password)
+		return &authenticated, nil
+	}, nil
+}
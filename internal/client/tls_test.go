@@ -0,0 +1,266 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doTLS makes a call with c, without retrying it.
+func doTLS(c *Client) error {
+	ctx := WithCallOptions(context.Background(), WithCallMaxRetries(0))
+	_, err := c.Do(ctx, http.MethodGet, "test", nil, nil, nil, nil, nil)
+	return err
+}
+
+func certificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func publicKeyPin(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// newCertificate returns a certificate signed by parent, or self-signed if
+// parent is nil.
+func newCertificate(t *testing.T, parent *tls.Certificate, isCA bool) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "cortex-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, any(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestTLS_CABundleAndPinning(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	bundle := certificatePEM(server.Certificate())
+
+	t.Run("should reject an unknown CA by default", func(t *testing.T) {
+		c := newTestClient(t, server.URL)
+		assert.ErrorContains(t, doTLS(c), "certificate")
+	})
+
+	t.Run("should trust the CA bundle", func(t *testing.T) {
+		c := newTestClient(t, server.URL, config.WithCABundle(bundle))
+		assert.NoError(t, doTLS(c))
+	})
+
+	t.Run("should trust the CA bundle file", func(t *testing.T) {
+		path := writeTempFile(t, bundle)
+		c := newTestClient(t, server.URL, config.WithCABundleFile(path))
+		assert.NoError(t, doTLS(c))
+	})
+
+	t.Run("should reject an invalid CA bundle", func(t *testing.T) {
+		_, err := NewClientFromConfig(testConfig(server.URL, config.WithCABundle([]byte("not a certificate"))))
+		assert.ErrorContains(t, err, "CA bundle holds no PEM-encoded certificate")
+	})
+
+	t.Run("should accept a pinned public key", func(t *testing.T) {
+		c := newTestClient(t, server.URL, config.WithCABundle(bundle), config.WithPinnedPublicKeys(publicKeyPin(server.Certificate())))
+		assert.NoError(t, doTLS(c))
+	})
+
+	t.Run("should reject a certificate whose key is not pinned", func(t *testing.T) {
+		other := newCertificate(t, nil, true)
+		c := newTestClient(t, server.URL, config.WithCABundle(bundle), config.WithPinnedPublicKeys(publicKeyPin(other.Leaf)))
+		assert.ErrorContains(t, doTLS(c), "does not match any pinned public key")
+	})
+
+	t.Run("should enforce pins without certificate verification", func(t *testing.T) {
+		other := newCertificate(t, nil, true)
+		c := newTestClient(t, server.URL, config.WithSkipSSLVerify(true), config.WithPinnedPublicKeys(publicKeyPin(other.Leaf)))
+		assert.ErrorContains(t, doTLS(c), "does not match any pinned public key")
+	})
+
+	t.Run("should reject an invalid pin", func(t *testing.T) {
+		_, err := NewClientFromConfig(testConfig(server.URL, config.WithPinnedPublicKeys("sha256/abc")))
+		assert.ErrorContains(t, err, "invalid public key pin")
+	})
+
+	t.Run("should enforce the minimum TLS version", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		c := newTestClient(t, server.URL, config.WithCABundle(certificatePEM(server.Certificate())), config.WithMinTLSVersion(tls.VersionTLS13))
+		assert.ErrorContains(t, doTLS(c), "protocol version")
+	})
+}
+
+func TestTLS_ClientCertificate(t *testing.T) {
+	ca := newCertificate(t, nil, true)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.Leaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	bundle := config.WithCABundle(certificatePEM(server.Certificate()))
+
+	cert := newCertificate(t, &ca, false)
+
+	t.Run("should present the client certificate", func(t *testing.T) {
+		c := newTestClient(t, server.URL, bundle, config.WithClientCertificate(cert))
+		assert.NoError(t, doTLS(c))
+	})
+
+	t.Run("should load the client certificate from files", func(t *testing.T) {
+		keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+		require.NoError(t, err)
+		certFile := writeTempFile(t, certificatePEM(cert.Leaf))
+		keyFile := writeTempFile(t, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+		c := newTestClient(t, server.URL, bundle, config.WithClientCertificateFile(certFile, keyFile))
+		assert.NoError(t, doTLS(c))
+	})
+
+	t.Run("should fail without a client certificate", func(t *testing.T) {
+		c := newTestClient(t, server.URL, bundle)
+		assert.Error(t, doTLS(c))
+	})
+
+	t.Run("should require both files", func(t *testing.T) {
+		_, err := NewClientFromConfig(testConfig(server.URL, bundle, config.WithClientCertificateFile("cert.pem", "")))
+		assert.ErrorContains(t, err, "requires both a certificate and a key file")
+	})
+}
+
+func TestTLS_Proxy(t *testing.T) {
+	var proxyAuth, target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		target = r.URL.String()
+		_, _ = w.Write([]byte(`{"reply":"ok"}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	t.Run("should send requests through the authenticated proxy", func(t *testing.T) {
+		c := newTestClient(t, "http://tenant.example.com", config.WithProxy(proxy.URL), config.WithProxyAuth("user", "secret"))
+		require.NoError(t, doTLS(c))
+		assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")), proxyAuth)
+		assert.Contains(t, target, "http://tenant.example.com/")
+	})
+
+	t.Run("should reject an invalid proxy URL without leaking it", func(t *testing.T) {
+		_, err := NewClientFromConfig(testConfig("http://tenant.example.com", config.WithProxy("user:secret@proxy")))
+		assert.ErrorContains(t, err, "invalid proxy URL")
+		assert.NotContains(t, err.Error(), "secret")
+	})
+}
+
+func TestTLS_Transport(t *testing.T) {
+	t.Run("should keep a configured transport without TLS settings", func(t *testing.T) {
+		transport := &http.Transport{}
+		c := newTestClient(t, "https://api.example.com", config.WithTransport(transport), config.WithSkipSSLVerify(true))
+		assert.Same(t, transport, c.transport)
+	})
+
+	t.Run("should apply TLS settings to a copy of a configured transport", func(t *testing.T) {
+		transport := &http.Transport{TLSClientConfig: &tls.Config{ServerName: "tenant"}}
+		c := newTestClient(t, "https://api.example.com", config.WithTransport(transport), config.WithMinTLSVersion(tls.VersionTLS13))
+		require.IsType(t, &http.Transport{}, c.transport)
+		assert.NotSame(t, transport, c.transport)
+		assert.Equal(t, uint16(tls.VersionTLS13), c.transport.(*http.Transport).TLSClientConfig.MinVersion)
+		assert.Equal(t, "tenant", c.transport.(*http.Transport).TLSClientConfig.ServerName)
+		assert.Zero(t, transport.TLSClientConfig.MinVersion)
+	})
+
+	t.Run("should reject TLS settings on a wrapping transport", func(t *testing.T) {
+		_, err := NewClientFromConfig(testConfig("https://api.example.com", config.WithTransport(roundTripperFunc(http.DefaultTransport.RoundTrip)), config.WithMinTLSVersion(tls.VersionTLS13)))
+		assert.ErrorContains(t, err, "cannot be applied to a transport of type")
+	})
+
+	t.Run("should build a new transport for derived clients with TLS settings", func(t *testing.T) {
+		base := newTestClient(t, "https://api.example.com")
+
+		derived, err := base.Derive(config.WithTimeout(5))
+		require.NoError(t, err)
+		assert.Same(t, base.transport, derived.transport)
+
+		derived, err = base.Derive(config.WithMinTLSVersion(tls.VersionTLS13))
+		require.NoError(t, err)
+		assert.NotSame(t, base.transport, derived.transport)
+	})
+}
+
+// roundTripperFunc is an http.RoundTripper wrapping another one.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func writeTempFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.pem")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestDecodePin(t *testing.T) {
+	digest := sha256.Sum256([]byte("key"))
+	encoded := base64.StdEncoding.EncodeToString(digest[:])
+
+	t.Run("should decode a pin with or without its prefix", func(t *testing.T) {
+		for _, pin := range []string{encoded, "sha256/" + encoded, "sha256//" + encoded} {
+			decoded, ok := decodePin(pin)
+			assert.True(t, ok, pin)
+			assert.Equal(t, digest, decoded, pin)
+		}
+	})
+
+	t.Run("should decode a digest whose encoding starts with a slash", func(t *testing.T) {
+		slashed := [sha256.Size]byte{0xfc}
+		encoded := base64.StdEncoding.EncodeToString(slashed[:])
+		require.True(t, encoded[0] == '/')
+		decoded, ok := decodePin("sha256/" + encoded)
+		assert.True(t, ok)
+		assert.Equal(t, slashed, decoded)
+	})
+
+	t.Run("should reject a pin that is not a SHA-256 digest", func(t *testing.T) {
+		_, ok := decodePin("sha256/abc")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
+)
+
+// callState records what happened during a call, for reporting once the call
+// has completed.
+type callState struct {
+	op         operation
+	requestID  string
+	attempts   int
+	statusCode int         // The status code of the last response, or 0 if none was received.
+	header     http.Header // The headers of the last response, or nil if none was received.
+
+	lastAttemptDuration time.Duration // The time the last attempt took.
+
+	// requestBody and responseBody are the marshaled input and the body of
+	// the last response, reported by the diagnostic bundle of a panic.
+	requestBody  []byte
+	responseBody []byte
+}
+
+// noopSpan is the span used when tracing is disabled.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...tracing.Attribute) {}
+func (noopSpan) RecordError(error)                  {}
+func (noopSpan) End()                               {}
+
+// startCallSpan starts the span covering a call, named after the SDK method
+// that made it.
+func (c *Client) startCallSpan(ctx context.Context, state *callState, method, endpoint string) (context.Context, tracing.Span) {
+	tracer := c.config.Tracer()
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	name := state.op.String()
+	if name == "" {
+		name = method + " " + endpoint
+	}
+	ctx, span := tracer.Start(ctx, name, tracing.SpanKindInternal)
+	span.SetAttributes(
+		tracing.String(tracing.AttrModule, state.op.module),
+		tracing.String(tracing.AttrOperation, state.op.name),
+		tracing.String(tracing.AttrEndpoint, endpoint),
+		tracing.String(tracing.AttrMethod, method),
+	)
+	return ctx, span
+}
+
+// endCallSpan records the outcome of the call on its span and ends it.
+func endCallSpan(span tracing.Span, state *callState, err error) {
+	span.SetAttributes(
+		tracing.String(tracing.AttrRequestID, state.requestID),
+		tracing.Int(tracing.AttrRetryCount, max(state.attempts-1, 0)),
+	)
+	if state.statusCode != 0 {
+		span.SetAttributes(tracing.Int(tracing.AttrStatusCode, state.statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// startAttemptSpan starts the span covering an attempt and injects its trace
+// context into the request headers.
+func (c *Client) startAttemptSpan(ctx context.Context, req *interceptor.Request, requestID string) (context.Context, tracing.Span) {
+	tracer := c.config.Tracer()
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := tracer.Start(ctx, "HTTP "+req.Method, tracing.SpanKindClient)
+	span.SetAttributes(
+		tracing.String(tracing.AttrEndpoint, req.Endpoint),
+		tracing.String(tracing.AttrMethod, req.Method),
+		tracing.String(tracing.AttrRequestID, requestID),
+		tracing.Int(tracing.AttrResendCount, req.Attempt),
+	)
+	tracer.Inject(ctx, req.Header)
+	return ctx, span
+}
+
+// endAttemptSpan records the outcome of the attempt on its span and ends it.
+func endAttemptSpan(span tracing.Span, resp *interceptor.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(tracing.Int(tracing.AttrStatusCode, resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/config"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo_Tracing(t *testing.T) {
+	t.Run("should trace the call with a child span per attempt", func(t *testing.T) {
+		var (
+			mu           sync.Mutex
+			traceparents []string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			traceparents = append(traceparents, r.Header.Get(tracing.TraceparentHeader))
+			first := len(traceparents) == 1
+			mu.Unlock()
+			if first {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"reply":true}`))
+		}))
+		defer server.Close()
+
+		recorder := tracing.NewRecorder()
+		client := newTestClient(t, server.URL, config.WithRetryPolicy(retry.Constant{MaxRetries: 1}), config.WithTracer(recorder))
+
+		ctx := WithRequestID(context.Background(), "req_traced")
+		_, err := client.Do(ctx, http.MethodGet, "public_api/v1/test", nil, nil, nil, nil, nil)
+		require.NoError(t, err)
+
+		spans := recorder.Spans()
+		require.Len(t, spans, 3)
+		first, second, call := spans[0], spans[1], spans[2]
+
+		// Calls that name no operation are named after the request
+		assert.Equal(t, "GET public_api/v1/test", call.Name)
+		assert.Equal(t, tracing.SpanKindInternal, call.Kind)
+		assert.Equal(t, "public_api/v1/test", call.Attributes[tracing.AttrEndpoint])
+		assert.Equal(t, "req_traced", call.Attributes[tracing.AttrRequestID])
+		assert.Equal(t, 1, call.Attributes[tracing.AttrRetryCount])
+		assert.Equal(t, http.StatusOK, call.Attributes[tracing.AttrStatusCode])
+		assert.NoError(t, call.Err)
+
+		for i, attempt := range []tracing.RecordedSpan{first, second} {
+			assert.Equal(t, "HTTP GET", attempt.Name)
+			assert.Equal(t, tracing.SpanKindClient, attempt.Kind)
+			assert.Equal(t, call.TraceID, attempt.TraceID)
+			assert.Equal(t, call.SpanID, attempt.ParentSpanID)
+			assert.Equal(t, i, attempt.Attributes[tracing.AttrResendCount])
+			assert.Equal(t, "req_traced", attempt.Attributes[tracing.AttrRequestID])
+			assert.Equal(t, "00-"+attempt.TraceID+"-"+attempt.SpanID+"-01", traceparents[i])
+		}
+		assert.Equal(t, http.StatusServiceUnavailable, first.Attributes[tracing.AttrStatusCode])
+		assert.Error(t, first.Err)
+		assert.Equal(t, http.StatusOK, second.Attributes[tracing.AttrStatusCode])
+		assert.NoError(t, second.Err)
+	})
+
+	t.Run("should name the span after the operation of the module", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		recorder := tracing.NewRecorder()
+		client := newTestClient(t, server.URL, config.WithTracer(recorder)).ForModule("cloudsec")
+
+		_, err := client.Do(context.Background(), http.MethodPost, "public_api/v1/policy", nil, nil, nil, nil, &DoOptions{Operation: "CreatePolicy"})
+		require.NoError(t, err)
+
+		spans := recorder.Spans()
+		require.Len(t, spans, 2)
+		call := spans[1]
+		assert.Equal(t, "cloudsec.CreatePolicy", call.Name)
+		assert.Equal(t, "cloudsec", call.Attributes[tracing.AttrModule])
+		assert.Equal(t, "CreatePolicy", call.Attributes[tracing.AttrOperation])
+	})
+
+	t.Run("should record the failure of the call", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		recorder := tracing.NewRecorder()
+		client := newTestClient(t, server.URL, config.WithTracer(recorder))
+
+		_, err := client.Do(context.Background(), http.MethodGet, "public_api/v1/test", nil, nil, nil, nil, nil)
+		require.Error(t, err)
+
+		spans := recorder.Spans()
+		require.Len(t, spans, 2)
+		call := spans[1]
+		assert.Equal(t, err, call.Err)
+		assert.Equal(t, http.StatusNotFound, call.Attributes[tracing.AttrStatusCode])
+		assert.Equal(t, 0, call.Attributes[tracing.AttrRetryCount])
+		assert.NotEmpty(t, call.Attributes[tracing.AttrRequestID])
+	})
+}
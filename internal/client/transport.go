@@ -6,11 +6,9 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
-	"strings"
 )
 
 // InternalClient abstracts the core client's logging and log-level settings.
@@ -22,6 +20,7 @@ type InternalClient interface {
 type transport struct {
 	transport http.RoundTripper
 	client    InternalClient
+	dump      *dumpPolicy
 }
 
 // sensitiveRequestHeaders lists header names whose values are redacted in debug logs.
@@ -45,11 +44,22 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	logLevelIsDebug := t.client.LogLevelIsSetTo("debug")
 
+	// Repeated exchanges with the same endpoint may be sampled out, their
+	// count being reported by the next dump
+	skipped := 0
+	if logLevelIsDebug && t.dump.sampler != nil {
+		logLevelIsDebug, skipped = t.dump.sampler.sample(req.Method + " " + req.URL.Path)
+	}
+
 	if logLevelIsDebug {
 		reqData, err := httputil.DumpRequestOut(req, true)
 		if err == nil {
 			redacted := redactSensitiveHeaders(reqData, sensitiveRequestHeaders)
-			t.client.Log(ctx, "debug", fmt.Sprintf(logReqMsg, requestID, prettyPrintJsonLines(redacted)))
+			msg := fmt.Sprintf(logReqMsg, requestID, t.dump.formatDump(redacted))
+			if skipped > 0 {
+				msg = fmt.Sprintf("[INFO] [%s] %d similar exchanges with %s %s were not dumped%s", requestID, skipped, req.Method, req.URL.Path, msg)
+			}
+			t.client.Log(ctx, "debug", msg)
 		} else {
 			t.client.Log(ctx, "error", fmt.Sprintf("[ERROR] Failed to dump HTTP request: %v", err))
 		}
@@ -65,7 +75,7 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		respData, err := httputil.DumpResponse(resp, true)
 		if err == nil {
 			redacted := redactSensitiveHeaders(respData, sensitiveResponseHeaders)
-			t.client.Log(ctx, "debug", fmt.Sprintf(logRespMsg, requestID, prettyPrintJsonLines(redacted)))
+			t.client.Log(ctx, "debug", fmt.Sprintf(logRespMsg, requestID, t.dump.formatDump(redacted)))
 		} else {
 			t.client.Log(ctx, "error", fmt.Sprintf("[ERROR] Failed to dump HTTP response: %v", err))
 		}
@@ -75,8 +85,9 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 // NewTransport wraps t with debug-level request/response logging via client.
+// Bodies are dumped with the built-in redaction and size limit.
 func NewTransport(t http.RoundTripper, client InternalClient) *transport {
-	return &transport{t, client}
+	return &transport{t, client, defaultDumpPolicy()}
 }
 
 // redactSensitiveHeaders replaces the value of any header in dump whose name
@@ -114,19 +125,6 @@ func redactSensitiveHeaders(dump []byte, sensitive map[string]struct{}) []byte {
 	return append(out, rest...)
 }
 
-// prettyPrintJsonLines pretty-prints any line in b that is valid JSON.
-func prettyPrintJsonLines(b []byte) string {
-	parts := strings.Split(string(b), "\n")
-	for i, p := range parts {
-		if b := []byte(p); json.Valid(b) {
-			var out bytes.Buffer
-			_ = json.Indent(&out, b, "", " ")
-			parts[i] = out.String()
-		}
-	}
-	return strings.Join(parts, "\n")
-}
-
 const logReqMsg = `
 ---[ REQUEST %s ]-----------------------------
 %s
@@ -185,7 +185,7 @@ func TestTransport_RoundTrip_DebugDumpRedactsRequestCredentials(t *testing.T) {
 	logs := fake.joined()
 	assert.NotContains(t, logs, "super-secret-api-key", "raw API key must not appear in debug log")
 	assert.Contains(t, logs, redactedValue)
-	// prettyPrintJsonLines reformats the body JSON, so match the field name only.
+	// The dump pretty-prints the body JSON, so match the field name only.
 	assert.Contains(t, logs, `"x"`)
 	// Nonce and timestamp are no longer redacted — they are not secrets.
 	assert.Contains(t, logs, "deadbeef")
@@ -5,15 +5,27 @@
 package config
 
 import (
+	"cmp"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/auth"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/circuit"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/interceptor"
 	cortexLog "github.com/PaloAltoNetworks/cortex-cloud-go/log"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/ratelimit"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/retry"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
 )
 
 const (
@@ -29,26 +41,72 @@ const (
 	CORTEXCLOUD_MAX_RETRIES_ENV_VAR            = "CORTEXCLOUD_MAX_RETRIES"
 	CORTEXCLOUD_RETRY_MAX_DELAY_ENV_VAR        = "CORTEXCLOUD_RETRY_MAX_DELAY"
 	CORTEXCLOUD_CRASH_STACK_DIR_ENV_VAR        = "CORTEXCLOUD_CRASH_STACK_DIR"
+	CORTEXCLOUD_SKIP_CRASH_BUNDLE_ENV_VAR      = "CORTEXCLOUD_SKIP_CRASH_BUNDLE"
 	CORTEXCLOUD_LOG_LEVEL_ENV_VAR              = "CORTEXCLOUD_LOG_LEVEL"
 	CORTEXCLOUD_SKIP_LOGGING_TRANSPORT_ENV_VAR = "CORTEXCLOUD_SKIP_LOGGING_TRANSPORT"
+	CORTEXCLOUD_MAX_RESPONSE_SIZE_ENV_VAR      = "CORTEXCLOUD_MAX_RESPONSE_SIZE"
+	CORTEXCLOUD_COMPRESSION_ENV_VAR            = "CORTEXCLOUD_COMPRESSION"
+	CORTEXCLOUD_CA_BUNDLE_FILE_ENV_VAR         = "CORTEXCLOUD_CA_BUNDLE_FILE"
+	CORTEXCLOUD_CLIENT_CERT_FILE_ENV_VAR       = "CORTEXCLOUD_CLIENT_CERT_FILE"
+	CORTEXCLOUD_CLIENT_KEY_FILE_ENV_VAR        = "CORTEXCLOUD_CLIENT_KEY_FILE"
+	CORTEXCLOUD_MIN_TLS_VERSION_ENV_VAR        = "CORTEXCLOUD_MIN_TLS_VERSION"
+	CORTEXCLOUD_LOG_DUMP_MAX_SIZE_ENV_VAR      = "CORTEXCLOUD_LOG_DUMP_MAX_SIZE"
 )
 
+// DefaultLogDumpMaxSize is the default maximum size of a body in debug dumps,
+// in bytes.
+const DefaultLogDumpMaxSize = 16 * 1024
+
 type Config struct {
-	cortexAPIURL         string
-	cortexAPIKey         string
-	cortexAPIKeyID       int
-	cortexAPIKeyType     string
-	headers              map[string]string
-	agent                string
-	skipSSLVerify        bool
-	transport            *http.Transport
-	timeout              int
-	maxRetries           int
-	retryMaxDelay        int
-	crashStackDir        string
-	logLevel             string
-	logger               cortexLog.Logger
-	skipLoggingTransport bool
+	cortexAPIURL          string
+	cortexAPIKey          string
+	cortexAPIKeyID        int
+	cortexAPIKeyType      string
+	credentialsProvider   credentials.Provider
+	authenticator         auth.Authenticator
+	headers               map[string]string
+	agent                 string
+	defaultAgent          string // The agent used if no layer sets one
+	skipSSLVerify         bool
+	transport             http.RoundTripper
+	caBundle              []byte
+	caBundleFile          string
+	clientCertificate     *tls.Certificate
+	clientCertFile        string
+	clientKeyFile         string
+	minTLSVersion         uint16
+	proxy                 string
+	proxyUsername         string
+	proxyPassword         string
+	pinnedPublicKeys      []string
+	timeout               int
+	maxRetries            int
+	retryMaxDelay         int
+	maxResponseSize       int64
+	compression           bool
+	uncompressedEndpoints []string
+	retryPolicy           retry.Policy
+	retryBudget           *retry.Budget
+	rateLimiter           *ratelimit.Limiter
+	circuitBreaker        *circuit.Breaker
+	interceptors          []interceptor.Interceptor
+	tracer                tracing.Tracer
+	metrics               metrics.Metrics
+	crashStackDir         string
+	skipCrashBundle       bool
+	logLevel              string
+	logger                cortexLog.Logger
+	skipLoggingTransport  bool
+	logRedactedFields     []string
+	logDumpMaxSize        int
+	logDumpSampleFirst    int
+	logDumpSampleEvery    int
+
+	profile    string            // The profile the settings were loaded from
+	configFile string            // The configuration file the profile was read from
+	profileErr error             // The error loading an explicitly selected profile
+	sources    map[string]Source // The source of each setting set by a layer
+	layer      Source            // The layer whose settings are being applied
 }
 
 // CortexAPIURL returns the API URL for the Cortex.
@@ -63,6 +121,14 @@ func (c *Config) CortexAPIKey() string { return c.cortexAPIKey }
 // CortexAPIKeyID returns the Cortex API key ID.
 func (c *Config) CortexAPIKeyID() int { return c.cortexAPIKeyID }
 
+// CredentialsProvider returns the credentials provider, or nil if the static
+// API key and key ID are used.
+func (c *Config) CredentialsProvider() credentials.Provider { return c.credentialsProvider }
+
+// Authenticator returns the authenticator signing requests, or nil if they
+// are signed with the API key.
+func (c *Config) Authenticator() auth.Authenticator { return c.authenticator }
+
 // Headers returns the HTTP headers.
 func (c *Config) Headers() map[string]string { return c.headers }
 
@@ -73,7 +139,39 @@ func (c *Config) Agent() string { return c.agent }
 func (c *Config) SkipSSLVerify() bool { return c.skipSSLVerify }
 
 // Transport returns the HTTP transport.
-func (c *Config) Transport() *http.Transport { return c.transport }
+func (c *Config) Transport() http.RoundTripper { return c.transport }
+
+// CABundle returns the PEM-encoded certificates trusted in addition to the
+// system roots.
+func (c *Config) CABundle() []byte { return c.caBundle }
+
+// CABundleFile returns the path of the PEM file of certificates trusted in
+// addition to the system roots.
+func (c *Config) CABundleFile() string { return c.caBundleFile }
+
+// ClientCertificate returns the client certificate presented for mutual TLS,
+// or nil if it is loaded from files or not set.
+func (c *Config) ClientCertificate() *tls.Certificate { return c.clientCertificate }
+
+// ClientCertificateFiles returns the paths of the PEM files of the client
+// certificate and its private key.
+func (c *Config) ClientCertificateFiles() (certFile, keyFile string) {
+	return c.clientCertFile, c.clientKeyFile
+}
+
+// MinTLSVersion returns the minimum TLS version, or 0 for the Go default.
+func (c *Config) MinTLSVersion() uint16 { return c.minTLSVersion }
+
+// Proxy returns the URL of the HTTP proxy, or an empty string if the proxy
+// is read from the environment.
+func (c *Config) Proxy() string { return c.proxy }
+
+// ProxyAuth returns the credentials used to authenticate to the proxy.
+func (c *Config) ProxyAuth() (username, password string) { return c.proxyUsername, c.proxyPassword }
+
+// PinnedPublicKeys returns the pins of the public keys the tenant certificate
+// chain must contain.
+func (c *Config) PinnedPublicKeys() []string { return c.pinnedPublicKeys }
 
 // Timeout returns the HTTP timeout.
 func (c *Config) Timeout() int { return c.timeout }
@@ -84,9 +182,46 @@ func (c *Config) MaxRetries() int { return c.maxRetries }
 // RetryMaxDelay returns the maximum retry delay.
 func (c *Config) RetryMaxDelay() int { return c.retryMaxDelay }
 
+// RetryPolicy returns the retry policy.
+func (c *Config) RetryPolicy() retry.Policy { return c.retryPolicy }
+
+// RetryBudget returns the retry budget.
+func (c *Config) RetryBudget() *retry.Budget { return c.retryBudget }
+
+// RateLimiter returns the client-side rate limiter.
+func (c *Config) RateLimiter() *ratelimit.Limiter { return c.rateLimiter }
+
+// CircuitBreaker returns the circuit breaker.
+func (c *Config) CircuitBreaker() *circuit.Breaker { return c.circuitBreaker }
+
+// Interceptors returns the interceptors called for every attempt, outermost first.
+func (c *Config) Interceptors() []interceptor.Interceptor { return c.interceptors }
+
+// Tracer returns the tracer, or nil if tracing is disabled.
+func (c *Config) Tracer() tracing.Tracer { return c.tracer }
+
+// Metrics returns the metrics recorder.
+func (c *Config) Metrics() metrics.Metrics { return c.metrics }
+
+// MaxResponseSize returns the maximum size of a response body in bytes, or 0
+// if it is unlimited.
+func (c *Config) MaxResponseSize() int64 { return c.maxResponseSize }
+
+// Compression returns whether request bodies are gzipped and gzip-encoded
+// responses are negotiated.
+func (c *Config) Compression() bool { return c.compression }
+
+// UncompressedEndpoints returns the endpoints whose request bodies are never
+// gzipped.
+func (c *Config) UncompressedEndpoints() []string { return c.uncompressedEndpoints }
+
 // CrashStackDir returns the crash stack directory.
 func (c *Config) CrashStackDir() string { return c.crashStackDir }
 
+// SkipCrashBundle returns whether to skip writing the diagnostic bundle of a
+// recovered panic.
+func (c *Config) SkipCrashBundle() bool { return c.skipCrashBundle }
+
 // LogLevel returns the log level.
 func (c *Config) LogLevel() string { return c.logLevel }
 
@@ -96,24 +231,62 @@ func (c *Config) Logger() cortexLog.Logger { return c.logger }
 // SkipLoggingTransport returns whether to skip logging transport.
 func (c *Config) SkipLoggingTransport() bool { return c.skipLoggingTransport }
 
+// LogRedactedFields returns the JSON paths of the body fields redacted in
+// debug dumps, in addition to the built-in ones.
+func (c *Config) LogRedactedFields() []string { return c.logRedactedFields }
+
+// LogDumpMaxSize returns the maximum size of a body in debug dumps in bytes,
+// or 0 if it is unlimited.
+func (c *Config) LogDumpMaxSize() int { return c.logDumpMaxSize }
+
+// LogDumpSampling returns how many exchanges with each endpoint are dumped
+// per minute before sampling starts, and how often exchanges are dumped
+// afterwards. Every exchange is dumped when both are 0.
+func (c *Config) LogDumpSampling() (first, thereafter int) {
+	return c.logDumpSampleFirst, c.logDumpSampleEvery
+}
+
+// Profile returns the name of the profile the settings were loaded from, or
+// an empty string if no profile was loaded.
+func (c *Config) Profile() string { return c.profile }
+
+// ConfigFile returns the path of the configuration file the profile was read
+// from, or an empty string if no profile was loaded.
+func (c *Config) ConfigFile() string { return c.configFile }
+
 // UnmarshalJSON unmarshals the provided byte array into the calling Config struct.
 func (c *Config) UnmarshalJSON(data []byte) error {
 	type Alias struct {
-		CortexAPIURL         string            `json:"api_url"`
-		CortexAPIKey         string            `json:"api_key"`
-		CortexAPIKeyID       int               `json:"api_key_id"`
-		CortexAPIKeyType     string            `json:"api_key_type"`
-		Headers              map[string]string `json:"headers"`
-		Agent                string            `json:"agent"`
-		SkipSSLVerify        bool              `json:"skip_ssl_verify"`
-		Transport            *http.Transport   `json:"-"`
-		Timeout              int               `json:"timeout"`
-		MaxRetries           int               `json:"max_retries"`
-		RetryMaxDelay        int               `json:"retry_max_delay"`
-		CrashStackDir        string            `json:"crash_stack_dir"`
-		LogLevel             string            `json:"log_level"`
-		Logger               cortexLog.Logger  `json:"-"`
-		SkipLoggingTransport bool              `json:"skip_logging_transport"`
+		CortexAPIURL          string                    `json:"api_url"`
+		CortexAPIKey          string                    `json:"api_key"`
+		CortexAPIKeyID        int                       `json:"api_key_id"`
+		CortexAPIKeyType      string                    `json:"api_key_type"`
+		CredentialsProvider   credentials.Provider      `json:"-"`
+		Authenticator         auth.Authenticator        `json:"-"`
+		Headers               map[string]string         `json:"headers"`
+		Agent                 string                    `json:"agent"`
+		SkipSSLVerify         bool                      `json:"skip_ssl_verify"`
+		Transport             http.RoundTripper         `json:"-"`
+		Timeout               int                       `json:"timeout"`
+		MaxRetries            int                       `json:"max_retries"`
+		RetryMaxDelay         int                       `json:"retry_max_delay"`
+		MaxResponseSize       int64                     `json:"max_response_size"`
+		Compression           bool                      `json:"compression"`
+		UncompressedEndpoints []string                  `json:"uncompressed_endpoints"`
+		RetryPolicy           retry.Policy              `json:"-"`
+		RetryBudget           *retry.Budget             `json:"-"`
+		RateLimiter           *ratelimit.Limiter        `json:"-"`
+		CircuitBreaker        *circuit.Breaker          `json:"-"`
+		Interceptors          []interceptor.Interceptor `json:"-"`
+		Tracer                tracing.Tracer            `json:"-"`
+		Metrics               metrics.Metrics           `json:"-"`
+		CrashStackDir         string                    `json:"crash_stack_dir"`
+		SkipCrashBundle       bool                      `json:"skip_crash_bundle"`
+		LogLevel              string                    `json:"log_level"`
+		Logger                cortexLog.Logger          `json:"-"`
+		SkipLoggingTransport  bool                      `json:"skip_logging_transport"`
+		LogRedactedFields     []string                  `json:"log_redacted_fields"`
+		LogDumpMaxSize        int                       `json:"log_dump_max_size"`
 	}
 
 	var aux Alias
@@ -131,13 +304,25 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.timeout = aux.Timeout
 	c.maxRetries = aux.MaxRetries
 	c.retryMaxDelay = aux.RetryMaxDelay
+	c.maxResponseSize = aux.MaxResponseSize
+	c.compression = aux.Compression
+	c.uncompressedEndpoints = aux.UncompressedEndpoints
 	c.crashStackDir = aux.CrashStackDir
+	c.skipCrashBundle = aux.SkipCrashBundle
 	c.logLevel = aux.LogLevel
 	c.skipLoggingTransport = aux.SkipLoggingTransport
+	c.logRedactedFields = aux.LogRedactedFields
+	c.logDumpMaxSize = aux.LogDumpMaxSize
 
 	return nil
 }
 
+// NewConfig returns a configuration layered by increasing precedence from
+// the defaults, the profile of the configuration file, the environment
+// variables and the given options. The configuration file and profile are
+// selected by WithConfigFile and WithProfile or by the
+// CORTEXCLOUD_CONFIG_FILE and CORTEXCLOUD_PROFILE environment variables; see
+// loadProfile.
 func NewConfig(opts ...Option) *Config {
 	config := &Config{
 		cortexAPIURL:         "",
@@ -145,7 +330,6 @@ func NewConfig(opts ...Option) *Config {
 		headers:              make(map[string]string),
 		agent:                "",
 		skipSSLVerify:        false,
-		transport:            http.DefaultTransport.(*http.Transport),
 		timeout:              30, // 30 seconds
 		maxRetries:           3,
 		retryMaxDelay:        60, // 60 seconds
@@ -153,82 +337,202 @@ func NewConfig(opts ...Option) *Config {
 		logLevel:             "info",
 		logger:               nil,
 		skipLoggingTransport: false,
+		logDumpMaxSize:       DefaultLogDumpMaxSize,
+		sources:              make(map[string]Source),
 	}
 
-	config.overwriteFromEnvVars()
-
+	config.layer = SourceOption
 	for _, opt := range opts {
 		opt(config)
 	}
 
+	// The options select the profile, whose settings are layered beneath
+	// them with those of the environment variables
+	layers := &Config{headers: make(map[string]string)}
+	layers.layer = SourceProfile
+	layers.loadProfile(config.configFile, config.profile)
+	layers.layer = SourceEnv
+	layers.overwriteFromEnvVars()
+	config.underlay(layers)
+
 	return config
 }
 
+// layeredSetting copies a setting that the configuration file or the
+// environment variables may set from one configuration to another.
+type layeredSetting struct {
+	copy  func(dst, src *Config)
+	merge bool // Whether options add to the setting rather than replace it
+}
+
+// layeredSettings are the settings that the configuration file or the
+// environment variables may set, keyed by setting name. Settings that merge
+// keep the values of the destination over those of the source, like the
+// options that set different fields of the same setting, e.g. WithProxy and
+// WithProxyAuth.
+var layeredSettings = map[string]layeredSetting{
+	"api_url":      {copy: func(dst, src *Config) { dst.cortexAPIURL = src.cortexAPIURL }},
+	"api_key":      {copy: func(dst, src *Config) { dst.cortexAPIKey = src.cortexAPIKey }},
+	"api_key_id":   {copy: func(dst, src *Config) { dst.cortexAPIKeyID = src.cortexAPIKeyID }},
+	"api_key_type": {copy: func(dst, src *Config) { dst.cortexAPIKeyType = src.cortexAPIKeyType }},
+	"headers": {merge: true, copy: func(dst, src *Config) {
+		headers := maps.Clone(src.headers)
+		maps.Copy(headers, dst.headers)
+		dst.headers = headers
+	}},
+	"agent":           {copy: func(dst, src *Config) { dst.agent = src.agent }},
+	"skip_ssl_verify": {copy: func(dst, src *Config) { dst.skipSSLVerify = src.skipSSLVerify }},
+	"ca_bundle": {merge: true, copy: func(dst, src *Config) {
+		dst.caBundleFile = cmp.Or(dst.caBundleFile, src.caBundleFile)
+	}},
+	"client_certificate": {merge: true, copy: func(dst, src *Config) {
+		if dst.clientCertFile == "" && dst.clientKeyFile == "" {
+			dst.clientCertFile, dst.clientKeyFile = src.clientCertFile, src.clientKeyFile
+		}
+	}},
+	"min_tls_version": {copy: func(dst, src *Config) { dst.minTLSVersion = src.minTLSVersion }},
+	"proxy": {merge: true, copy: func(dst, src *Config) {
+		dst.proxy = cmp.Or(dst.proxy, src.proxy)
+	}},
+	"pinned_public_keys": {copy: func(dst, src *Config) { dst.pinnedPublicKeys = slices.Clone(src.pinnedPublicKeys) }},
+	"timeout":            {copy: func(dst, src *Config) { dst.timeout = src.timeout }},
+	"max_retries":        {copy: func(dst, src *Config) { dst.maxRetries = src.maxRetries }},
+	"retry_max_delay":    {copy: func(dst, src *Config) { dst.retryMaxDelay = src.retryMaxDelay }},
+	"max_response_size":  {copy: func(dst, src *Config) { dst.maxResponseSize = src.maxResponseSize }},
+	"compression":        {copy: func(dst, src *Config) { dst.compression = src.compression }},
+	"uncompressed_endpoints": {merge: true, copy: func(dst, src *Config) {
+		dst.uncompressedEndpoints = append(slices.Clone(src.uncompressedEndpoints), dst.uncompressedEndpoints...)
+	}},
+	"crash_stack_dir":        {copy: func(dst, src *Config) { dst.crashStackDir = src.crashStackDir }},
+	"skip_crash_bundle":      {copy: func(dst, src *Config) { dst.skipCrashBundle = src.skipCrashBundle }},
+	"log_level":              {copy: func(dst, src *Config) { dst.logLevel = src.logLevel }},
+	"skip_logging_transport": {copy: func(dst, src *Config) { dst.skipLoggingTransport = src.skipLoggingTransport }},
+	"log_redacted_fields": {merge: true, copy: func(dst, src *Config) {
+		dst.logRedactedFields = append(slices.Clone(src.logRedactedFields), dst.logRedactedFields...)
+	}},
+	"log_dump_max_size": {copy: func(dst, src *Config) { dst.logDumpMaxSize = src.logDumpMaxSize }},
+}
+
+// underlay sets the settings of the profile and environment variable layers
+// of layers that the options did not set, and adds to those they add to.
+func (c *Config) underlay(layers *Config) {
+	for setting, source := range layers.sources {
+		layered := layeredSettings[setting]
+		if c.Source(setting) == SourceOption {
+			if layered.merge {
+				layered.copy(c, layers)
+			}
+			continue
+		}
+		layered.copy(c, layers)
+		c.sources[setting] = source
+	}
+
+	if layers.configFile != "" {
+		c.profile, c.configFile = layers.profile, layers.configFile
+	}
+	c.profileErr = layers.profileErr
+}
+
+// NewConfigFromFile returns a configuration loaded from the configuration
+// file at filepath, as NewConfig(WithConfigFile(filepath)) does, failing if
+// the file or its profile cannot be loaded.
 func NewConfigFromFile(filepath string) (*Config, error) {
-	cBytes, err := os.ReadFile(filepath)
-	if err != nil {
-		return nil, fmt.Errorf("Error reading configuration file: %s", err)
-	}
-
-	var cFile Config
-	if err = json.Unmarshal(cBytes, &cFile); err != nil {
-		return nil, fmt.Errorf("Error unmarshalling configuration file: %s", err)
-	}
-
-	return NewConfig(
-		WithCortexAPIURL(cFile.cortexAPIURL),
-		WithCortexAPIKey(cFile.cortexAPIKey),
-		WithCortexAPIKeyID(cFile.cortexAPIKeyID),
-		WithCortexAPIKeyType(cFile.cortexAPIKeyType),
-		WithHeaders(cFile.headers),
-		WithAgent(cFile.agent),
-		WithSkipSSLVerify(cFile.skipSSLVerify),
-		WithTransport(cFile.transport),
-		WithTimeout(cFile.timeout),
-		WithMaxRetries(cFile.maxRetries),
-		WithRetryMaxDelay(cFile.retryMaxDelay),
-		WithCrashStackDir(cFile.crashStackDir),
-		WithLogLevel(cFile.logLevel),
-		WithLogger(cFile.logger),
-		WithSkipLoggingTransport(cFile.skipLoggingTransport),
-	), nil
+	config := NewConfig(WithConfigFile(filepath))
+	if config.profileErr != nil {
+		return nil, config.profileErr
+	}
+	return config, nil
+}
+
+// With returns a copy of the configuration with the given options applied.
+// Unlike NewConfig, it does not read the configuration file or the
+// environment variables again.
+func (c *Config) With(opts ...Option) *Config {
+	clone := *c
+	clone.headers = maps.Clone(c.headers)
+	clone.uncompressedEndpoints = slices.Clone(c.uncompressedEndpoints)
+	clone.interceptors = slices.Clone(c.interceptors)
+	clone.pinnedPublicKeys = slices.Clone(c.pinnedPublicKeys)
+	clone.logRedactedFields = slices.Clone(c.logRedactedFields)
+	clone.sources = maps.Clone(c.sources)
+	clone.layer = SourceOption
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	return &clone
 }
 
 func (c *Config) GetOptions() []Option {
-	return []Option{
+	opts := []Option{
 		WithCortexAPIURL(c.cortexAPIURL),
 		WithCortexAPIKey(c.cortexAPIKey),
 		WithCortexAPIKeyID(c.cortexAPIKeyID),
 		WithCortexAPIKeyType(c.cortexAPIKeyType),
+		WithCredentialsProvider(c.credentialsProvider),
+		WithAuthenticator(c.authenticator),
 		WithHeaders(c.headers),
 		WithAgent(c.agent),
 		WithSkipSSLVerify(c.skipSSLVerify),
 		WithTransport(c.transport),
+		WithCABundle(c.caBundle),
+		WithCABundleFile(c.caBundleFile),
+		WithClientCertificateFile(c.clientCertFile, c.clientKeyFile),
+		WithMinTLSVersion(c.minTLSVersion),
+		WithProxy(c.proxy),
+		WithProxyAuth(c.proxyUsername, c.proxyPassword),
+		WithPinnedPublicKeys(c.pinnedPublicKeys...),
 		WithTimeout(c.timeout),
 		WithMaxRetries(c.maxRetries),
 		WithRetryMaxDelay(c.retryMaxDelay),
+		WithMaxResponseSize(c.maxResponseSize),
+		WithCompression(c.compression),
+		WithUncompressedEndpoints(c.uncompressedEndpoints...),
+		WithRetryPolicy(c.retryPolicy),
+		WithRetryBudget(c.retryBudget),
+		WithRateLimiter(c.rateLimiter),
+		WithCircuitBreaker(c.circuitBreaker),
+		WithInterceptors(c.interceptors...),
+		WithTracer(c.tracer),
+		WithMetrics(c.metrics),
 		WithCrashStackDir(c.crashStackDir),
+		WithSkipCrashBundle(c.skipCrashBundle),
 		WithLogLevel(c.logLevel),
 		WithLogger(c.logger),
 		WithSkipLoggingTransport(c.skipLoggingTransport),
+		WithLogRedactedFields(c.logRedactedFields...),
+		WithLogDumpMaxSize(c.logDumpMaxSize),
+		WithLogDumpSampling(c.logDumpSampleFirst, c.logDumpSampleEvery),
 	}
+	if c.clientCertificate != nil {
+		opts = append(opts, WithClientCertificate(*c.clientCertificate))
+	}
+	return opts
 }
 
 func (c Config) Validate() error {
+	if c.profileErr != nil {
+		return c.profileErr
+	}
+
 	if c.cortexAPIURL == "" {
 		return fmt.Errorf("API URL not set")
 	}
 
-	if c.cortexAPIKey == "" {
-		return fmt.Errorf("API key not set")
-	}
+	// The credentials provider supplies the key and key ID of each request,
+	// and a custom authenticator needs neither
+	if c.credentialsProvider == nil && c.authenticator == nil {
+		if c.cortexAPIKey == "" {
+			return fmt.Errorf("API key not set")
+		}
 
-	if c.cortexAPIKeyID == 0 {
-		return fmt.Errorf("API key ID not set")
-	}
+		if c.cortexAPIKeyID == 0 {
+			return fmt.Errorf("API key ID not set")
+		}
 
-	if c.cortexAPIKeyID < 0 {
-		return fmt.Errorf("Invalid API key ID: %d", c.cortexAPIKeyID)
+		if c.cortexAPIKeyID < 0 {
+			return fmt.Errorf("Invalid API key ID: %d", c.cortexAPIKeyID)
+		}
 	}
 
 	if c.cortexAPIKeyType != "standard" && c.cortexAPIKeyType != "advanced" {
@@ -241,25 +545,34 @@ func (c Config) Validate() error {
 // SetDefaults sets default values for the configuration.
 func (c *Config) SetDefaults() {
 	if c.logger == nil {
-		c.logger = cortexLog.DefaultLogger{Logger: log.Default()}
+		c.logger = cortexLog.NewSlogAdapter(cortexLog.NewStdHandler(log.Default()))
+	}
+	if c.metrics == nil {
+		c.metrics = metrics.Nop{}
 	}
 	if c.cortexAPIKeyType == "" {
 		c.cortexAPIKeyType = "advanced"
 	}
+	if c.agent == "" {
+		c.agent = c.defaultAgent
+	}
 }
 
 func (c *Config) overwriteFromEnvVars() {
 	if envAPIURL, ok := os.LookupEnv(CORTEXCLOUD_API_URL_ENV_VAR); ok {
 		c.cortexAPIURL = envAPIURL
+		c.mark("api_url")
 	}
 
 	if envAPIKey, ok := os.LookupEnv(CORTEXCLOUD_API_KEY_ENV_VAR); ok {
 		c.cortexAPIKey = envAPIKey
+		c.mark("api_key")
 	}
 
 	if envAPIKeyID, ok := os.LookupEnv(CORTEXCLOUD_API_KEY_ID_ENV_VAR); ok {
 		if parsedInt, err := strconv.Atoi(envAPIKeyID); err == nil {
 			c.cortexAPIKeyID = parsedInt
+			c.mark("api_key_id")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_API_KEY_ID_ENV_VAR, envAPIKeyID)
 		}
@@ -267,6 +580,7 @@ func (c *Config) overwriteFromEnvVars() {
 
 	if envAPIKeyType, ok := os.LookupEnv(CORTEXCLOUD_API_KEY_TYPE_ENV_VAR); ok {
 		c.cortexAPIKeyType = envAPIKeyType
+		c.mark("api_key_type")
 	}
 
 	if envHeaders, ok := os.LookupEnv(CORTEXCLOUD_HEADERS_ENV_VAR); ok {
@@ -281,23 +595,51 @@ func (c *Config) overwriteFromEnvVars() {
 				c.headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 			}
 		}
+		c.mark("headers")
 	}
 
 	if envAgent, ok := os.LookupEnv(CORTEXCLOUD_AGENT_ENV_VAR); ok {
 		c.agent = envAgent
+		c.mark("agent")
 	}
 
 	if envSkipSSLVerify, ok := os.LookupEnv(CORTEXCLOUD_SKIP_SSL_VERIFY_ENV_VAR); ok {
 		if parsedBool, err := strconv.ParseBool(envSkipSSLVerify); err == nil {
 			c.skipSSLVerify = parsedBool
+			c.mark("skip_ssl_verify")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected true/false.\n", CORTEXCLOUD_SKIP_SSL_VERIFY_ENV_VAR, envSkipSSLVerify)
 		}
 	}
 
+	if envCABundleFile, ok := os.LookupEnv(CORTEXCLOUD_CA_BUNDLE_FILE_ENV_VAR); ok {
+		c.caBundleFile = envCABundleFile
+		c.mark("ca_bundle")
+	}
+
+	if envClientCertFile, ok := os.LookupEnv(CORTEXCLOUD_CLIENT_CERT_FILE_ENV_VAR); ok {
+		c.clientCertFile = envClientCertFile
+		c.mark("client_certificate")
+	}
+
+	if envClientKeyFile, ok := os.LookupEnv(CORTEXCLOUD_CLIENT_KEY_FILE_ENV_VAR); ok {
+		c.clientKeyFile = envClientKeyFile
+		c.mark("client_certificate")
+	}
+
+	if envMinTLSVersion, ok := os.LookupEnv(CORTEXCLOUD_MIN_TLS_VERSION_ENV_VAR); ok {
+		if version, err := ParseTLSVersion(envMinTLSVersion); err == nil {
+			c.minTLSVersion = version
+			c.mark("min_tls_version")
+		} else {
+			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected 1.0, 1.1, 1.2 or 1.3.\n", CORTEXCLOUD_MIN_TLS_VERSION_ENV_VAR, envMinTLSVersion)
+		}
+	}
+
 	if envTimeout, ok := os.LookupEnv(CORTEXCLOUD_TIMEOUT_ENV_VAR); ok {
 		if parsedInt, err := strconv.Atoi(envTimeout); err == nil {
 			c.timeout = parsedInt
+			c.mark("timeout")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_TIMEOUT_ENV_VAR, envTimeout)
 		}
@@ -306,6 +648,7 @@ func (c *Config) overwriteFromEnvVars() {
 	if envMaxRetries, ok := os.LookupEnv(CORTEXCLOUD_MAX_RETRIES_ENV_VAR); ok {
 		if parsedInt, err := strconv.Atoi(envMaxRetries); err == nil {
 			c.maxRetries = parsedInt
+			c.mark("max_retries")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_MAX_RETRIES_ENV_VAR, envMaxRetries)
 		}
@@ -314,24 +657,64 @@ func (c *Config) overwriteFromEnvVars() {
 	if envRetryMaxDelay, ok := os.LookupEnv(CORTEXCLOUD_RETRY_MAX_DELAY_ENV_VAR); ok {
 		if parsedInt, err := strconv.Atoi(envRetryMaxDelay); err == nil {
 			c.retryMaxDelay = parsedInt
+			c.mark("retry_max_delay")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_RETRY_MAX_DELAY_ENV_VAR, envRetryMaxDelay)
 		}
 	}
 
+	if envMaxResponseSize, ok := os.LookupEnv(CORTEXCLOUD_MAX_RESPONSE_SIZE_ENV_VAR); ok {
+		if parsedInt, err := strconv.ParseInt(envMaxResponseSize, 10, 64); err == nil {
+			c.maxResponseSize = parsedInt
+			c.mark("max_response_size")
+		} else {
+			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_MAX_RESPONSE_SIZE_ENV_VAR, envMaxResponseSize)
+		}
+	}
+
+	if envCompression, ok := os.LookupEnv(CORTEXCLOUD_COMPRESSION_ENV_VAR); ok {
+		if parsedBool, err := strconv.ParseBool(envCompression); err == nil {
+			c.compression = parsedBool
+			c.mark("compression")
+		} else {
+			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected true/false.\n", CORTEXCLOUD_COMPRESSION_ENV_VAR, envCompression)
+		}
+	}
+
 	if envCrashStackDir, ok := os.LookupEnv(CORTEXCLOUD_CRASH_STACK_DIR_ENV_VAR); ok {
 		c.crashStackDir = envCrashStackDir
+		c.mark("crash_stack_dir")
+	}
+
+	if envSkipCrashBundle, ok := os.LookupEnv(CORTEXCLOUD_SKIP_CRASH_BUNDLE_ENV_VAR); ok {
+		if parsedBool, err := strconv.ParseBool(envSkipCrashBundle); err == nil {
+			c.skipCrashBundle = parsedBool
+			c.mark("skip_crash_bundle")
+		} else {
+			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected true/false.\n", CORTEXCLOUD_SKIP_CRASH_BUNDLE_ENV_VAR, envSkipCrashBundle)
+		}
 	}
 
 	if envLogLevel, ok := os.LookupEnv(CORTEXCLOUD_LOG_LEVEL_ENV_VAR); ok {
 		c.logLevel = envLogLevel
+		c.mark("log_level")
 	}
 
 	if envSkipLoggingTransport, ok := os.LookupEnv(CORTEXCLOUD_SKIP_LOGGING_TRANSPORT_ENV_VAR); ok {
 		if parsedBool, err := strconv.ParseBool(envSkipLoggingTransport); err == nil {
 			c.skipLoggingTransport = parsedBool
+			c.mark("skip_logging_transport")
 		} else {
 			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected true/false.\n", CORTEXCLOUD_SKIP_LOGGING_TRANSPORT_ENV_VAR, envSkipLoggingTransport)
 		}
 	}
+
+	if envLogDumpMaxSize, ok := os.LookupEnv(CORTEXCLOUD_LOG_DUMP_MAX_SIZE_ENV_VAR); ok {
+		if parsedInt, err := strconv.Atoi(envLogDumpMaxSize); err == nil {
+			c.logDumpMaxSize = parsedInt
+			c.mark("log_dump_max_size")
+		} else {
+			fmt.Printf("Warning: Invalid value for %s environment variable: %s. Expected integer.\n", CORTEXCLOUD_LOG_DUMP_MAX_SIZE_ENV_VAR, envLogDumpMaxSize)
+		}
+	}
 }
@@ -0,0 +1,272 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// CORTEXCLOUD_PROFILE_ENV_VAR selects the profile of the configuration
+	// file.
+	CORTEXCLOUD_PROFILE_ENV_VAR = "CORTEXCLOUD_PROFILE"
+
+	// DefaultProfile is the profile used when none is selected and the
+	// configuration file does not name a default one.
+	DefaultProfile = "default"
+)
+
+// DefaultConfigFile returns the path of the configuration file read when
+// neither WithConfigFile nor the CORTEXCLOUD_CONFIG_FILE environment
+// variable name one: ~/.cortex/config. It returns an empty string if the
+// home directory is unknown.
+func DefaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cortex", "config")
+}
+
+// configFile is the content of a configuration file. A file may either hold
+// named profiles, e.g. in YAML:
+//
+//	default_profile: prod
+//	profiles:
+//	  prod:
+//	    api_url: https://prod.xdr.us.paloaltonetworks.com
+//	    api_key_id: 1
+//	  staging:
+//	    api_url: https://staging.xdr.us.paloaltonetworks.com
+//	    api_key_id: 2
+//
+// or, like the flat JSON files read by earlier versions, the settings of a
+// single profile, which is named DefaultProfile. Like those versions, flat
+// files may hold keys that are not settings.
+type configFile struct {
+	DefaultProfile string                                `json:"default_profile"`
+	Profiles       map[string]map[string]json.RawMessage `json:"profiles"`
+
+	flat bool // Whether the file holds the settings of a single profile
+}
+
+// readConfigFile reads the configuration file at path, in JSON, YAML or TOML
+// depending on its extension. Files without a known extension, such as
+// DefaultConfigFile, are read as JSON or YAML depending on their content.
+func readConfigFile(path string) (*configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if !slices.Contains([]string{"json", "yaml", "yml", "toml"}, format) && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		format = "json"
+	}
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &doc)
+	case "toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+	var file configFile
+	if err := json.Unmarshal(normalized, &file); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if _, ok := doc["profiles"]; !ok {
+		var settings map[string]json.RawMessage
+		if err := json.Unmarshal(normalized, &settings); err != nil {
+			return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+		}
+		delete(settings, "default_profile")
+		file.Profiles = map[string]map[string]json.RawMessage{DefaultProfile: settings}
+		file.flat = true
+	}
+	return &file, nil
+}
+
+// loadProfile applies the settings of a profile of the configuration file.
+// The file is the one named by WithConfigFile, the CORTEXCLOUD_CONFIG_FILE
+// environment variable or DefaultConfigFile, and the profile the one named
+// by WithProfile, the CORTEXCLOUD_PROFILE environment variable, the
+// default_profile key of the file or DefaultProfile, in that order.
+//
+// The default file and profile are optional: failing to load a file or a
+// profile that was explicitly selected is reported by Validate, while
+// failing to load the default ones only prints a warning.
+func (c *Config) loadProfile(path, name string) {
+	if path == "" {
+		path = os.Getenv(CORTEXCLOUD_CONFIG_FILE_ENV_VAR)
+	}
+	explicitPath := path != ""
+	if path == "" {
+		path = DefaultConfigFile()
+	}
+	if name == "" {
+		name = os.Getenv(CORTEXCLOUD_PROFILE_ENV_VAR)
+	}
+	explicitName := name != ""
+	if path == "" {
+		if explicitName {
+			c.profileErr = fmt.Errorf("profile %q selected, but no configuration file found", name)
+		}
+		return
+	}
+	fail := func(err error) {
+		if explicitPath || explicitName {
+			c.profileErr = err
+		} else {
+			fmt.Printf("Warning: Ignoring configuration file %s: %v\n", path, err)
+		}
+	}
+
+	file, err := readConfigFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicitPath && !explicitName {
+		return
+	}
+	if err != nil {
+		fail(fmt.Errorf("failed to load configuration file: %w", err))
+		return
+	}
+
+	// The default profile named by the file must exist, unlike DefaultProfile
+	selected := name != "" || file.DefaultProfile != ""
+	if name == "" {
+		name = file.DefaultProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	settings, ok := file.Profiles[name]
+	if !ok {
+		if selected {
+			fail(fmt.Errorf("profile %q not found in configuration file %s", name, path))
+		}
+		return
+	}
+
+	opts, err := profileOptions(settings, !file.flat)
+	if err != nil {
+		fail(fmt.Errorf("invalid profile %q in configuration file %s: %w", name, path, err))
+		return
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.profile, c.configFile = name, path
+}
+
+// profileSettings maps the keys of a profile to the options setting them.
+var profileSettings = map[string]func(json.RawMessage) (Option, error){
+	"api_url":                profileSetting(WithCortexAPIURL),
+	"api_key":                profileSetting(WithCortexAPIKey),
+	"api_key_id":             profileSetting(WithCortexAPIKeyID),
+	"api_key_type":           profileSetting(WithCortexAPIKeyType),
+	"headers":                profileSetting(WithHeaders),
+	"agent":                  profileSetting(WithAgent),
+	"skip_ssl_verify":        profileSetting(WithSkipSSLVerify),
+	"ca_bundle_file":         profileSetting(WithCABundleFile),
+	"client_cert_file":       profileSetting(withClientCertFile),
+	"client_key_file":        profileSetting(withClientKeyFile),
+	"min_tls_version":        profileTLSVersion,
+	"proxy":                  profileSetting(WithProxy),
+	"pinned_public_keys":     profileSetting(func(pins []string) Option { return WithPinnedPublicKeys(pins...) }),
+	"timeout":                profileSetting(WithTimeout),
+	"max_retries":            profileSetting(WithMaxRetries),
+	"retry_max_delay":        profileSetting(WithRetryMaxDelay),
+	"max_response_size":      profileSetting(WithMaxResponseSize),
+	"compression":            profileSetting(WithCompression),
+	"uncompressed_endpoints": profileSetting(func(endpoints []string) Option { return WithUncompressedEndpoints(endpoints...) }),
+	"crash_stack_dir":        profileSetting(WithCrashStackDir),
+	"skip_crash_bundle":      profileSetting(WithSkipCrashBundle),
+	"log_level":              profileSetting(WithLogLevel),
+	"skip_logging_transport": profileSetting(WithSkipLoggingTransport),
+	"log_redacted_fields":    profileSetting(func(paths []string) Option { return WithLogRedactedFields(paths...) }),
+	"log_dump_max_size":      profileSetting(WithLogDumpMaxSize),
+}
+
+// withClientCertFile and withClientKeyFile set the halves of
+// WithClientCertificateFile, which profiles set through distinct keys.
+func withClientCertFile(path string) Option {
+	return func(c *Config) {
+		c.mark("client_certificate")
+		c.clientCertFile = path
+	}
+}
+
+func withClientKeyFile(path string) Option {
+	return func(c *Config) {
+		c.mark("client_certificate")
+		c.clientKeyFile = path
+	}
+}
+
+// profileTLSVersion decodes a TLS version such as "1.2".
+func profileTLSVersion(raw json.RawMessage) (Option, error) {
+	var version string
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return nil, err
+	}
+	parsed, err := ParseTLSVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return WithMinTLSVersion(parsed), nil
+}
+
+// profileSetting returns a function decoding the value of a profile key and
+// returning the option that sets it.
+func profileSetting[T any](with func(T) Option) func(json.RawMessage) (Option, error) {
+	return func(raw json.RawMessage) (Option, error) {
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		return with(v), nil
+	}
+}
+
+// profileOptions returns the options setting the settings of a profile.
+// Keys that are not settings are an error if strict, and ignored otherwise.
+func profileOptions(settings map[string]json.RawMessage, strict bool) ([]Option, error) {
+	var opts []Option
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		setting, ok := profileSettings[key]
+		if !ok && !strict {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		opt, err := setting(settings[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %w", key, err)
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
@@ -0,0 +1,351 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlProfiles = `
+default_profile: prod
+profiles:
+  prod:
+    api_url: https://prod.example.com
+    api_key: prod-key
+    api_key_id: 1
+    timeout: 45
+  staging:
+    api_url: https://staging.example.com
+    api_key: staging-key
+    api_key_id: 2
+    headers:
+      X-Tenant: staging
+    uncompressed_endpoints: [a, b]
+`
+
+const tomlProfiles = `
+default_profile = "prod"
+
+[profiles.prod]
+api_url = "https://prod.example.com"
+api_key = "prod-key"
+api_key_id = 1
+timeout = 45
+
+[profiles.staging]
+api_url = "https://staging.example.com"
+api_key = "staging-key"
+api_key_id = 2
+headers = { X-Tenant = "staging" }
+uncompressed_endpoints = ["a", "b"]
+`
+
+const jsonProfiles = `{
+  "default_profile": "prod",
+  "profiles": {
+    "prod": {"api_url": "https://prod.example.com", "api_key": "prod-key", "api_key_id": 1, "timeout": 45},
+    "staging": {
+      "api_url": "https://staging.example.com", "api_key": "staging-key", "api_key_id": 2,
+      "headers": {"X-Tenant": "staging"}, "uncompressed_endpoints": ["a", "b"]
+    }
+  }
+}`
+
+// isolateEnv clears the environment variables read by NewConfig and points
+// the home directory at an empty temporary one.
+func isolateEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{
+		CORTEXCLOUD_API_URL_ENV_VAR,
+		CORTEXCLOUD_API_KEY_ENV_VAR,
+		CORTEXCLOUD_API_KEY_ID_ENV_VAR,
+		CORTEXCLOUD_API_KEY_TYPE_ENV_VAR,
+		CORTEXCLOUD_TIMEOUT_ENV_VAR,
+		CORTEXCLOUD_CONFIG_FILE_ENV_VAR,
+		CORTEXCLOUD_PROFILE_ENV_VAR,
+	} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewConfig_Profiles(t *testing.T) {
+	for _, tc := range []struct {
+		name, file, content string
+	}{
+		{"yaml", "config.yaml", yamlProfiles},
+		{"toml", "config.toml", tomlProfiles},
+		{"json", "config.json", jsonProfiles},
+		{"yaml without extension", "config", yamlProfiles},
+		{"json without extension", "config", jsonProfiles},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			isolateEnv(t)
+			path := writeConfigFile(t, tc.file, tc.content)
+
+			c := NewConfig(WithConfigFile(path))
+			require.NoError(t, c.Validate())
+			assert.Equal(t, "prod", c.Profile())
+			assert.Equal(t, path, c.ConfigFile())
+			assert.Equal(t, "https://prod.example.com", c.CortexAPIURL())
+			assert.Equal(t, 45, c.Timeout())
+
+			c = NewConfig(WithConfigFile(path), WithProfile("staging"))
+			require.NoError(t, c.Validate())
+			assert.Equal(t, "staging", c.Profile())
+			assert.Equal(t, "staging-key", c.CortexAPIKey())
+			assert.Equal(t, 2, c.CortexAPIKeyID())
+			assert.Equal(t, map[string]string{"X-Tenant": "staging"}, c.Headers())
+			assert.Equal(t, []string{"a", "b"}, c.UncompressedEndpoints())
+			assert.Equal(t, 30, c.Timeout())
+		})
+	}
+}
+
+func TestNewConfig_Precedence(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfigFile(t, "config.yaml", yamlProfiles)
+	t.Setenv(CORTEXCLOUD_CONFIG_FILE_ENV_VAR, path)
+	t.Setenv(CORTEXCLOUD_PROFILE_ENV_VAR, "staging")
+	t.Setenv(CORTEXCLOUD_API_KEY_ENV_VAR, "env-key")
+	t.Setenv(CORTEXCLOUD_TIMEOUT_ENV_VAR, "10")
+
+	c := NewConfig(WithTimeout(5))
+	require.NoError(t, c.Validate())
+
+	assert.Equal(t, "staging", c.Profile())
+	assert.Equal(t, "https://staging.example.com", c.CortexAPIURL())
+	assert.Equal(t, "env-key", c.CortexAPIKey())
+	assert.Equal(t, 5, c.Timeout())
+	assert.Equal(t, 3, c.MaxRetries())
+
+	assert.Equal(t, SourceProfile, c.Source("api_url"))
+	assert.Equal(t, SourceEnv, c.Source("api_key"))
+	assert.Equal(t, SourceOption, c.Source("timeout"))
+	assert.Equal(t, SourceDefault, c.Source("max_retries"))
+
+	sources := c.Sources()
+	assert.Len(t, sources, len(settingNames))
+	assert.Equal(t, SourceProfile, sources["api_key_id"])
+
+	t.Run("should apply each option once", func(t *testing.T) {
+		var applied int
+		NewConfig(func(*Config) { applied++ })
+		assert.Equal(t, 1, applied)
+	})
+
+	t.Run("should add the headers of the options to those of the profile", func(t *testing.T) {
+		c := NewConfig(WithHeaders(map[string]string{"X-Tenant": "option", "X-Trace": "on"}), WithUncompressedEndpoints("c"))
+		assert.Equal(t, map[string]string{"X-Tenant": "option", "X-Trace": "on"}, c.Headers())
+		assert.Equal(t, []string{"a", "b", "c"}, c.UncompressedEndpoints())
+		assert.Equal(t, SourceOption, c.Source("headers"))
+	})
+
+	t.Run("should report options applied by With", func(t *testing.T) {
+		derived := c.With(WithCortexAPIURL("https://other.example.com"))
+		assert.Equal(t, SourceOption, derived.Source("api_url"))
+		assert.Equal(t, SourceProfile, c.Source("api_url"))
+	})
+}
+
+func TestNewConfig_ProfileSelection(t *testing.T) {
+	t.Run("should ignore a missing default file", func(t *testing.T) {
+		isolateEnv(t)
+		c := NewConfig(WithCortexAPIURL("https://example.com"), WithCortexAPIKey("key"), WithCortexAPIKeyID(1))
+		require.NoError(t, c.Validate())
+		assert.Empty(t, c.Profile())
+	})
+
+	t.Run("should read the default file", func(t *testing.T) {
+		isolateEnv(t)
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		require.NoError(t, os.MkdirAll(filepath.Join(home, ".cortex"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".cortex", "config"), []byte(yamlProfiles), 0o600))
+
+		c := NewConfig()
+		require.NoError(t, c.Validate())
+		assert.Equal(t, "prod", c.Profile())
+	})
+
+	t.Run("should ignore an invalid default file", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"malformed":       "profiles: [",
+			"unknown setting": "profiles:\n  default:\n    api_port: 443\n",
+			"invalid value":   "profiles:\n  default:\n    timeout: soon\n",
+			"missing profile": "default_profile: dev\nprofiles:\n  prod:\n    timeout: 45\n",
+		} {
+			t.Run(name, func(t *testing.T) {
+				isolateEnv(t)
+				home := t.TempDir()
+				t.Setenv("HOME", home)
+				require.NoError(t, os.MkdirAll(filepath.Join(home, ".cortex"), 0o700))
+				require.NoError(t, os.WriteFile(filepath.Join(home, ".cortex", "config"), []byte(content), 0o600))
+
+				c := NewConfig(WithCortexAPIURL("https://example.com"), WithCortexAPIKey("key"), WithCortexAPIKeyID(1))
+				require.NoError(t, c.Validate())
+				assert.Empty(t, c.Profile())
+				assert.Equal(t, 30, c.Timeout())
+			})
+		}
+	})
+
+	t.Run("should fail on a missing explicit file", func(t *testing.T) {
+		isolateEnv(t)
+		c := NewConfig(WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+		assert.ErrorContains(t, c.Validate(), "failed to load configuration file")
+	})
+
+	t.Run("should fail on a missing explicit profile", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.yaml", yamlProfiles)
+		c := NewConfig(WithConfigFile(path), WithProfile("dev"))
+		assert.ErrorContains(t, c.Validate(), `profile "dev" not found`)
+	})
+
+	t.Run("should fail on a malformed TOML file", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.toml", "[profiles.default]\ntimeout = \n")
+		c := NewConfig(WithConfigFile(path))
+		assert.ErrorContains(t, c.Validate(), "failed to parse configuration file")
+	})
+
+	t.Run("should fail on an unknown setting", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.yaml", "profiles:\n  default:\n    api_urll: https://example.com\n")
+		c := NewConfig(WithConfigFile(path))
+		assert.ErrorContains(t, c.Validate(), `unknown setting "api_urll"`)
+	})
+
+	t.Run("should fail on an invalid value", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.yaml", "profiles:\n  default:\n    timeout: soon\n")
+		c := NewConfig(WithConfigFile(path))
+		assert.ErrorContains(t, c.Validate(), `invalid value for "timeout"`)
+	})
+}
+
+func TestNewConfig_TLSProfile(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfigFile(t, "config.yaml", `
+profiles:
+  default:
+    ca_bundle_file: /etc/cortex/ca.pem
+    client_cert_file: /etc/cortex/client.pem
+    client_key_file: /etc/cortex/client.key
+    min_tls_version: "1.3"
+    proxy: http://proxy:3128
+    pinned_public_keys: [sha256/AAAA]
+`)
+
+	c, err := NewConfigFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/cortex/ca.pem", c.CABundleFile())
+	certFile, keyFile := c.ClientCertificateFiles()
+	assert.Equal(t, "/etc/cortex/client.pem", certFile)
+	assert.Equal(t, "/etc/cortex/client.key", keyFile)
+	assert.Equal(t, uint16(tls.VersionTLS13), c.MinTLSVersion())
+	assert.Equal(t, "http://proxy:3128", c.Proxy())
+	assert.Equal(t, []string{"sha256/AAAA"}, c.PinnedPublicKeys())
+	assert.True(t, c.HasTLSSettings())
+	assert.Equal(t, SourceProfile, c.Source("client_certificate"))
+
+	path = writeConfigFile(t, "config.yaml", "profiles:\n  default:\n    min_tls_version: \"2.0\"\n")
+	assert.ErrorContains(t, NewConfig(WithConfigFile(path)).Validate(), "invalid TLS version")
+}
+
+func TestNewConfigFromFile(t *testing.T) {
+	t.Run("should read a flat JSON file as the default profile", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.json", `{"api_url": "https://example.com", "api_key": "key", "api_key_id": 3, "api_key_type": "standard"}`)
+
+		c, err := NewConfigFromFile(path)
+		require.NoError(t, err)
+		require.NoError(t, c.Validate())
+		assert.Equal(t, DefaultProfile, c.Profile())
+		assert.Equal(t, "https://example.com", c.CortexAPIURL())
+		assert.Equal(t, 3, c.CortexAPIKeyID())
+		assert.Equal(t, "standard", c.CortexAPIKeyType())
+		assert.Equal(t, SourceProfile, c.Source("api_key_type"))
+	})
+
+	t.Run("should ignore keys of a flat file that are not settings", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.json", `{"api_url": "https://example.com", "api_key": "key", "api_key_id": 3, "api_port": 443}`)
+
+		c, err := NewConfigFromFile(path)
+		require.NoError(t, err)
+		require.NoError(t, c.Validate())
+		assert.Equal(t, "https://example.com", c.CortexAPIURL())
+	})
+
+	t.Run("should fail on an invalid file", func(t *testing.T) {
+		isolateEnv(t)
+		path := writeConfigFile(t, "config.json", `{"api_url": `)
+
+		_, err := NewConfigFromFile(path)
+		assert.ErrorContains(t, err, "failed to parse configuration file")
+	})
+}
+
+func TestLayeredSettings(t *testing.T) {
+	t.Run("should layer every setting of a profile beneath the options", func(t *testing.T) {
+		for key, setting := range profileSettings {
+			c := &Config{}
+			for _, raw := range []string{`"1.2"`, `1`, `true`, `{}`, `[]`} {
+				if opt, err := setting(json.RawMessage(raw)); err == nil {
+					opt(c)
+					break
+				}
+			}
+			require.Len(t, c.sources, 1, key)
+			for name := range c.sources {
+				assert.Contains(t, layeredSettings, name, key)
+			}
+		}
+	})
+}
+
+func TestSetDefaults_Agent(t *testing.T) {
+	t.Run("should use the default agent when no layer sets one", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv(CORTEXCLOUD_AGENT_ENV_VAR, "")
+		require.NoError(t, os.Unsetenv(CORTEXCLOUD_AGENT_ENV_VAR))
+		c := NewConfig(WithDefaultAgent("sdk/1.0"))
+		c.SetDefaults()
+		assert.Equal(t, "sdk/1.0", c.Agent())
+		assert.Equal(t, SourceDefault, c.Source("agent"))
+	})
+
+	t.Run("should prefer the agent of the environment and the profile", func(t *testing.T) {
+		isolateEnv(t)
+		t.Setenv(CORTEXCLOUD_AGENT_ENV_VAR, "env-agent")
+		c := NewConfig(WithDefaultAgent("sdk/1.0"))
+		c.SetDefaults()
+		assert.Equal(t, "env-agent", c.Agent())
+		assert.Equal(t, SourceEnv, c.Source("agent"))
+
+		require.NoError(t, os.Unsetenv(CORTEXCLOUD_AGENT_ENV_VAR))
+		path := writeConfigFile(t, "config.yaml", "agent: profile-agent\n")
+		c = NewConfig(WithDefaultAgent("sdk/1.0"), WithConfigFile(path))
+		c.SetDefaults()
+		assert.Equal(t, "profile-agent", c.Agent())
+		assert.Equal(t, SourceProfile, c.Source("agent"))
+	})
+}
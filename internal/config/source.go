@@ -0,0 +1,88 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+// Source is where the value of a setting came from. Settings are layered by
+// increasing precedence: defaults, then the profile of the configuration
+// file, then the environment variables, then the options passed in code.
+type Source string
+
+const (
+	// SourceDefault is the source of settings left to their default value.
+	SourceDefault Source = "default"
+	// SourceProfile is the source of settings read from a profile of the
+	// configuration file.
+	SourceProfile Source = "profile"
+	// SourceEnv is the source of settings read from environment variables.
+	SourceEnv Source = "env"
+	// SourceOption is the source of settings set by options.
+	SourceOption Source = "option"
+)
+
+// settingNames are the names of the settings whose source is reported, in
+// the order of the configuration file keys.
+var settingNames = []string{
+	"api_url",
+	"api_key",
+	"api_key_id",
+	"api_key_type",
+	"credentials_provider",
+	"authenticator",
+	"headers",
+	"agent",
+	"skip_ssl_verify",
+	"transport",
+	"ca_bundle",
+	"client_certificate",
+	"min_tls_version",
+	"proxy",
+	"pinned_public_keys",
+	"timeout",
+	"max_retries",
+	"retry_max_delay",
+	"max_response_size",
+	"compression",
+	"uncompressed_endpoints",
+	"retry_policy",
+	"retry_budget",
+	"rate_limiter",
+	"circuit_breaker",
+	"interceptors",
+	"tracer",
+	"metrics",
+	"crash_stack_dir",
+	"skip_crash_bundle",
+	"log_level",
+	"logger",
+	"skip_logging_transport",
+	"log_redacted_fields",
+	"log_dump_max_size",
+	"log_dump_sampling",
+}
+
+// mark records that the setting was set by the layer being applied.
+func (c *Config) mark(setting string) {
+	if c.sources == nil {
+		c.sources = make(map[string]Source)
+	}
+	c.sources[setting] = c.layer
+}
+
+// Source returns where the value of the setting came from. Settings are
+// named after their configuration file keys, e.g. "api_url" or "timeout".
+func (c *Config) Source(setting string) Source {
+	if source, ok := c.sources[setting]; ok && source != "" {
+		return source
+	}
+	return SourceDefault
+}
+
+// Sources returns the source of every setting, keyed by setting name.
+func (c *Config) Sources() map[string]Source {
+	sources := make(map[string]Source, len(settingNames))
+	for _, setting := range settingNames {
+		sources[setting] = c.Source(setting)
+	}
+	return sources
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// transportSettings are the settings the client builds its transport from.
+var transportSettings = []string{
+	"skip_ssl_verify",
+	"transport",
+	"ca_bundle",
+	"client_certificate",
+	"min_tls_version",
+	"proxy",
+	"pinned_public_keys",
+}
+
+// ParseTLSVersion parses a TLS version such as "1.2" or "TLS1.3".
+func ParseTLSVersion(version string) (uint16, error) {
+	v := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS")
+	switch strings.TrimSpace(v) {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS version %q", version)
+	}
+}
+
+// HasTLSSettings returns whether any TLS or proxy setting beyond
+// SkipSSLVerify is set, which requires the client to configure its transport.
+func (c *Config) HasTLSSettings() bool {
+	return len(c.caBundle) > 0 || c.caBundleFile != "" ||
+		c.clientCertificate != nil || c.clientCertFile != "" || c.clientKeyFile != "" ||
+		c.minTLSVersion != 0 || c.proxy != "" || c.proxyUsername != "" ||
+		len(c.pinnedPublicKeys) > 0
+}
+
+// ChangesTransport returns whether the options set any of the settings the
+// client builds its transport from, in which case a client derived with them
+// cannot share the transport of its parent.
+func ChangesTransport(opts ...Option) bool {
+	probe := &Config{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	for _, setting := range transportSettings {
+		if _, ok := probe.sources[setting]; ok {
+			return true
+		}
+	}
+	return false
+}
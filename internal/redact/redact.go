@@ -0,0 +1,23 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package redact holds the secrets the SDK keeps out of what it writes, such
+// as debug dumps and recorded interactions.
+package redact
+
+// Fields are the keys of the JSON body fields redacted wherever the SDK
+// writes bodies out: certificates and keys of syslog and authentication
+// settings, and the credentials of integration payloads. Keys are lower case.
+var Fields = []string{
+	"certificate_content",
+	"idp_certificate",
+	"private_key",
+	"password",
+	"secret",
+	"client_secret",
+	"api_key",
+	"apikey",
+	"token",
+	"access_token",
+	"refresh_token",
+}
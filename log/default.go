@@ -6,25 +6,45 @@ package log
 import (
 	"context"
 	"log"
+	"log/slog"
+	"strings"
 )
 
-// DefaultLogger implements the Logger interface using Go's standard log package.
+// DefaultLogger implements the Logger interface using Go's standard log
+// package, formatting each line as NewStdHandler does. A nil Logger logs to
+// log.Default().
 type DefaultLogger struct {
 	*log.Logger
 }
 
 func (l DefaultLogger) Debug(ctx context.Context, msg string, args ...map[string]any) {
-	l.Printf("DEBUG: %s %v", msg, args)
+	l.log(slog.LevelDebug, msg, args)
 }
 
 func (l DefaultLogger) Info(ctx context.Context, msg string, args ...map[string]any) {
-	l.Printf("INFO: %s %v", msg, args)
+	l.log(slog.LevelInfo, msg, args)
 }
 
 func (l DefaultLogger) Warn(ctx context.Context, msg string, args ...map[string]any) {
-	l.Printf("WARN: %s %v", msg, args)
+	l.log(slog.LevelWarn, msg, args)
 }
 
 func (l DefaultLogger) Error(ctx context.Context, msg string, args ...map[string]any) {
-	l.Printf("ERROR: %s %v", msg, args)
+	l.log(slog.LevelError, msg, args)
+}
+
+func (l DefaultLogger) log(level slog.Level, msg string, args []map[string]any) {
+	var line strings.Builder
+	writeHeader(&line, level, msg)
+	for _, attr := range fieldAttrs(args) {
+		appendAttr(&line, "", attr)
+	}
+
+	logger := l.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	// Skip log and the level method, so that the caller of the logger is
+	// reported with the Lshortfile or Llongfile flag
+	_ = logger.Output(3, line.String())
 }
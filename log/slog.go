@@ -0,0 +1,176 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlogAdapter implements the Logger interface on top of a *slog.Logger,
+// emitting the fields of each line as attributes. The source of each record
+// is the caller of the adapter. A nil Logger logs to slog.Default().
+type SlogAdapter struct {
+	*slog.Logger
+}
+
+// NewSlogAdapter returns a SlogAdapter logging to handler.
+func NewSlogAdapter(handler slog.Handler) SlogAdapter {
+	return SlogAdapter{slog.New(handler)}
+}
+
+func (l SlogAdapter) Debug(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, slog.LevelDebug, msg, args)
+}
+
+func (l SlogAdapter) Info(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, slog.LevelInfo, msg, args)
+}
+
+func (l SlogAdapter) Warn(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, slog.LevelWarn, msg, args)
+}
+
+func (l SlogAdapter) Error(ctx context.Context, msg string, args ...map[string]any) {
+	l.log(ctx, slog.LevelError, msg, args)
+}
+
+func (l SlogAdapter) log(ctx context.Context, level slog.Level, msg string, args []map[string]any) {
+	logger := l.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the level method, so that the source
+	// of the record is the caller of the adapter
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(fieldAttrs(args)...)
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+// fieldAttrs returns the fields of a log line as attributes, sorted by key.
+func fieldAttrs(args []map[string]any) []slog.Attr {
+	var attrs []slog.Attr
+	for _, fields := range args {
+		for _, key := range slices.Sorted(maps.Keys(fields)) {
+			attrs = append(attrs, slog.Any(key, fields[key]))
+		}
+	}
+	return attrs
+}
+
+// NewStdHandler returns a slog.Handler writing each record through l, or
+// log.Default() if l is nil, as its level and message followed by its
+// attributes as key=value pairs. Messages are written as is, so that
+// multi-line messages such as debug dumps stay readable. The handler
+// enables every level: the SDK filters lines by its configured log level.
+// With the Lshortfile or Llongfile flag, l reports the source of the record.
+func NewStdHandler(l *log.Logger) slog.Handler {
+	return &stdHandler{logger: l}
+}
+
+type stdHandler struct {
+	logger *log.Logger
+	attrs  string // The formatted attributes added by WithAttrs
+	group  string // The prefix of the keys of the group opened by WithGroup
+}
+
+func (h *stdHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *stdHandler) Handle(_ context.Context, r slog.Record) error {
+	var line strings.Builder
+	writeHeader(&line, r.Level, r.Message)
+	line.WriteString(h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		appendAttr(&line, h.group, attr)
+		return true
+	})
+
+	logger := h.logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	return logger.Output(callDepth(r.PC), line.String())
+}
+
+// callDepth returns the call depth, relative to the caller of callDepth, to
+// pass to log.Logger.Output for it to report the frame of pc. Without pc on
+// the stack, the caller of callDepth is reported.
+func callDepth(pc uintptr) int {
+	if pc == 0 {
+		return 1
+	}
+	var pcs [64]uintptr
+	// Skip runtime.Callers and callDepth
+	n := runtime.Callers(2, pcs[:])
+	for i, framePC := range pcs[:n] {
+		if framePC == pc {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+func (h *stdHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var formatted strings.Builder
+	for _, attr := range attrs {
+		appendAttr(&formatted, h.group, attr)
+	}
+	clone := *h
+	clone.attrs += formatted.String()
+	return &clone
+}
+
+func (h *stdHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group += name + "."
+	return &clone
+}
+
+// writeHeader writes the level and message of a line.
+func writeHeader(line *strings.Builder, level slog.Level, msg string) {
+	line.WriteString(level.String())
+	line.WriteString(": ")
+	line.WriteString(msg)
+}
+
+// appendAttr writes attr to line as " key=value", flattening groups into
+// dotted keys and quoting values that would be ambiguous otherwise.
+func appendAttr(line *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			appendAttr(line, prefix, member)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(line, " %s%s=%s", prefix, attr.Key, value)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogAdapter(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewSlogAdapter(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	logger.Debug(context.Background(), "hidden")
+	logger.Warn(context.Background(), "API request started", map[string]any{"request_id": "req-1", "attempt": 2})
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "API request started", record["msg"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, float64(2), record["attempt"])
+}
+
+func TestStdHandler(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewSlogAdapter(NewStdHandler(log.New(&out, "", 0)))
+
+	logger.Debug(context.Background(), "line one\nline two", map[string]any{"module": "platform", "status_code": 200, "error": "not found"})
+	assert.Equal(t, "DEBUG: line one\nline two error=\"not found\" module=platform status_code=200\n", out.String())
+
+	out.Reset()
+	grouped := slog.New(NewStdHandler(log.New(&out, "", 0))).With("module", "cwp").WithGroup("http")
+	grouped.Info("done", "status", 200)
+	assert.Equal(t, "INFO: done module=cwp http.status=200\n", out.String())
+}
+
+func TestDefaultLogger(t *testing.T) {
+	var out bytes.Buffer
+	DefaultLogger{log.New(&out, "", 0)}.Error(context.Background(), "failed", map[string]any{"request_id": "req-1"})
+	assert.Equal(t, "ERROR: failed request_id=req-1\n", out.String())
+}
+
+// nextLine returns the "file:line: " prefix that the Lshortfile flag writes
+// for the line following the call.
+func nextLine(t *testing.T) string {
+	t.Helper()
+	_, _, line, ok := runtime.Caller(1)
+	require.True(t, ok)
+	return "slog_test.go:" + strconv.Itoa(line+1) + ": "
+}
+
+func TestStdHandler_Source(t *testing.T) {
+	var out bytes.Buffer
+	std := log.New(&out, "", log.Lshortfile)
+
+	prefix := nextLine(t)
+	slog.New(NewStdHandler(std)).Info("done")
+	assert.Equal(t, prefix+"INFO: done\n", out.String())
+
+	out.Reset()
+	prefix = nextLine(t)
+	NewSlogAdapter(NewStdHandler(std)).Warn(context.Background(), "retrying")
+	assert.Equal(t, prefix+"WARN: retrying\n", out.String())
+
+	out.Reset()
+	prefix = nextLine(t)
+	DefaultLogger{std}.Debug(context.Background(), "sent")
+	assert.Equal(t, prefix+"DEBUG: sent\n", out.String())
+}
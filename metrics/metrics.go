@@ -0,0 +1,63 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package metrics defines the telemetry recorded by the SDK for every request
+// attempt, and provides a Prometheus-compatible implementation.
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// StatusClassError is the status class of an attempt that received no
+// response, e.g. because of a network error.
+const StatusClassError = "error"
+
+// RetryReasonNetwork is the retry reason of an attempt that failed with a
+// network error.
+const RetryReasonNetwork = "network"
+
+// Labels identifies the requests a measurement applies to.
+type Labels struct {
+	Module   string // The SDK module, e.g. "cloudsec".
+	Endpoint string // The endpoint constant, e.g. "public_api/v1/policy".
+	Method   string // The HTTP method.
+}
+
+// Metrics records request telemetry. The SDK calls it for every attempt of
+// every call, so implementations must be safe for concurrent use and should
+// not block.
+type Metrics interface {
+	// AddInFlight adds delta to the number of attempts in flight.
+	AddInFlight(labels Labels, delta int)
+	// ObserveRequest records a completed attempt, with the class of its
+	// status code as returned by StatusClass and its duration.
+	ObserveRequest(labels Labels, statusClass string, duration time.Duration)
+	// ObserveRetry records that a failed attempt is being retried. The
+	// reason is the status code of the failed attempt, e.g. "429", or
+	// RetryReasonNetwork.
+	ObserveRetry(labels Labels, reason string)
+}
+
+// StatusClass returns the class of an HTTP status code, e.g. "4xx" for 429,
+// or StatusClassError if no response was received.
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return StatusClassError
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+// Nop is a Metrics that discards every measurement. It is used when no
+// metrics are configured.
+type Nop struct{}
+
+// AddInFlight implements Metrics.
+func (Nop) AddInFlight(Labels, int) {}
+
+// ObserveRequest implements Metrics.
+func (Nop) ObserveRequest(Labels, string, time.Duration) {}
+
+// ObserveRetry implements Metrics.
+func (Nop) ObserveRetry(Labels, string) {}
@@ -0,0 +1,18 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", StatusClass(200))
+	assert.Equal(t, "4xx", StatusClass(429))
+	assert.Equal(t, "5xx", StatusClass(503))
+	assert.Equal(t, StatusClassError, StatusClass(0))
+	assert.Equal(t, StatusClassError, StatusClass(600))
+}
@@ -0,0 +1,260 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the request duration
+// histogram buckets.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Prometheus is a Metrics that aggregates measurements in memory and exposes
+// them in the Prometheus text exposition format, without depending on the
+// Prometheus client library. To register the metrics with the
+// prometheus.Registry of a service instead, use the prommetrics module. It
+// serves the metrics over HTTP, and can be mounted on an existing metrics
+// endpoint:
+//
+//	m := metrics.NewPrometheus()
+//	client, err := cloudsec.NewClient(cloudsec.WithMetrics(m))
+//	http.Handle("/metrics/cortex", m)
+//
+// The following metrics are exposed, prefixed with the namespace:
+//
+//	requests_total             counter   by module, endpoint, method and status_class
+//	request_duration_seconds   histogram by module, endpoint and method
+//	retries_total              counter   by module, endpoint, method and reason
+//	requests_in_flight         gauge     by module, endpoint and method
+type Prometheus struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[labeledKey]uint64
+	durations map[Labels]*histogram
+	retries   map[labeledKey]uint64
+	inFlight  map[Labels]int64
+}
+
+// labeledKey identifies a series with one label in addition to Labels.
+type labeledKey struct {
+	Labels
+	value string
+}
+
+// histogram holds the cumulative bucket counts of a series.
+type histogram struct {
+	counts []uint64 // counts[i] is the number of observations <= buckets[i]
+	count  uint64
+	sum    float64
+}
+
+// PrometheusOption configures a Prometheus.
+type PrometheusOption func(*Prometheus)
+
+// WithNamespace returns a PrometheusOption that sets the prefix of every
+// metric name. Defaults to "cortex_sdk".
+func WithNamespace(namespace string) PrometheusOption {
+	return func(p *Prometheus) {
+		p.namespace = namespace
+	}
+}
+
+// WithBuckets returns a PrometheusOption that sets the upper bounds, in
+// seconds, of the request duration histogram buckets. Defaults to
+// DefaultBuckets. The bounds are sorted and deduplicated; NaN and +Inf,
+// whose bucket is always exposed, are ignored. Without any other bound, the
+// default buckets are kept.
+func WithBuckets(buckets ...float64) PrometheusOption {
+	return func(p *Prometheus) {
+		if bounds := NormalizeBuckets(buckets); len(bounds) > 0 {
+			p.buckets = bounds
+		}
+	}
+}
+
+// NormalizeBuckets returns the histogram bucket upper bounds in buckets in
+// increasing order, without duplicates, NaN or +Inf.
+func NormalizeBuckets(buckets []float64) []float64 {
+	bounds := slices.DeleteFunc(slices.Clone(buckets), func(bound float64) bool {
+		return math.IsNaN(bound) || math.IsInf(bound, 1)
+	})
+	slices.Sort(bounds)
+	return slices.Compact(bounds)
+}
+
+// NewPrometheus returns a Prometheus configured with the given options.
+func NewPrometheus(opts ...PrometheusOption) *Prometheus {
+	p := &Prometheus{
+		namespace: "cortex_sdk",
+		buckets:   DefaultBuckets,
+		requests:  make(map[labeledKey]uint64),
+		durations: make(map[Labels]*histogram),
+		retries:   make(map[labeledKey]uint64),
+		inFlight:  make(map[Labels]int64),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// AddInFlight implements Metrics.
+func (p *Prometheus) AddInFlight(labels Labels, delta int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight[labels] += int64(delta)
+}
+
+// ObserveRequest implements Metrics.
+func (p *Prometheus) ObserveRequest(labels Labels, statusClass string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[labeledKey{labels, statusClass}]++
+
+	h, ok := p.durations[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[labels] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObserveRetry implements Metrics.
+func (p *Prometheus) ObserveRetry(labels Labels, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[labeledKey{labels, reason}]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = p.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	name := p.namespace + "_requests_total"
+	writeHeader(cw, name, "counter", "Total number of request attempts sent by the SDK.")
+	for _, key := range sortedKeys(p.requests) {
+		fmt.Fprintf(cw, "%s{%s,status_class=%s} %d\n", name, formatLabels(key.Labels), quote(key.value), p.requests[key])
+	}
+
+	name = p.namespace + "_request_duration_seconds"
+	writeHeader(cw, name, "histogram", "Duration of the request attempts sent by the SDK.")
+	for _, labels := range sortedLabels(p.durations) {
+		h := p.durations[labels]
+		base := formatLabels(labels)
+		for i, bound := range p.buckets {
+			fmt.Fprintf(cw, "%s_bucket{%s,le=%s} %d\n", name, base, quote(formatFloat(bound)), h.counts[i])
+		}
+		fmt.Fprintf(cw, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, base, h.count)
+		fmt.Fprintf(cw, "%s_sum{%s} %s\n", name, base, formatFloat(h.sum))
+		fmt.Fprintf(cw, "%s_count{%s} %d\n", name, base, h.count)
+	}
+
+	name = p.namespace + "_retries_total"
+	writeHeader(cw, name, "counter", "Total number of request attempts retried by the SDK.")
+	for _, key := range sortedKeys(p.retries) {
+		fmt.Fprintf(cw, "%s{%s,reason=%s} %d\n", name, formatLabels(key.Labels), quote(key.value), p.retries[key])
+	}
+
+	name = p.namespace + "_requests_in_flight"
+	writeHeader(cw, name, "gauge", "Number of request attempts currently in flight.")
+	for _, labels := range sortedLabels(p.inFlight) {
+		fmt.Fprintf(cw, "%s{%s} %d\n", name, formatLabels(labels), p.inFlight[labels])
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric.
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// formatLabels formats the common labels of a series.
+func formatLabels(l Labels) string {
+	return "module=" + quote(l.Module) + ",endpoint=" + quote(l.Endpoint) + ",method=" + quote(l.Method)
+}
+
+// quote quotes a label value, escaping backslashes, quotes and newlines.
+func quote(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatFloat formats a sample value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// compareLabels orders series by module, endpoint and method.
+func compareLabels(a, b Labels) int {
+	return cmp.Or(
+		cmp.Compare(a.Module, b.Module),
+		cmp.Compare(a.Endpoint, b.Endpoint),
+		cmp.Compare(a.Method, b.Method),
+	)
+}
+
+// sortedLabels returns the keys of m in a stable order.
+func sortedLabels[V any](m map[Labels]V) []Labels {
+	return slices.SortedFunc(maps.Keys(m), compareLabels)
+}
+
+// sortedKeys returns the keys of m in a stable order.
+func sortedKeys(m map[labeledKey]uint64) []labeledKey {
+	return slices.SortedFunc(maps.Keys(m), func(a, b labeledKey) int {
+		return cmp.Or(compareLabels(a.Labels, b.Labels), cmp.Compare(a.value, b.value))
+	})
+}
+
+// countingWriter counts the bytes written and keeps the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
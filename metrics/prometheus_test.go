@@ -0,0 +1,85 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheus(t *testing.T) {
+	policy := Labels{Module: "cloudsec", Endpoint: "public_api/v1/policy", Method: http.MethodPost}
+	rule := Labels{Module: "cloudsec", Endpoint: "public_api/v1/rule", Method: http.MethodGet}
+
+	p := NewPrometheus(WithNamespace("test"), WithBuckets(1, 0.1))
+	p.AddInFlight(policy, 1)
+	p.ObserveRequest(policy, "4xx", 50*time.Millisecond)
+	p.ObserveRetry(policy, "429")
+	p.ObserveRequest(policy, "2xx", 500*time.Millisecond)
+	p.AddInFlight(policy, -1)
+	p.AddInFlight(rule, 1)
+
+	var out strings.Builder
+	n, err := p.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(out.Len()), n)
+
+	assert.Equal(t, `# HELP test_requests_total Total number of request attempts sent by the SDK.
+# TYPE test_requests_total counter
+test_requests_total{module="cloudsec",endpoint="public_api/v1/policy",method="POST",status_class="2xx"} 1
+test_requests_total{module="cloudsec",endpoint="public_api/v1/policy",method="POST",status_class="4xx"} 1
+# HELP test_request_duration_seconds Duration of the request attempts sent by the SDK.
+# TYPE test_request_duration_seconds histogram
+test_request_duration_seconds_bucket{module="cloudsec",endpoint="public_api/v1/policy",method="POST",le="0.1"} 1
+test_request_duration_seconds_bucket{module="cloudsec",endpoint="public_api/v1/policy",method="POST",le="1"} 2
+test_request_duration_seconds_bucket{module="cloudsec",endpoint="public_api/v1/policy",method="POST",le="+Inf"} 2
+test_request_duration_seconds_sum{module="cloudsec",endpoint="public_api/v1/policy",method="POST"} 0.55
+test_request_duration_seconds_count{module="cloudsec",endpoint="public_api/v1/policy",method="POST"} 2
+# HELP test_retries_total Total number of request attempts retried by the SDK.
+# TYPE test_retries_total counter
+test_retries_total{module="cloudsec",endpoint="public_api/v1/policy",method="POST",reason="429"} 1
+# HELP test_requests_in_flight Number of request attempts currently in flight.
+# TYPE test_requests_in_flight gauge
+test_requests_in_flight{module="cloudsec",endpoint="public_api/v1/policy",method="POST"} 0
+test_requests_in_flight{module="cloudsec",endpoint="public_api/v1/rule",method="GET"} 1
+`, out.String())
+}
+
+func TestWithBuckets(t *testing.T) {
+	t.Run("should sort and deduplicate the bounds", func(t *testing.T) {
+		p := NewPrometheus(WithBuckets(1, 0.1, 1, math.NaN(), math.Inf(1), 0.5))
+		assert.Equal(t, []float64{0.1, 0.5, 1}, p.buckets)
+	})
+
+	t.Run("should keep the default buckets without bounds", func(t *testing.T) {
+		assert.Equal(t, DefaultBuckets, NewPrometheus(WithBuckets()).buckets)
+		assert.Equal(t, DefaultBuckets, NewPrometheus(WithBuckets(math.Inf(1))).buckets)
+	})
+}
+
+func TestPrometheus_ServeHTTP(t *testing.T) {
+	p := NewPrometheus()
+	p.ObserveRequest(Labels{Module: "platform", Endpoint: "a\"b\\c", Method: http.MethodGet}, "2xx", time.Second)
+
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, resp.Header.Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, string(body), `cortex_sdk_requests_total{module="platform",endpoint="a\"b\\c",method="GET",status_class="2xx"} 1`)
+	assert.Contains(t, string(body), `cortex_sdk_request_duration_seconds_bucket{module="platform",endpoint="a\"b\\c",method="GET",le="1"} 1`)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package prommetrics records the telemetry of the SDK with the Prometheus
+// client library, so that it is exposed with the other metrics of a service.
+//
+// It is a separate module, so that only the users of the Prometheus client
+// library depend on it:
+//
+//	m := prommetrics.New()
+//	prometheus.MustRegister(m)
+//	client, err := cloudsec.NewClient(cloudsec.WithMetrics(m))
+//
+// The metrics are those of metrics.Prometheus.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is a metrics.Metrics recording to Prometheus collectors. It is a
+// prometheus.Collector, to be registered with a prometheus.Registerer.
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+	inFlight  *prometheus.GaugeVec
+}
+
+var (
+	_ metrics.Metrics      = (*Metrics)(nil)
+	_ prometheus.Collector = (*Metrics)(nil)
+)
+
+// Option configures a Metrics.
+type Option func(*options)
+
+type options struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace returns an Option that sets the prefix of every metric name.
+// Defaults to "cortex_sdk".
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithBuckets returns an Option that sets the upper bounds, in seconds, of
+// the request duration histogram buckets. Defaults to metrics.DefaultBuckets.
+// The bounds are normalized by metrics.NormalizeBuckets; without any bound
+// left, the default buckets are kept.
+func WithBuckets(buckets ...float64) Option {
+	return func(o *options) {
+		if bounds := metrics.NormalizeBuckets(buckets); len(bounds) > 0 {
+			o.buckets = bounds
+		}
+	}
+}
+
+// New returns a Metrics configured with the given options.
+func New(opts ...Option) *Metrics {
+	o := options{namespace: "cortex_sdk", buckets: metrics.DefaultBuckets}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	labels := []string{"module", "endpoint", "method"}
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "requests_total",
+			Help:      "Total number of request attempts sent by the SDK.",
+		}, append(labels, "status_class")),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the request attempts sent by the SDK.",
+			Buckets:   o.buckets,
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "retries_total",
+			Help:      "Total number of request attempts retried by the SDK.",
+		}, append(labels, "reason")),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: o.namespace,
+			Name:      "requests_in_flight",
+			Help:      "Number of request attempts currently in flight.",
+		}, labels),
+	}
+}
+
+// AddInFlight implements metrics.Metrics.
+func (m *Metrics) AddInFlight(labels metrics.Labels, delta int) {
+	m.inFlight.WithLabelValues(labels.Module, labels.Endpoint, labels.Method).Add(float64(delta))
+}
+
+// ObserveRequest implements metrics.Metrics.
+func (m *Metrics) ObserveRequest(labels metrics.Labels, statusClass string, duration time.Duration) {
+	m.requests.WithLabelValues(labels.Module, labels.Endpoint, labels.Method, statusClass).Inc()
+	m.durations.WithLabelValues(labels.Module, labels.Endpoint, labels.Method).Observe(duration.Seconds())
+}
+
+// ObserveRetry implements metrics.Metrics.
+func (m *Metrics) ObserveRetry(labels metrics.Labels, reason string) {
+	m.retries.WithLabelValues(labels.Module, labels.Endpoint, labels.Method, reason).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.durations.Describe(ch)
+	m.retries.Describe(ch)
+	m.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.durations.Collect(ch)
+	m.retries.Collect(ch)
+	m.inFlight.Collect(ch)
+}
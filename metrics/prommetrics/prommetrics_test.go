@@ -0,0 +1,71 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package prommetrics
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	policy := metrics.Labels{Module: "cloudsec", Endpoint: "public_api/v1/policy", Method: http.MethodPost}
+
+	t.Run("should be registered with a registry", func(t *testing.T) {
+		m := New(WithNamespace("test"), WithBuckets(1, 0.1, 1))
+		registry := prometheus.NewPedanticRegistry()
+		require.NoError(t, registry.Register(m))
+
+		m.AddInFlight(policy, 1)
+		m.ObserveRequest(policy, "4xx", 50*time.Millisecond)
+		m.ObserveRetry(policy, "429")
+		m.ObserveRequest(policy, "2xx", 500*time.Millisecond)
+		m.AddInFlight(policy, -1)
+
+		err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP test_requests_total Total number of request attempts sent by the SDK.
+# TYPE test_requests_total counter
+test_requests_total{endpoint="public_api/v1/policy",method="POST",module="cloudsec",status_class="2xx"} 1
+test_requests_total{endpoint="public_api/v1/policy",method="POST",module="cloudsec",status_class="4xx"} 1
+# HELP test_request_duration_seconds Duration of the request attempts sent by the SDK.
+# TYPE test_request_duration_seconds histogram
+test_request_duration_seconds_bucket{endpoint="public_api/v1/policy",method="POST",module="cloudsec",le="0.1"} 1
+test_request_duration_seconds_bucket{endpoint="public_api/v1/policy",method="POST",module="cloudsec",le="1"} 2
+test_request_duration_seconds_bucket{endpoint="public_api/v1/policy",method="POST",module="cloudsec",le="+Inf"} 2
+test_request_duration_seconds_sum{endpoint="public_api/v1/policy",method="POST",module="cloudsec"} 0.55
+test_request_duration_seconds_count{endpoint="public_api/v1/policy",method="POST",module="cloudsec"} 2
+# HELP test_retries_total Total number of request attempts retried by the SDK.
+# TYPE test_retries_total counter
+test_retries_total{endpoint="public_api/v1/policy",method="POST",module="cloudsec",reason="429"} 1
+# HELP test_requests_in_flight Number of request attempts currently in flight.
+# TYPE test_requests_in_flight gauge
+test_requests_in_flight{endpoint="public_api/v1/policy",method="POST",module="cloudsec"} 0
+`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should keep the default buckets without bounds", func(t *testing.T) {
+		m := New(WithBuckets())
+		m.ObserveRequest(policy, "2xx", time.Second)
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(m))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "cortex_sdk_request_duration_seconds" {
+				assert.Len(t, family.GetMetric()[0].GetHistogram().GetBucket(), len(metrics.DefaultBuckets))
+				return
+			}
+		}
+		t.Fatal("the request duration histogram was not gathered")
+	})
+}
@@ -22,6 +22,7 @@ type genericAssetGroupsResponse struct {
 func (c *Client) CreateAssetGroup(ctx context.Context, req types.CreateOrUpdateAssetGroupRequest) (success bool, assetGroupID int, err error) {
 	var resp genericAssetGroupsResponse
 	_, err = c.internalClient.Do(ctx, http.MethodPost, CreateAssetGroupEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateAssetGroup",
 		RequestWrapperKeys:  []string{"request_data", "asset_group"},
 		ResponseWrapperKeys: []string{"reply", "data"},
 	})
@@ -32,6 +33,7 @@ func (c *Client) CreateAssetGroup(ctx context.Context, req types.CreateOrUpdateA
 func (c *Client) ListAssetGroups(ctx context.Context, req types.ListAssetGroupsRequest) (assetGroups []types.AssetGroup, err error) {
 	var resp []types.AssetGroup
 	_, err = c.internalClient.Do(ctx, http.MethodPost, ListAssetGroupsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "ListAssetGroups",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply", "data"},
 	})
@@ -42,6 +44,7 @@ func (c *Client) ListAssetGroups(ctx context.Context, req types.ListAssetGroupsR
 func (c *Client) UpdateAssetGroup(ctx context.Context, groupID int, req types.CreateOrUpdateAssetGroupRequest) (success bool, err error) {
 	var resp genericAssetGroupsResponse
 	_, err = c.internalClient.Do(ctx, http.MethodPost, UpdateAssetGroupEndpoint, &[]string{strconv.Itoa(groupID)}, nil, req, &resp, &client.DoOptions{
+		Operation:           "UpdateAssetGroup",
 		RequestWrapperKeys:  []string{"request_data", "asset_group"},
 		ResponseWrapperKeys: []string{"reply", "data"},
 	})
@@ -52,6 +55,7 @@ func (c *Client) UpdateAssetGroup(ctx context.Context, groupID int, req types.Cr
 func (c *Client) DeleteAssetGroup(ctx context.Context, groupID int) (success bool, err error) {
 	var resp genericAssetGroupsResponse
 	_, err = c.internalClient.Do(ctx, http.MethodPost, DeleteAssetGroupEndpoint, &[]string{strconv.Itoa(groupID)}, nil, nil, &resp, &client.DoOptions{
+		Operation:           "DeleteAssetGroup",
 		ResponseWrapperKeys: []string{"reply", "data"},
 	})
 	return resp.Success, err
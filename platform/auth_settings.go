@@ -17,6 +17,7 @@ import (
 func (c *Client) ListIDPMetadata(ctx context.Context) (types.ListIDPMetadataResponse, error) {
 	var resp types.ListIDPMetadataResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListIDPMetadataEndpoint, nil, nil, types.ListIDPMetadataRequest{}, &resp, &client.DoOptions{
+		Operation:          "ListIDPMetadata",
 		RequestWrapperKeys: []string{"request_data"},
 	})
 	return resp, err
@@ -29,6 +30,7 @@ func (c *Client) ListIDPMetadata(ctx context.Context) (types.ListIDPMetadataResp
 func (c *Client) ListAuthSettings(ctx context.Context) ([]types.AuthSettings, error) {
 	var ans []types.AuthSettings
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListAuthSettingsEndpoint, nil, nil, types.ListAuthSettingsRequest{}, &ans, &client.DoOptions{
+		Operation:           "ListAuthSettings",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -46,6 +48,7 @@ func (c *Client) ListAuthSettings(ctx context.Context) ([]types.AuthSettings, er
 func (c *Client) CreateAuthSettings(ctx context.Context, req types.CreateAuthSettingsRequest) (bool, error) {
 	var resp bool
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateAuthSettingsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateAuthSettings",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -62,6 +65,7 @@ func (c *Client) CreateAuthSettings(ctx context.Context, req types.CreateAuthSet
 func (c *Client) UpdateAuthSettings(ctx context.Context, req types.UpdateAuthSettingsRequest) (bool, error) {
 	var resp bool
 	_, err := c.internalClient.Do(ctx, http.MethodPost, UpdateAuthSettingsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "UpdateAuthSettings",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
@@ -75,6 +79,7 @@ func (c *Client) UpdateAuthSettings(ctx context.Context, req types.UpdateAuthSet
 func (c *Client) DeleteAuthSettings(ctx context.Context, domain string) (bool, error) {
 	var resp bool
 	_, err := c.internalClient.Do(ctx, http.MethodPost, DeleteAuthSettingsEndpoint, nil, nil, types.DeleteAuthSettingsRequest{Domain: domain}, &resp, &client.DoOptions{
+		Operation:           "DeleteAuthSettings",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
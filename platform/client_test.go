@@ -4,9 +4,13 @@
 package platform
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +67,48 @@ func TestNewClientFromFile(t *testing.T) {
 		assert.Nil(t, client)
 	})
 }
+
+func TestClient_Tracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"reply":[{"user_email":"test@example.com"}]}`))
+	}))
+	defer server.Close()
+
+	newTracedClient := func(t *testing.T) (*Client, *tracing.Recorder) {
+		recorder := tracing.NewRecorder()
+		client, err := NewClient(
+			WithCortexAPIURL(server.URL),
+			WithCortexAPIKey("test-key"),
+			WithCortexAPIKeyID(123),
+			WithSkipLoggingTransport(true),
+			WithTracer(recorder),
+		)
+		require.NoError(t, err)
+		return client, recorder
+	}
+
+	t.Run("should name the span after the method called rather than the method it wraps", func(t *testing.T) {
+		client, recorder := newTracedClient(t)
+
+		_, err := client.GetUser(context.Background(), "test@example.com")
+		require.NoError(t, err)
+
+		spans := recorder.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "platform.GetUser", spans[1].Name)
+		assert.Equal(t, ModuleName, spans[1].Attributes[tracing.AttrModule])
+		assert.Equal(t, "GetUser", spans[1].Attributes[tracing.AttrOperation])
+	})
+
+	t.Run("should name the span after the method called when it shares a helper", func(t *testing.T) {
+		client, recorder := newTracedClient(t)
+
+		require.NoError(t, client.EnableNotificationForwardingConfiguration(context.Background(), "config-1"))
+		require.NoError(t, client.DisableNotificationForwardingConfiguration(context.Background(), "config-1"))
+
+		spans := recorder.Spans()
+		require.Len(t, spans, 4)
+		assert.Equal(t, "platform.EnableNotificationForwardingConfiguration", spans[1].Name)
+		assert.Equal(t, "platform.DisableNotificationForwardingConfiguration", spans[3].Name)
+	})
+}
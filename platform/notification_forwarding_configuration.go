@@ -5,7 +5,6 @@ package platform
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
@@ -16,72 +15,76 @@ import (
 func (c *Client) CreateNotificationForwardingConfiguration(ctx context.Context, req types.CreateOrUpdateNotificationForwardingConfigurationRequest) (types.NotificationForwardingConfiguration, error) {
 	var resp types.CreateOrUpdateNotificationForwardingConfigurationResponse
 	if _, err := c.internalClient.Do(ctx, http.MethodPost, NotificationForwardingConfigurationsEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:          "CreateNotificationForwardingConfiguration",
 		RequestWrapperKeys: []string{"request_data"},
 	}); err != nil {
-		return types.NotificationForwardingConfiguration{}, mapError(err)
+		return types.NotificationForwardingConfiguration{}, err
 	} else {
-		return resp.Data.ToSDK(), mapError(err)
+		return resp.Data.ToSDK(), err
 	}
 }
 
 // UpdateNotificationForwardingConfiguration updates an existing notification forwarding configuration.
 func (c *Client) UpdateNotificationForwardingConfiguration(ctx context.Context, id string, req types.CreateOrUpdateNotificationForwardingConfigurationRequest) (types.NotificationForwardingConfiguration, error) {
 	var resp types.CreateOrUpdateNotificationForwardingConfigurationResponse
-	if _, err := c.internalClient.Do(ctx, http.MethodPut, fmt.Sprintf("%s/%s", NotificationForwardingConfigurationsEndpoint, id), nil, nil, req, &resp, &client.DoOptions{
+	if _, err := c.internalClient.Do(ctx, http.MethodPut, NotificationForwardingConfigurationsEndpoint, &[]string{id}, nil, req, &resp, &client.DoOptions{
+		Operation:          "UpdateNotificationForwardingConfiguration",
 		RequestWrapperKeys: []string{"request_data"},
 	}); err != nil {
-		return types.NotificationForwardingConfiguration{}, mapError(err)
+		return types.NotificationForwardingConfiguration{}, err
 	} else {
-		return resp.Data.ToSDK(), mapError(err)
+		return resp.Data.ToSDK(), err
 	}
 }
 
-// toggleNotificationForwardingConfiguration enables or disables a notification forwarding configuration.
-func (c *Client) toggleNotificationForwardingConfiguration(ctx context.Context, id string, status string) error {
+// toggleNotificationForwardingConfiguration enables or disables a notification
+// forwarding configuration on behalf of the named operation.
+func (c *Client) toggleNotificationForwardingConfiguration(ctx context.Context, operation, id string, status string) error {
 	req := types.ToggleNotificationForwardingConfigurationRequest{
 		Status: status,
 	}
-	_, err := c.internalClient.Do(ctx, http.MethodPatch, fmt.Sprintf("%s/%s", ToggleNotificationForwardingConfigurationEndpoint, id), nil, nil, req, nil, &client.DoOptions{
+	_, err := c.internalClient.Do(ctx, http.MethodPatch, ToggleNotificationForwardingConfigurationEndpoint, &[]string{id}, nil, req, nil, &client.DoOptions{
+		Operation:          operation,
 		RequestWrapperKeys: []string{"request_data"},
 	})
-	return mapError(err)
+	return err
 }
 
 // EnableNotificationForwardingConfiguration enables a notification forwarding configuration.
 func (c *Client) EnableNotificationForwardingConfiguration(ctx context.Context, id string) error {
-	return c.toggleNotificationForwardingConfiguration(ctx, id, "enable")
+	return c.toggleNotificationForwardingConfiguration(ctx, "EnableNotificationForwardingConfiguration", id, "enable")
 }
 
 // DisableNotificationForwardingConfiguration disables a notification forwarding configuration.
 func (c *Client) DisableNotificationForwardingConfiguration(ctx context.Context, id string) error {
-	return c.toggleNotificationForwardingConfiguration(ctx, id, "disable")
+	return c.toggleNotificationForwardingConfiguration(ctx, "DisableNotificationForwardingConfiguration", id, "disable")
 }
 
 // DeleteNotificationForwardingConfiguration deletes a notification forwarding configuration.
 func (c *Client) DeleteNotificationForwardingConfiguration(ctx context.Context, id string) error {
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, fmt.Sprintf("%s/%s", NotificationForwardingConfigurationsEndpoint, id), nil, nil, nil, nil, nil)
-	return mapError(err)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, NotificationForwardingConfigurationsEndpoint, &[]string{id}, nil, nil, nil, &client.DoOptions{Operation: "DeleteNotificationForwardingConfiguration"})
+	return err
 }
 
 // GetNotificationForwardingConfiguration retrieves the notification forwarding configuration with the specified ID value.
 func (c *Client) GetNotificationForwardingConfiguration(ctx context.Context, id string) (types.NotificationForwardingConfiguration, error) {
 	var resp types.CreateOrUpdateNotificationForwardingConfigurationResponse
-	if _, err := c.internalClient.Do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", NotificationForwardingConfigurationsEndpoint, id), nil, nil, nil, &resp, nil); err != nil {
-		return types.NotificationForwardingConfiguration{}, mapError(err)
+	if _, err := c.internalClient.Do(ctx, http.MethodGet, NotificationForwardingConfigurationsEndpoint, &[]string{id}, nil, nil, &resp, &client.DoOptions{Operation: "GetNotificationForwardingConfiguration"}); err != nil {
+		return types.NotificationForwardingConfiguration{}, err
 	} else {
-		return resp.Data.ToSDK(), mapError(err)
+		return resp.Data.ToSDK(), err
 	}
 }
 
 // ListNotificationForwardingConfigurations retrieves a filtered list of all notification forwarding configurations.
 func (c *Client) ListNotificationForwardingConfigurations(ctx context.Context) (data []types.NotificationForwardingConfiguration, totalCount int, error error) {
 	var resp types.ListNotificationForwardingConfigurationsResponse
-	if _, err := c.internalClient.Do(ctx, http.MethodGet, ListNotificationForwardingConfigurationsEndpoint, nil, nil, nil, &resp, nil); err != nil {
-		return []types.NotificationForwardingConfiguration{}, 0, mapError(err)
+	if _, err := c.internalClient.Do(ctx, http.MethodGet, ListNotificationForwardingConfigurationsEndpoint, nil, nil, nil, &resp, &client.DoOptions{Operation: "ListNotificationForwardingConfigurations"}); err != nil {
+		return []types.NotificationForwardingConfiguration{}, 0, err
 	} else {
 		for _, datum := range resp.Data {
 			data = append(data, datum.ToSDK())
 		}
-		return data, resp.Metadata.TotalCount, mapError(err)
+		return data, resp.Metadata.TotalCount, err
 	}
 }
@@ -15,16 +15,18 @@ import (
 func (c *Client) CreateSyslogIntegration(ctx context.Context, input types.CreateSyslogIntegrationRequest) (types.CreateSyslogIntegrationResponse, error) {
 	var resp types.CreateSyslogIntegrationResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, CreateSyslogIntegrationEndpoint, nil, nil, input, &resp, &client.DoOptions{
+		Operation:          "CreateSyslogIntegration",
 		RequestWrapperKeys: []string{"request_data"},
 	})
-	return resp, mapError(err)
+	return resp, err
 }
 
 // ListSyslogIntegrations retrieves a filtered list of all syslog integrations.
 func (c *Client) ListSyslogIntegrations(ctx context.Context, input types.ListSyslogIntegrationsRequest) (types.ListSyslogIntegrationsResponse, error) {
 	var resp types.ListSyslogIntegrationsResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListSyslogIntegrationsEndpoint, nil, nil, input, &resp, &client.DoOptions{
+		Operation:          "ListSyslogIntegrations",
 		RequestWrapperKeys: []string{"request_data"},
 	})
-	return resp, mapError(err)
+	return resp, err
 }
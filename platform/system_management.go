@@ -17,9 +17,9 @@ import (
 // GetUser retrieves the specified user in your environment.
 func (c *Client) GetUser(ctx context.Context, userEmail string) (types.User, error) {
 	var ans types.User
-	resp, err := c.ListUsers(ctx)
+	resp, err := c.listUsers(ctx, "GetUser")
 	if err != nil {
-		return ans, mapError(err)
+		return ans, err
 	}
 	for _, user := range resp {
 		if user.Email == userEmail {
@@ -32,17 +32,23 @@ func (c *Client) GetUser(ctx context.Context, userEmail string) (types.User, err
 
 // ListUsers retrieves a list of the current users in your environment.
 func (c *Client) ListUsers(ctx context.Context) ([]types.User, error) {
+	return c.listUsers(ctx, "ListUsers")
+}
+
+// listUsers retrieves the current users on behalf of the named operation.
+func (c *Client) listUsers(ctx context.Context, operation string) ([]types.User, error) {
 	var ans []types.User
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListUsersEndpoint, nil, nil, nil, &ans, &client.DoOptions{
+		Operation:           operation,
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 func (c *Client) ListAllRoles(ctx context.Context) (*types.ListRolesResponse, error) {
 	var resp types.ListRolesResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, RoleEndpoint, nil, nil, nil, &resp, &client.DoOptions{})
-	return &resp, mapError(err)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, RoleEndpoint, nil, nil, nil, &resp, &client.DoOptions{Operation: "ListAllRoles"})
+	return &resp, err
 }
 
 func (c *Client) CreateRole(ctx context.Context, req types.RoleCreateRequest) (*types.RoleCreateResponse, error) {
@@ -53,11 +59,12 @@ func (c *Client) CreateRole(ctx context.Context, req types.RoleCreateRequest) (*
 	}
 	_, err := c.internalClient.Do(ctx, http.MethodPost, RoleEndpoint, nil, nil, req.RequestData, &raw,
 		&client.DoOptions{
+			Operation:          "CreateRole",
 			RequestWrapperKeys: []string{"request_data"},
 		},
 	)
 	if err != nil {
-		return nil, mapError(err)
+		return nil, err
 	}
 
 	roleID := ""
@@ -74,14 +81,14 @@ func (c *Client) CreateRole(ctx context.Context, req types.RoleCreateRequest) (*
 }
 
 func (c *Client) DeleteRole(ctx context.Context, roleID string) error {
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, RoleEndpoint, &[]string{roleID}, nil, nil, nil, &client.DoOptions{})
-	return mapError(err)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, RoleEndpoint, &[]string{roleID}, nil, nil, nil, &client.DoOptions{Operation: "DeleteRole"})
+	return err
 }
 
 func (c *Client) ListPermissionConfigs(ctx context.Context) (*types.ListPermissionConfigsResponse, error) {
 	var resp types.ListPermissionConfigsResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, PermissionConfigEndpoint, nil, nil, nil, &resp, &client.DoOptions{})
-	return &resp, mapError(err)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, PermissionConfigEndpoint, nil, nil, nil, &resp, &client.DoOptions{Operation: "ListPermissionConfigs"})
+	return &resp, err
 }
 
 // SetRole adds or removes one or more users from a role.
@@ -90,10 +97,11 @@ func (c *Client) ListPermissionConfigs(ctx context.Context) (*types.ListPermissi
 func (c *Client) SetRole(ctx context.Context, input types.SetRoleRequest) (types.SetRoleResponse, error) {
 	var ans types.SetRoleResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, SetUserRoleEndpoint, nil, nil, input, &ans, &client.DoOptions{
+		Operation:           "SetRole",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // GetRiskScore retrieves the risk score of a specific user or endpoint in your environment,
@@ -101,11 +109,12 @@ func (c *Client) SetRole(ctx context.Context, input types.SetRoleRequest) (types
 func (c *Client) GetRiskScore(ctx context.Context, req types.GetRiskScoreRequest) (types.GetRiskScoreResponse, error) {
 	var ans types.GetRiskScoreResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetRiskScoreEndpoint, nil, nil, req, &ans, &client.DoOptions{
+		Operation:           "GetRiskScore",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
 
-	return ans, mapError(err)
+	return ans, err
 }
 
 // ListRiskyUsers retrieves a list of users with the highest risk score in your environment
@@ -113,9 +122,10 @@ func (c *Client) GetRiskScore(ctx context.Context, req types.GetRiskScoreRequest
 func (c *Client) ListRiskyUsers(ctx context.Context) ([]types.ListRiskyUsersResponse, error) {
 	var ans []types.ListRiskyUsersResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListRiskyUsersEndpoint, nil, nil, nil, &ans, &client.DoOptions{
+		Operation:           "ListRiskyUsers",
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // ListRiskyHosts retrieves a list of endpoints with the highest risk score in your environment
@@ -123,58 +133,64 @@ func (c *Client) ListRiskyUsers(ctx context.Context) ([]types.ListRiskyUsersResp
 func (c *Client) ListRiskyHosts(ctx context.Context) ([]types.ListRiskyHostsResponse, error) {
 	var ans []types.ListRiskyHostsResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, ListRiskyHostsEndpoint, nil, nil, nil, &ans, &client.DoOptions{
+		Operation:           "ListRiskyHosts",
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // HealthCheck performs a health check on the service.
 func (c *Client) HealthCheck(ctx context.Context) (types.HealthCheckResponse, error) {
 	var ans types.HealthCheckResponse
 	_, err := c.internalClient.Do(ctx, http.MethodGet, HealthCheckEndpoint, nil, nil, nil, &ans, &client.DoOptions{
+		Operation:           "HealthCheck",
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // GetTenantInfo retrieves information about the specified tenants.
 func (c *Client) GetTenantInfo(ctx context.Context, req types.GetTenantInfoRequest) ([]types.TenantInfo, error) {
 	var ans []types.TenantInfo
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetTenantInfoEndpoint, nil, nil, req, &ans, &client.DoOptions{
+		Operation:           "GetTenantInfo",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // ListUserGroups retrieves a list of all user groups.
 func (c *Client) ListUserGroups(ctx context.Context) ([]types.UserGroup, error) {
 	var ans []types.UserGroup
 	_, err := c.internalClient.Do(ctx, http.MethodGet, UserGroupEndpoint, nil, nil, nil, &ans, &client.DoOptions{
+		Operation:           "ListUserGroups",
 		ResponseWrapperKeys: []string{"data"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // GetUserGroup retrieves information about the specified user groups.
 func (c *Client) GetUserGroup(ctx context.Context, req types.GetUserGroupRequest) ([]types.UserGroup, error) {
 	var ans []types.UserGroup
 	_, err := c.internalClient.Do(ctx, http.MethodPost, GetUserGroupEndpoint, nil, nil, req, &ans, &client.DoOptions{
+		Operation:           "GetUserGroup",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"reply", "data"},
 	})
-	return ans, mapError(err)
+	return ans, err
 }
 
 // CreateUserGroup creates a new user group and returns its ID.
 func (c *Client) CreateUserGroup(ctx context.Context, req types.UserGroupCreateRequest) (string, error) {
 	var resp types.UserGroupCreateResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPost, UserGroupEndpoint, nil, nil, req, &resp, &client.DoOptions{
+		Operation:           "CreateUserGroup",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"data"},
 	})
 	if err != nil {
-		return "", mapError(err)
+		return "", err
 	}
 
 	// Message is "user group with group id <id> created successfully"
@@ -191,27 +207,29 @@ func (c *Client) CreateUserGroup(ctx context.Context, req types.UserGroupCreateR
 func (c *Client) EditUserGroup(ctx context.Context, groupID string, req types.UserGroupEditRequest) (string, error) {
 	var resp types.UserGroupEditResponse
 	_, err := c.internalClient.Do(ctx, http.MethodPatch, UserGroupEndpoint, &[]string{groupID}, nil, req, &resp, &client.DoOptions{
+		Operation:           "EditUserGroup",
 		RequestWrapperKeys:  []string{"request_data"},
 		ResponseWrapperKeys: []string{"data"},
 	})
-	return resp.Message, mapError(err)
+	return resp.Message, err
 }
 
 // DeleteUserGroup deletes an existing user group by its ID.
 func (c *Client) DeleteUserGroup(ctx context.Context, groupID string) (string, error) {
 	var resp types.UserGroupDeleteResponse
 	_, err := c.internalClient.Do(ctx, http.MethodDelete, UserGroupEndpoint, &[]string{groupID}, nil, nil, &resp, &client.DoOptions{
+		Operation:           "DeleteUserGroup",
 		ResponseWrapperKeys: []string{"data"},
 	})
-	return resp.Message, mapError(err)
+	return resp.Message, err
 }
 
 // ListIAMUsers retrieves a list of all users and their respective properties.
 func (c *Client) ListIAMUsers(ctx context.Context) (*types.ListIamUsersResponse, error) {
 	var ans types.ListIamUsersResponse
-	_, err := c.internalClient.Do(ctx, http.MethodGet, IamUsersEndpoint, nil, nil, nil, &ans, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, IamUsersEndpoint, nil, nil, nil, &ans, &client.DoOptions{Operation: "ListIAMUsers"})
 	if err != nil {
-		return nil, mapError(err)
+		return nil, err
 	}
 	return &ans, nil
 }
@@ -219,8 +237,8 @@ func (c *Client) ListIAMUsers(ctx context.Context) (*types.ListIamUsersResponse,
 // GetIAMUser retrieves a user and its respective properties.
 func (c *Client) GetIAMUser(ctx context.Context, userEmail string) (*types.IamUser, error) {
 	var ans types.GetIamUserResponse
-	if _, err := c.internalClient.Do(ctx, http.MethodGet, IamUsersEndpoint, &[]string{userEmail}, nil, nil, &ans, nil); err != nil {
-		return nil, mapError(err)
+	if _, err := c.internalClient.Do(ctx, http.MethodGet, IamUsersEndpoint, &[]string{userEmail}, nil, nil, &ans, &client.DoOptions{Operation: "GetIAMUser"}); err != nil {
+		return nil, err
 	}
 	return &ans.Data, nil
 }
@@ -234,19 +252,21 @@ func (c *Client) EditIAMUser(ctx context.Context, userEmail string, req types.Ia
 	}
 	// The request body is wrapped in {"request_data": ...} as seen in other API calls.
 	_, err := c.internalClient.Do(ctx, http.MethodPatch, IamUsersEndpoint, &[]string{userEmail}, nil, req, &resp, &client.DoOptions{
+		Operation:          "EditIAMUser",
 		RequestWrapperKeys: []string{"request_data"},
 	})
-	return resp.Data.Message, mapError(err)
+	return resp.Data.Message, err
 }
 
 // GetScope retrieves the scope for the given entity type and ID.
 func (c *Client) GetScope(ctx context.Context, entityType, entityID string) (*types.Scope, error) {
 	var scope types.Scope
 	_, err := c.internalClient.Do(ctx, http.MethodGet, ScopeEndpoint, &[]string{entityType, entityID}, nil, nil, &scope, &client.DoOptions{
+		Operation:           "GetScope",
 		ResponseWrapperKeys: []string{"data"},
 	})
 	if err != nil {
-		return nil, mapError(err)
+		return nil, err
 	}
 	return &scope, nil
 }
@@ -254,7 +274,8 @@ func (c *Client) GetScope(ctx context.Context, entityType, entityID string) (*ty
 // EditScope modifies the scope for the given entity type and ID.
 func (c *Client) EditScope(ctx context.Context, entityType, entityID string, req types.EditScopeRequestData) error {
 	_, err := c.internalClient.Do(ctx, http.MethodPut, ScopeEndpoint, &[]string{entityType, entityID}, nil, req, nil, &client.DoOptions{
+		Operation:          "EditScope",
 		RequestWrapperKeys: []string{"request_data"},
 	})
-	return mapError(err)
+	return err
 }
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/errors"
 	"github.com/PaloAltoNetworks/cortex-cloud-go/types/platform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -713,6 +714,13 @@ func TestClient_CreateRole(t *testing.T) {
 
 		assert.Contains(t, err.Error(), "The request contains invalid or missing parameters")
 		assert.Contains(t, err.Error(), "The role name CustomRoleName is already utilized")
+
+		var sdkErr *errors.CortexCloudSdkError
+		require.True(t, errors.AsCortexCloudSdkError(err, &sdkErr))
+		require.NotNil(t, sdkErr.HTTPStatus)
+		assert.Equal(t, http.StatusBadRequest, *sdkErr.HTTPStatus)
+		assert.Equal(t, RoleEndpoint, sdkErr.Endpoint)
+		assert.Equal(t, "400", sdkErr.ServerCode)
 	})
 }
 
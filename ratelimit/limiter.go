@@ -0,0 +1,252 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package ratelimit provides a client-side rate limiter that can be shared by
+// every SDK module client talking to the same Cortex tenant.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter combines a token bucket, which bounds the rate at which requests
+// are started, with a cap on the number of requests in flight at once.
+//
+// The in-flight cap adapts to the tenant's feedback: it is halved whenever
+// the tenant answers with HTTP 429 and grows back by one with every
+// successful response, up to the configured maximum. When the tenant reports
+// through rate-limit headers that its quota is exhausted, the limiter holds
+// back every new request until the quota resets.
+//
+// A Limiter is safe for concurrent use. Pass the same instance to every
+// module client of a tenant through the WithRateLimiter option so that they
+// draw from one shared budget.
+type Limiter struct {
+	mu sync.Mutex
+
+	rate   float64 // tokens per second; 0 disables rate limiting
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	maxInFlight int // 0 disables the in-flight cap
+	limit       int // current, adaptive in-flight cap
+	inFlight    int
+
+	pausedUntil time.Time
+
+	// changed is closed and replaced whenever capacity may have been freed.
+	changed chan struct{}
+	now     func() time.Time
+}
+
+// Option configures a Limiter.
+type Option func(*Limiter)
+
+// WithRate returns an Option that limits the rate at which requests are
+// started to perSecond, allowing bursts of up to burst requests.
+func WithRate(perSecond float64, burst int) Option {
+	return func(l *Limiter) {
+		l.rate = perSecond
+		l.burst = float64(max(burst, 1))
+	}
+}
+
+// WithMaxInFlight returns an Option that caps the number of concurrent
+// in-flight requests.
+func WithMaxInFlight(n int) Option {
+	return func(l *Limiter) {
+		l.maxInFlight = n
+	}
+}
+
+// New returns a Limiter configured with the given options. A Limiter created
+// without options never blocks.
+func New(opts ...Option) *Limiter {
+	l := &Limiter{
+		changed: make(chan struct{}),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.tokens = l.burst
+	l.limit = l.maxInFlight
+	l.last = l.now()
+	return l
+}
+
+// Wait blocks until a request may be started or ctx is done. On success, the
+// returned release function must be called once the request has completed.
+func (l *Limiter) Wait(ctx context.Context) (release func(), err error) {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.refill(now)
+
+		var delay time.Duration
+		switch {
+		case now.Before(l.pausedUntil):
+			delay = l.pausedUntil.Sub(now)
+		case l.maxInFlight > 0 && l.inFlight >= l.limit:
+			// Wait for a release; no timer needed.
+		case l.rate > 0 && l.tokens < 1:
+			delay = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		default:
+			if l.rate > 0 {
+				l.tokens--
+			}
+			l.inFlight++
+			l.mu.Unlock()
+			return l.releaseFunc(), nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		if err := waitFor(ctx, changed, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitFor blocks until changed is closed, the delay elapses (if positive) or
+// ctx is done, returning the context error in the latter case.
+func waitFor(ctx context.Context, changed <-chan struct{}, delay time.Duration) error {
+	var timeout <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
+	case <-timeout:
+		return nil
+	}
+}
+
+// Observe adapts the limiter to the tenant's response.
+func (l *Limiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// Multiplicative decrease
+		if l.maxInFlight > 0 {
+			l.limit = max(l.limit/2, 1)
+		}
+		if delay, ok := headerSeconds(resp.Header, now, "Retry-After"); ok {
+			l.pauseUntil(now.Add(delay))
+		}
+	case resp.StatusCode < http.StatusBadRequest:
+		// Additive increase
+		if l.limit < l.maxInFlight {
+			l.limit++
+		}
+	}
+
+	remaining, ok := headerInt(resp.Header, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	if ok && remaining <= 0 {
+		if delay, ok := headerSeconds(resp.Header, now, "RateLimit-Reset", "X-RateLimit-Reset"); ok {
+			l.pauseUntil(now.Add(delay))
+		}
+	}
+
+	l.notify()
+}
+
+// InFlight returns the number of requests currently in flight.
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Limit returns the current in-flight cap, or 0 if none is configured.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// releaseFunc returns a function that frees the in-flight slot taken by Wait.
+// Calling it more than once has no further effect.
+func (l *Limiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.inFlight--
+			l.notify()
+		})
+	}
+}
+
+// refill adds the tokens accrued since the last refill. Callers must hold l.mu.
+func (l *Limiter) refill(now time.Time) {
+	if l.rate <= 0 {
+		return
+	}
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	if elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed*l.rate, l.burst)
+	}
+}
+
+// pauseUntil holds back new requests until t. Callers must hold l.mu.
+func (l *Limiter) pauseUntil(t time.Time) {
+	if t.After(l.pausedUntil) {
+		l.pausedUntil = t
+	}
+}
+
+// notify wakes every waiter. Callers must hold l.mu.
+func (l *Limiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// headerInt returns the integer value of the first present header in names.
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if v := strings.TrimSpace(header.Get(name)); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// headerSeconds returns the delay described by the first present header in
+// names. Values are interpreted as delta-seconds, or as a Unix timestamp if
+// they are too large to be a plausible delta.
+func headerSeconds(header http.Header, now time.Time, names ...string) (time.Duration, bool) {
+	const epochThreshold = 1_000_000_000
+
+	n, ok := headerInt(header, names...)
+	if !ok || n < 0 {
+		return 0, false
+	}
+	if n >= epochThreshold {
+		return max(time.Unix(int64(n), 0).Sub(now), 0), true
+	}
+	return time.Duration(n) * time.Second, true
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func response(status int, headers ...string) *http.Response {
+	h := http.Header{}
+	for i := 0; i+1 < len(headers); i += 2 {
+		h.Set(headers[i], headers[i+1])
+	}
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestLimiter_NoOptionsNeverBlocks(t *testing.T) {
+	l := New()
+	for i := 0; i < 100; i++ {
+		release, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		defer release()
+	}
+	assert.Equal(t, 100, l.InFlight())
+	assert.Zero(t, l.Limit())
+}
+
+func TestLimiter_Rate(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	l := New(WithRate(1, 2))
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	for i := 0; i < 2; i++ {
+		release, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		release()
+	}
+
+	// The bucket is empty; the next call must wait for a refill.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := l.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	now = now.Add(time.Second)
+	release, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestLimiter_MaxInFlight(t *testing.T) {
+	l := New(WithMaxInFlight(1))
+
+	release, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, l.InFlight())
+
+	acquired := make(chan struct{})
+	go func() {
+		r, err := l.Wait(context.Background())
+		if err == nil {
+			r()
+		}
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second request was admitted while the first was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	release() // releasing twice must not free a second slot
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second request was not admitted after release")
+	}
+	assert.Zero(t, l.InFlight())
+}
+
+func TestLimiter_AdaptsInFlightCap(t *testing.T) {
+	l := New(WithMaxInFlight(8))
+	assert.Equal(t, 8, l.Limit())
+
+	l.Observe(response(http.StatusTooManyRequests))
+	assert.Equal(t, 4, l.Limit())
+	l.Observe(response(http.StatusTooManyRequests))
+	l.Observe(response(http.StatusTooManyRequests))
+	l.Observe(response(http.StatusTooManyRequests))
+	assert.Equal(t, 1, l.Limit())
+
+	l.Observe(response(http.StatusOK))
+	assert.Equal(t, 2, l.Limit())
+
+	// Server errors other than 429 leave the cap unchanged.
+	l.Observe(response(http.StatusInternalServerError))
+	assert.Equal(t, 2, l.Limit())
+
+	for i := 0; i < 20; i++ {
+		l.Observe(response(http.StatusOK))
+	}
+	assert.Equal(t, 8, l.Limit())
+}
+
+func TestLimiter_PausesOnRateLimitHeaders(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		resp *http.Response
+	}{
+		{"retry after on 429", response(http.StatusTooManyRequests, "Retry-After", "30")},
+		{"remaining quota exhausted", response(http.StatusOK, "RateLimit-Remaining", "0", "RateLimit-Reset", "30")},
+		{"legacy headers with epoch reset", response(http.StatusOK,
+			"X-RateLimit-Remaining", "0",
+			"X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Second).Unix(), 10))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New()
+			l.now = func() time.Time { return now }
+			l.Observe(tt.resp)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			_, err := l.Wait(ctx)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+			now = now.Add(31 * time.Second)
+			release, err := l.Wait(context.Background())
+			require.NoError(t, err)
+			release()
+			now = now.Add(-31 * time.Second)
+		})
+	}
+
+	t.Run("should not pause while quota remains", func(t *testing.T) {
+		l := New()
+		l.now = func() time.Time { return now }
+		l.Observe(response(http.StatusOK, "RateLimit-Remaining", "5", "RateLimit-Reset", "30"))
+
+		release, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+}
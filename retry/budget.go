@@ -0,0 +1,88 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget caps the number of retries a client may perform within a sliding
+// time window, across all of its calls. Once the budget is spent, failed
+// attempts are returned to the caller immediately instead of adding more
+// load to a struggling tenant.
+//
+// A Budget is safe for concurrent use and may be shared by several clients.
+type Budget struct {
+	mu         sync.Mutex
+	maxRetries int
+	window     time.Duration
+	spent      []time.Time
+	now        func() time.Time
+}
+
+// NewBudget returns a Budget allowing at most maxRetries retries in any
+// window of the given duration.
+func NewBudget(maxRetries int, window time.Duration) *Budget {
+	return &Budget{
+		maxRetries: maxRetries,
+		window:     window,
+		now:        time.Now,
+	}
+}
+
+// Allow reports whether a retry may be performed, consuming one unit of the
+// budget if so.
+func (b *Budget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+	if len(b.spent) >= b.maxRetries {
+		return false
+	}
+	b.spent = append(b.spent, now)
+	return true
+}
+
+// Remaining returns the number of retries still available in the current
+// window.
+func (b *Budget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune(b.now())
+	return max(b.maxRetries-len(b.spent), 0)
+}
+
+// prune drops retries that fell out of the window. Callers must hold b.mu.
+func (b *Budget) prune(now time.Time) {
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(b.spent) && !b.spent[i].After(cutoff) {
+		i++
+	}
+	b.spent = b.spent[i:]
+}
+
+// WithBudget returns a Policy that defers to p, but refuses any retry once
+// b has been spent.
+func WithBudget(p Policy, b *Budget) Policy {
+	return budgeted{policy: p, budget: b}
+}
+
+type budgeted struct {
+	policy Policy
+	budget *Budget
+}
+
+// ShouldRetry implements Policy.
+func (p budgeted) ShouldRetry(attempt Attempt) (bool, time.Duration) {
+	retry, delay := p.policy.ShouldRetry(attempt)
+	if !retry || !p.budget.Allow() {
+		return false, 0
+	}
+	return true, delay
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package retry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBudget(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.Equal(t, 2, b.Remaining())
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+	assert.Equal(t, 0, b.Remaining())
+
+	// Retries older than the window are released.
+	now = now.Add(time.Minute + time.Second)
+	assert.Equal(t, 2, b.Remaining())
+	assert.True(t, b.Allow())
+}
+
+func TestWithBudget(t *testing.T) {
+	b := NewBudget(1, time.Hour)
+	p := WithBudget(Constant{Delay: time.Second, MaxRetries: 5}, b)
+
+	ok, delay := p.ShouldRetry(statusAttempt(0, http.StatusServiceUnavailable))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	ok, _ = p.ShouldRetry(statusAttempt(1, http.StatusServiceUnavailable))
+	assert.False(t, ok)
+
+	t.Run("should not spend budget when the policy declines", func(t *testing.T) {
+		fresh := NewBudget(1, time.Hour)
+		p := WithBudget(NoRetry{}, fresh)
+		ok, _ := p.ShouldRetry(statusAttempt(0, http.StatusServiceUnavailable))
+		assert.False(t, ok)
+		assert.Equal(t, 1, fresh.Remaining())
+	})
+}
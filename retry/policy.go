@@ -0,0 +1,150 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package retry defines the retry policies used by the SDK's core HTTP client
+// to decide whether, and after how long, a failed request is attempted again.
+package retry
+
+import (
+	"math/rand"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// DefaultRetryableStatuses lists the HTTP status codes retried by the built-in
+// policies when no explicit list is configured.
+var DefaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,    // 429
+	http.StatusBadGateway,         // 502
+	http.StatusServiceUnavailable, // 503
+	http.StatusGatewayTimeout,     // 504
+}
+
+// IsRetryableStatus reports whether statusCode is one of the
+// DefaultRetryableStatuses.
+func IsRetryableStatus(statusCode int) bool {
+	return slices.Contains(DefaultRetryableStatuses, statusCode)
+}
+
+// Attempt describes the outcome of a single request attempt.
+//
+// Exactly one of Response and Err is set: Response when the server replied
+// with a non-successful status, Err when no response was received at all
+// (for example a connection or TLS failure).
+type Attempt struct {
+	// Number is the zero-based index of the attempt that just failed.
+	Number int
+	// Method is the HTTP method of the request.
+	Method string
+	// Response is the HTTP response received, if any. Its body has already
+	// been consumed and must not be read.
+	Response *http.Response
+	// Err is the network or transport error, if no response was received.
+	Err error
+}
+
+// StatusCode returns the status code of the attempt's response, or 0 if no
+// response was received.
+func (a Attempt) StatusCode() int {
+	if a.Response == nil {
+		return 0
+	}
+	return a.Response.StatusCode
+}
+
+// Policy decides whether a failed attempt should be retried.
+//
+// ShouldRetry is only consulted for attempts that are safe to repeat; the
+// core client never retries a non-idempotent request that may already have
+// been applied, regardless of the policy. Implementations must be safe for
+// concurrent use.
+type Policy interface {
+	// ShouldRetry reports whether another attempt should be made and how
+	// long to wait before making it.
+	ShouldRetry(attempt Attempt) (retry bool, delay time.Duration)
+}
+
+// isRetryable reports whether the attempt failed in a way that the given
+// status list considers transient. Network errors are always transient.
+func isRetryable(attempt Attempt, statuses []int) bool {
+	if attempt.Response == nil {
+		return true
+	}
+	if statuses == nil {
+		statuses = DefaultRetryableStatuses
+	}
+	return slices.Contains(statuses, attempt.Response.StatusCode)
+}
+
+// ExponentialJitter retries transient failures using exponential backoff
+// with ±25% jitter to prevent a thundering herd.
+type ExponentialJitter struct {
+	// BaseDelay is the delay before the first retry. It doubles with every
+	// subsequent attempt. Defaults to one second.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed delay. Defaults to 60 seconds.
+	MaxDelay time.Duration
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+	// RetryableStatuses lists the HTTP status codes to retry. Defaults to
+	// DefaultRetryableStatuses.
+	RetryableStatuses []int
+}
+
+// ShouldRetry implements Policy.
+func (p ExponentialJitter) ShouldRetry(attempt Attempt) (bool, time.Duration) {
+	if attempt.Number >= p.MaxRetries || !isRetryable(attempt, p.RetryableStatuses) {
+		return false, 0
+	}
+
+	baseDelay := p.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = time.Second
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 60 * time.Second
+	}
+
+	// Exponential backoff: BaseDelay * 2^attempt, capped at MaxDelay
+	delay := maxDelay
+	if attempt.Number < 32 {
+		if d := baseDelay << uint(attempt.Number); d > 0 && d < maxDelay {
+			delay = d
+		}
+	}
+
+	// Add jitter (±25% randomization)
+	if half := int64(delay / 2); half > 0 {
+		delay += time.Duration(rand.Int63n(half)) - delay/4
+	}
+	return true, delay
+}
+
+// Constant retries transient failures after a fixed delay.
+type Constant struct {
+	// Delay is the wait between attempts.
+	Delay time.Duration
+	// MaxRetries is the maximum number of retries after the initial attempt.
+	MaxRetries int
+	// RetryableStatuses lists the HTTP status codes to retry. Defaults to
+	// DefaultRetryableStatuses.
+	RetryableStatuses []int
+}
+
+// ShouldRetry implements Policy.
+func (p Constant) ShouldRetry(attempt Attempt) (bool, time.Duration) {
+	if attempt.Number >= p.MaxRetries || !isRetryable(attempt, p.RetryableStatuses) {
+		return false, 0
+	}
+	return true, p.Delay
+}
+
+// NoRetry never retries, making every call fail fast on its first error.
+type NoRetry struct{}
+
+// ShouldRetry implements Policy.
+func (NoRetry) ShouldRetry(Attempt) (bool, time.Duration) {
+	return false, 0
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func statusAttempt(number, status int) Attempt {
+	return Attempt{Number: number, Method: http.MethodGet, Response: &http.Response{StatusCode: status}}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	assert.False(t, IsRetryableStatus(http.StatusUnauthorized))
+	assert.True(t, IsRetryableStatus(http.StatusTooManyRequests))
+	assert.True(t, IsRetryableStatus(http.StatusBadGateway))
+	assert.True(t, IsRetryableStatus(http.StatusServiceUnavailable))
+	assert.True(t, IsRetryableStatus(http.StatusGatewayTimeout))
+	assert.False(t, IsRetryableStatus(http.StatusInternalServerError))
+	assert.False(t, IsRetryableStatus(http.StatusNotFound))
+}
+
+func TestAttempt_StatusCode(t *testing.T) {
+	assert.Equal(t, 503, statusAttempt(0, 503).StatusCode())
+	assert.Zero(t, Attempt{Err: errors.New("boom")}.StatusCode())
+}
+
+func TestExponentialJitter(t *testing.T) {
+	p := ExponentialJitter{BaseDelay: time.Second, MaxDelay: 4 * time.Second, MaxRetries: 3}
+
+	t.Run("should back off exponentially within jitter bounds", func(t *testing.T) {
+		for attempt, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+			ok, delay := p.ShouldRetry(statusAttempt(attempt, http.StatusServiceUnavailable))
+			assert.True(t, ok)
+			assert.GreaterOrEqual(t, delay, base-base/4)
+			assert.LessOrEqual(t, delay, base+base/4)
+		}
+	})
+
+	t.Run("should stop after MaxRetries", func(t *testing.T) {
+		ok, _ := p.ShouldRetry(statusAttempt(3, http.StatusServiceUnavailable))
+		assert.False(t, ok)
+	})
+
+	t.Run("should retry network errors", func(t *testing.T) {
+		ok, _ := p.ShouldRetry(Attempt{Method: http.MethodGet, Err: errors.New("connection refused")})
+		assert.True(t, ok)
+	})
+
+	t.Run("should not retry non-retryable status", func(t *testing.T) {
+		ok, _ := p.ShouldRetry(statusAttempt(0, http.StatusBadRequest))
+		assert.False(t, ok)
+	})
+
+	t.Run("should honor custom status list", func(t *testing.T) {
+		custom := ExponentialJitter{MaxRetries: 1, RetryableStatuses: []int{http.StatusInternalServerError}}
+		ok, _ := custom.ShouldRetry(statusAttempt(0, http.StatusInternalServerError))
+		assert.True(t, ok)
+		ok, _ = custom.ShouldRetry(statusAttempt(0, http.StatusServiceUnavailable))
+		assert.False(t, ok)
+	})
+
+	t.Run("should not overflow for large attempt numbers", func(t *testing.T) {
+		big := ExponentialJitter{MaxDelay: time.Minute, MaxRetries: 100}
+		ok, delay := big.ShouldRetry(statusAttempt(80, http.StatusServiceUnavailable))
+		assert.True(t, ok)
+		assert.LessOrEqual(t, delay, time.Minute+time.Minute/4)
+		assert.Greater(t, delay, time.Duration(0))
+	})
+}
+
+func TestConstant(t *testing.T) {
+	p := Constant{Delay: 250 * time.Millisecond, MaxRetries: 2}
+
+	ok, delay := p.ShouldRetry(statusAttempt(1, http.StatusTooManyRequests))
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, delay)
+
+	ok, _ = p.ShouldRetry(statusAttempt(2, http.StatusTooManyRequests))
+	assert.False(t, ok)
+}
+
+func TestNoRetry(t *testing.T) {
+	ok, _ := NoRetry{}.ShouldRetry(statusAttempt(0, http.StatusServiceUnavailable))
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package oteltracing adapts OpenTelemetry to the tracing hooks of the SDK.
+//
+// It is a separate module, so that only the users of OpenTelemetry depend on
+// it:
+//
+//	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+//	client, err := cloudsec.NewClient(
+//		cloudsec.WithTracer(oteltracing.New(tp)),
+//		...
+//	)
+//
+// Trace context is propagated with the global TextMapPropagator, which is
+// set with otel.SetTextMapPropagator.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans started by the SDK.
+const ScopeName = "github.com/PaloAltoNetworks/cortex-cloud-go"
+
+// Tracer is a tracing.Tracer starting OpenTelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ tracing.Tracer = (*Tracer)(nil)
+
+// New returns a Tracer starting spans with a tracer of provider. If provider
+// is nil, the global TracerProvider is used.
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(ScopeName, trace.WithInstrumentationVersion(version.SDKVersion))}
+}
+
+// Start implements tracing.Tracer.
+func (t *Tracer) Start(ctx context.Context, name string, kind tracing.SpanKind) (context.Context, tracing.Span) {
+	spanKind := trace.SpanKindInternal
+	if kind == tracing.SpanKindClient {
+		spanKind = trace.SpanKindClient
+	}
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(spanKind))
+	return ctx, otelSpan{span}
+}
+
+// Inject implements tracing.Tracer, using the global TextMapPropagator.
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// otelSpan adapts an OpenTelemetry span to tracing.Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(attrs ...tracing.Attribute) {
+	kvs := make([]attribute.KeyValue, len(attrs))
+	for i, attr := range attrs {
+		kvs[i] = keyValue(attr)
+	}
+	s.span.SetAttributes(kvs...)
+}
+
+func (s otelSpan) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+// keyValue converts attr to an OpenTelemetry attribute. Values of types
+// OpenTelemetry does not support are formatted as strings.
+func keyValue(attr tracing.Attribute) attribute.KeyValue {
+	key := attribute.Key(attr.Key)
+	switch v := attr.Value.(type) {
+	case string:
+		return key.String(v)
+	case int:
+		return key.Int(v)
+	case int64:
+		return key.Int64(v)
+	case bool:
+		return key.Bool(v)
+	case float64:
+		return key.Float64(v)
+	default:
+		return key.String(fmt.Sprint(v))
+	}
+}
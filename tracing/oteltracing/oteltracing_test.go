@@ -0,0 +1,107 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oteltracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cloudsec"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestProvider returns a TracerProvider exporting its spans to the
+// returned in-memory exporter as soon as they end.
+func newTestProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, exporter
+}
+
+// attributes returns the attributes of span keyed by attribute key.
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("should trace the calls of a client", func(t *testing.T) {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		t.Cleanup(func() { otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator()) })
+
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			w.Write([]byte(`{"id":"policy-1"}`))
+		}))
+		defer server.Close()
+
+		provider, exporter := newTestProvider(t)
+		client, err := cloudsec.NewClient(
+			cloudsec.WithCortexAPIURL(server.URL),
+			cloudsec.WithCortexAPIKey("key"),
+			cloudsec.WithCortexAPIKeyID(1),
+			cloudsec.WithSkipLoggingTransport(true),
+			cloudsec.WithTracer(New(provider)),
+		)
+		require.NoError(t, err)
+
+		_, err = client.GetPolicy(context.Background(), "policy-1")
+		require.NoError(t, err)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 2)
+		attempt, call := spans[0], spans[1]
+
+		assert.Equal(t, "cloudsec.GetPolicy", call.Name)
+		assert.Equal(t, trace.SpanKindInternal, call.SpanKind)
+		assert.Equal(t, ScopeName, call.InstrumentationScope.Name)
+		assert.Equal(t, "cloudsec", attributes(call)[tracing.AttrModule].AsString())
+		assert.Equal(t, int64(http.StatusOK), attributes(call)[tracing.AttrStatusCode].AsInt64())
+
+		assert.Equal(t, "HTTP GET", attempt.Name)
+		assert.Equal(t, trace.SpanKindClient, attempt.SpanKind)
+		assert.Equal(t, call.SpanContext.SpanID(), attempt.Parent.SpanID())
+		assert.Equal(t, call.SpanContext.TraceID(), attempt.SpanContext.TraceID())
+		assert.Equal(t, "00-"+attempt.SpanContext.TraceID().String()+"-"+attempt.SpanContext.SpanID().String()+"-01", traceparent)
+	})
+
+	t.Run("should mark the span of a failed call as an error", func(t *testing.T) {
+		provider, exporter := newTestProvider(t)
+		_, span := New(provider).Start(context.Background(), "cloudsec.GetPolicy", tracing.SpanKindInternal)
+		span.RecordError(errors.New("boom"))
+		span.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "boom", spans[0].Status.Description)
+		require.Len(t, spans[0].Events, 1)
+		assert.Equal(t, "exception", spans[0].Events[0].Name)
+	})
+
+	t.Run("should convert the attribute values", func(t *testing.T) {
+		assert.Equal(t, attribute.String("a", "b"), keyValue(tracing.String("a", "b")))
+		assert.Equal(t, attribute.Int("a", 1), keyValue(tracing.Int("a", 1)))
+		assert.Equal(t, attribute.Bool("a", true), keyValue(tracing.Attribute{Key: "a", Value: true}))
+		assert.Equal(t, attribute.String("a", "[1 2]"), keyValue(tracing.Attribute{Key: "a", Value: []int{1, 2}}))
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader is the W3C Trace Context header written by the Recorder.
+const TraceparentHeader = "traceparent"
+
+// RecordedSpan is a span completed by a Recorder.
+type RecordedSpan struct {
+	Name         string
+	Kind         SpanKind
+	TraceID      string // 32 hex characters.
+	SpanID       string // 16 hex characters.
+	ParentSpanID string // Empty for root spans.
+	Attributes   map[string]any
+	Err          error // The last error recorded on the span.
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// Recorder is a Tracer that keeps completed spans in memory, for use in
+// tests. It propagates trace context using the W3C traceparent header.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// recorderSpanKey is the context key for the span started by a Recorder.
+type recorderSpanKey struct{}
+
+// Start implements Tracer.
+func (r *Recorder) Start(ctx context.Context, name string, kind SpanKind) (context.Context, Span) {
+	s := &recorderSpan{
+		recorder: r,
+		data: RecordedSpan{
+			Name:       name,
+			Kind:       kind,
+			SpanID:     randomHex(8),
+			Attributes: make(map[string]any),
+			StartTime:  time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(recorderSpanKey{}).(*recorderSpan); ok {
+		s.data.TraceID = parent.data.TraceID
+		s.data.ParentSpanID = parent.data.SpanID
+	} else {
+		s.data.TraceID = randomHex(16)
+	}
+	return context.WithValue(ctx, recorderSpanKey{}, s), s
+}
+
+// Inject implements Tracer.
+func (r *Recorder) Inject(ctx context.Context, header http.Header) {
+	if s, ok := ctx.Value(recorderSpanKey{}).(*recorderSpan); ok {
+		header.Set(TraceparentHeader, fmt.Sprintf("00-%s-%s-01", s.data.TraceID, s.data.SpanID))
+	}
+}
+
+// Spans returns the completed spans, in the order they ended.
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedSpan(nil), r.spans...)
+}
+
+// Reset discards the completed spans.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+// recorderSpan is the Span started by a Recorder.
+type recorderSpan struct {
+	recorder *Recorder
+
+	mu    sync.Mutex
+	data  RecordedSpan
+	ended bool
+}
+
+// SetAttributes implements Span.
+func (s *recorderSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.data.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError implements Span.
+func (s *recorderSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err
+}
+
+// End implements Span. Calling it more than once has no further effect.
+func (s *recorderSpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.spans = append(s.recorder.spans, data)
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("should record child spans in the parent's trace", func(t *testing.T) {
+		r := NewRecorder()
+		ctx, parent := r.Start(context.Background(), "parent", SpanKindInternal)
+		_, child := r.Start(ctx, "child", SpanKindClient)
+		child.SetAttributes(Int(AttrStatusCode, 200))
+		child.End()
+		parent.RecordError(errors.New("boom"))
+		parent.End()
+
+		spans := r.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "child", spans[0].Name)
+		assert.Equal(t, SpanKindClient, spans[0].Kind)
+		assert.Equal(t, 200, spans[0].Attributes[AttrStatusCode])
+		assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+		assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+		assert.Empty(t, spans[1].ParentSpanID)
+		assert.EqualError(t, spans[1].Err, "boom")
+	})
+
+	t.Run("should inject the W3C traceparent of the current span", func(t *testing.T) {
+		r := NewRecorder()
+		header := http.Header{}
+		r.Inject(context.Background(), header)
+		assert.Empty(t, header.Get(TraceparentHeader))
+
+		ctx, span := r.Start(context.Background(), "span", SpanKindClient)
+		r.Inject(ctx, header)
+		span.End()
+
+		spans := r.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "00-"+spans[0].TraceID+"-"+spans[0].SpanID+"-01", header.Get(TraceparentHeader))
+		assert.Len(t, spans[0].TraceID, 32)
+		assert.Len(t, spans[0].SpanID, 16)
+	})
+
+	t.Run("should record a span once", func(t *testing.T) {
+		r := NewRecorder()
+		_, span := r.Start(context.Background(), "span", SpanKindInternal)
+		span.End()
+		span.End()
+		assert.Len(t, r.Spans(), 1)
+
+		r.Reset()
+		assert.Empty(t, r.Spans())
+	})
+}
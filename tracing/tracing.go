@@ -0,0 +1,91 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package tracing defines the tracing hooks called by the SDK for every call.
+//
+// The SDK does not depend on a tracing library. Instead, a Tracer is passed to
+// the WithTracer option of a module client. The oteltracing module provides
+// a Tracer starting OpenTelemetry spans:
+//
+//	client, err := cloudsec.NewClient(
+//		cloudsec.WithTracer(oteltracing.New(tracerProvider)),
+//		...
+//	)
+//
+// For every call, the SDK starts a span named after the SDK method, such as
+// "cloudsec.CreatePolicy", with one child span per HTTP attempt. The trace
+// context of the attempt span is injected into the outgoing request headers.
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// Attribute keys set on the spans started by the SDK.
+const (
+	AttrModule      = "cortex.module"             // The SDK module, e.g. "cloudsec".
+	AttrOperation   = "cortex.operation"          // The SDK method, e.g. "CreatePolicy".
+	AttrEndpoint    = "cortex.endpoint"           // The endpoint constant, e.g. "public_api/v1/policy".
+	AttrRequestID   = "cortex.request_id"         // The X-Request-ID sent with the call.
+	AttrRetryCount  = "cortex.retry_count"        // The number of retries made by the call.
+	AttrMethod      = "http.request.method"       // The HTTP method.
+	AttrResendCount = "http.request.resend_count" // The zero-based attempt number.
+	AttrStatusCode  = "http.response.status_code" // The HTTP status code of the last response.
+)
+
+// SpanKind describes the role of a span in a trace.
+type SpanKind int
+
+const (
+	// SpanKindInternal is the kind of the span covering an SDK method.
+	SpanKindInternal SpanKind = iota
+	// SpanKindClient is the kind of the span covering an HTTP attempt.
+	SpanKindClient
+)
+
+// String returns the name of the span kind.
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindClient:
+		return "client"
+	default:
+		return "unknown"
+	}
+}
+
+// Attribute is a key-value pair describing a span. Values are strings, ints
+// or bools.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string-valued Attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an int-valued Attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span is a single operation within a trace.
+type Span interface {
+	// SetAttributes sets the given attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError records err on the span and marks the span as failed.
+	RecordError(err error)
+	// End completes the span. No method may be called after End.
+	End()
+}
+
+// Tracer starts spans and propagates their trace context.
+//
+// A Tracer must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns
+	// a context holding the new span.
+	Start(ctx context.Context, name string, kind SpanKind) (context.Context, Span)
+	// Inject writes the trace context of the span in ctx into header.
+	Inject(ctx context.Context, header http.Header)
+}
@@ -0,0 +1,265 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package vcr provides an HTTP transport that records the requests sent by
+// the SDK, and the responses they received, to a cassette file, and replays
+// them later without a tenant.
+//
+// Record a session once against a real tenant:
+//
+//	rec, err := vcr.New("testdata/policies.json", vcr.ModeRecord)
+//	client, err := cloudsec.NewClient(
+//		cloudsec.WithCortexAPIURL(apiURL),
+//		cloudsec.WithCortexAPIKey(apiKey),
+//		cloudsec.WithCortexAPIKeyID(apiKeyID),
+//		cloudsec.WithTransport(rec),
+//	)
+//	// ... make calls ...
+//	err = rec.Save()
+//
+// Then replay it in offline tests by creating the Recorder with ModeReplay.
+// Secrets are redacted from the cassette as it is recorded, so the replaying
+// client may use any API key. The API keys of the clients using the Recorder
+// are redacted automatically; WithSecrets adds other values to redact.
+package vcr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves requests from the cassette, without sending them.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the tenant and records them in the
+	// cassette.
+	ModeRecord
+)
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+// Pass it to a module client through the WithTransport option.
+//
+// In replay mode, a request is served the response of the first interaction
+// not yet replayed that has the same method, URL path and body. JSON bodies
+// are compared after normalization, so key order and whitespace do not
+// matter. Interactions are replayed at most once, in the order they were
+// recorded, so that a sequence of identical requests, e.g. retries, receives
+// the same sequence of responses as when it was recorded.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	redactor  redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithRealTransport returns an Option that sets the transport used to send
+// requests in record mode. Defaults to http.DefaultTransport.
+func WithRealTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithRedactedHeaders returns an Option that redacts the given headers in
+// addition to DefaultRedactedHeaders.
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.redactor.headers = append(r.redactor.headers, names...)
+	}
+}
+
+// WithRedactedFields returns an Option that redacts the string values of the
+// given JSON object keys in addition to DefaultRedactedFields.
+func WithRedactedFields(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redactor.fields[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithSecrets returns an Option that redacts every occurrence of the given
+// values from recorded URLs, headers and bodies, in addition to the API keys
+// of the clients using the Recorder.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Recorder) {
+		r.redactor.addSecrets(secrets...)
+	}
+}
+
+// New returns a Recorder using the cassette stored at path. In replay mode,
+// the cassette must exist; in record mode, it is written by Save.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		redactor: redactor{
+			headers: append([]string(nil), DefaultRedactedHeaders...),
+			fields:  make(map[string]bool),
+		},
+		cassette: &Cassette{Version: cassetteVersion},
+	}
+	for _, field := range DefaultRedactedFields {
+		r.redactor.fields[field] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeReplay:
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.replayed = make([]bool, len(cassette.Interactions))
+	case ModeRecord:
+	default:
+		return nil, fmt.Errorf("unknown recorder mode %d", mode)
+	}
+	return r, nil
+}
+
+// RedactSecrets redacts every occurrence of the given values from the
+// interactions recorded from now on, like WithSecrets. The SDK clients using
+// the Recorder as their transport call it with their API key, and with those
+// of their credentials provider as they are retrieved.
+func (r *Recorder) RedactSecrets(secrets ...string) {
+	r.redactor.addSecrets(secrets...)
+}
+
+// Cassette returns a copy of the interactions recorded or loaded so far.
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Cassette{
+		Version:      r.cassette.Version,
+		Interactions: append([]Interaction(nil), r.cassette.Interactions...),
+	}
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// replay serves req from the cassette.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	normalized := normalizeBody(r.redactor.body(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || !interaction.Request.matches(req.Method, req.URL.Path, normalized) {
+			continue
+		}
+		r.replayed[i] = true
+		return newResponse(req, interaction.Response.StatusCode, interaction.Response.Headers, interaction.Response.Body), nil
+	}
+	return nil, fmt.Errorf("no unplayed interaction in cassette %s matches %s %s", r.path, req.Method, req.URL.Path)
+}
+
+// record sends req to the tenant and records the interaction.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     r.redactor.text(req.URL.String()),
+			Headers: r.redactor.header(req.Header),
+			Body:    r.redactor.body(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    r.redactor.header(resp.Header),
+			Body:       r.redactor.body(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// readRequestBody reads and closes the body of req.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
+
+// newResponse builds the response replayed for req.
+func newResponse(req *http.Request, statusCode int, header http.Header, body []byte) *http.Response {
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// The recorded length may no longer match the redacted body
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
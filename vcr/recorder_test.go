@@ -0,0 +1,209 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vcr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/cloudsec"
+	"github.com/PaloAltoNetworks/cortex-cloud-go/credentials"
+	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/cloudsec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIKey = "super-secret-api-key"
+
+func newCloudSecClient(t *testing.T, apiURL, apiKey string, rec *Recorder) *cloudsec.Client {
+	t.Helper()
+	client, err := cloudsec.NewClient(
+		cloudsec.WithCortexAPIURL(apiURL),
+		cloudsec.WithCortexAPIKey(apiKey),
+		cloudsec.WithCortexAPIKeyID(1),
+		cloudsec.WithTransport(rec),
+		cloudsec.WithMaxRetries(0),
+		cloudsec.WithSkipLoggingTransport(true),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost:
+			assert.Contains(t, string(body), `"name":"Policy A"`)
+			w.Write([]byte(`{"id":"policy-1","name":"Policy A","api_key":"returned-secret"}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"id":"policy-1","name":"Policy A"}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassettes", "policies.json")
+	rec, err := New(path, ModeRecord, WithSecrets(testAPIKey))
+	require.NoError(t, err)
+
+	client := newCloudSecClient(t, server.URL, testAPIKey, rec)
+	created, err := client.CreatePolicy(context.Background(), types.PolicyCreateRequest{Name: "Policy A", RuleMatchingType: "ALL_RULES", AssetMatchingType: "ALL_ASSETS"})
+	require.NoError(t, err)
+	assert.Equal(t, "policy-1", created.ID)
+	_, err = client.GetPolicy(context.Background(), "policy-1")
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+	server.Close()
+
+	t.Run("should redact secrets from the cassette", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), testAPIKey)
+		assert.NotContains(t, string(data), "returned-secret")
+
+		cassette := rec.Cassette()
+		require.Len(t, cassette.Interactions, 2)
+		request := cassette.Interactions[0].Request
+		assert.Equal(t, []string{Redacted}, request.Headers.Values("Authorization"))
+		assert.Equal(t, []string{Redacted}, request.Headers.Values("X-Xdr-Nonce"))
+		assert.Contains(t, string(cassette.Interactions[0].Response.Body), `"api_key":"REDACTED"`)
+	})
+
+	t.Run("should replay matching requests without a tenant", func(t *testing.T) {
+		replay, err := New(path, ModeReplay)
+		require.NoError(t, err)
+
+		client := newCloudSecClient(t, "https://replay.example.com", "another-key", replay)
+		got, err := client.GetPolicy(context.Background(), "policy-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Policy A", got.Name)
+
+		created, err := client.CreatePolicy(context.Background(), types.PolicyCreateRequest{Name: "Policy A", AssetMatchingType: "ALL_ASSETS", RuleMatchingType: "ALL_RULES"})
+		require.NoError(t, err)
+		assert.Equal(t, "policy-1", created.ID)
+
+		// Each interaction is replayed once
+		_, err = client.GetPolicy(context.Background(), "policy-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no unplayed interaction")
+	})
+
+	t.Run("should not replay requests with a different body", func(t *testing.T) {
+		replay, err := New(path, ModeReplay)
+		require.NoError(t, err)
+
+		client := newCloudSecClient(t, "https://replay.example.com", "another-key", replay)
+		_, err = client.CreatePolicy(context.Background(), types.PolicyCreateRequest{Name: "Policy B"})
+		require.Error(t, err)
+	})
+}
+
+func TestRecorder_ReplayMissingCassette(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	assert.Error(t, err)
+}
+
+func TestNormalizeBody(t *testing.T) {
+	assert.Equal(t, normalizeBody([]byte(`{"b": 1, "a": {"d": [1, 2], "c": 12345678901234567890}}`)), normalizeBody([]byte(`{"a":{"c":12345678901234567890,"d":[1,2]},"b":1}`)))
+	assert.NotEqual(t, normalizeBody([]byte(`{"a":12345678901234567890}`)), normalizeBody([]byte(`{"a":12345678901234567891}`)))
+	assert.Equal(t, []byte("not json"), normalizeBody([]byte("not json")))
+	assert.Nil(t, normalizeBody([]byte("  ")))
+}
+
+func TestBody_JSON(t *testing.T) {
+	c := Cassette{Interactions: []Interaction{{
+		Request:  RecordedRequest{Method: http.MethodPost, URL: "https://example.com/a", Body: Body(`{"a":1}`)},
+		Response: RecordedResponse{StatusCode: http.StatusOK, Body: Body{0x1f, 0x8b, 0xff}},
+	}}}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, c.Save(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"body": "{\"a\":1}"`))
+	assert.True(t, strings.Contains(string(data), `"base64"`))
+
+	loaded, err := LoadCassette(path)
+	require.NoError(t, err)
+	assert.Equal(t, c.Interactions, loaded.Interactions)
+}
+
+func TestRedactor(t *testing.T) {
+	r := &redactor{
+		headers: DefaultRedactedHeaders,
+		fields:  map[string]bool{"password": true},
+		secrets: []string{"s3cr3t"},
+	}
+
+	h := r.header(http.Header{"Authorization": {"abc"}, "X-Custom": {"key=s3cr3t"}})
+	assert.Equal(t, Redacted, h.Get("Authorization"))
+	assert.Equal(t, "key=REDACTED", h.Get("X-Custom"))
+
+	body := r.body([]byte(`{"users":[{"name":"a&b","Password":"p"}],"note":"s3cr3t"}`))
+	assert.JSONEq(t, `{"users":[{"name":"a&b","Password":"REDACTED"}],"note":"REDACTED"}`, string(body))
+	assert.Contains(t, string(body), "a&b")
+
+	// Bodies without redacted fields are preserved byte for byte
+	assert.Equal(t, `{ "b": 1, "a": 2 }`, string(r.body([]byte(`{ "b": 1, "a": 2 }`))))
+}
+
+func TestNew_RedactedFields(t *testing.T) {
+	t.Run("should redact the fields redacted from debug dumps", func(t *testing.T) {
+		rec, err := New(filepath.Join(t.TempDir(), "cassette.json"), ModeRecord)
+		require.NoError(t, err)
+
+		body := rec.redactor.body([]byte(`{"syslog":{"certificate_content":"c","private_key":"k"},"sso":{"idp_certificate":"i"}}`))
+		assert.JSONEq(t, `{"syslog":{"certificate_content":"REDACTED","private_key":"REDACTED"},"sso":{"idp_certificate":"REDACTED"}}`, string(body))
+	})
+}
+
+func TestRecorder_RedactsClientSecrets(t *testing.T) {
+	// The server echoes the API key, in the body and the headers
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"id":"policy-1","note":"signed by ` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer server.Close()
+
+	record := func(t *testing.T, opts ...cloudsec.Option) Cassette {
+		t.Helper()
+		rec, err := New(filepath.Join(t.TempDir(), "cassette.json"), ModeRecord)
+		require.NoError(t, err)
+		opts = append([]cloudsec.Option{
+			cloudsec.WithCortexAPIURL(server.URL),
+			cloudsec.WithCortexAPIKeyID(1),
+			cloudsec.WithCortexAPIKeyType("standard"),
+			cloudsec.WithTransport(rec),
+			cloudsec.WithMaxRetries(0),
+			cloudsec.WithSkipLoggingTransport(true),
+		}, opts...)
+		client, err := cloudsec.NewClient(opts...)
+		require.NoError(t, err)
+		_, err = client.GetPolicy(context.Background(), "policy-1")
+		require.NoError(t, err)
+		return rec.Cassette()
+	}
+
+	t.Run("should redact the API key of the client", func(t *testing.T) {
+		cassette := record(t, cloudsec.WithCortexAPIKey(testAPIKey))
+		require.Len(t, cassette.Interactions, 1)
+		interaction := cassette.Interactions[0]
+		assert.Equal(t, []string{Redacted}, interaction.Request.Headers.Values("X-Xdr-Auth-Id"))
+		assert.Equal(t, Redacted, interaction.Response.Headers.Get("X-Echo"))
+		assert.Contains(t, string(interaction.Response.Body), "signed by REDACTED")
+	})
+
+	t.Run("should redact the API keys of the credentials provider", func(t *testing.T) {
+		cassette := record(t, cloudsec.WithCredentialsProvider(credentials.Static{APIKey: "provided-api-key", APIKeyID: 1}))
+		require.Len(t, cassette.Interactions, 1)
+		interaction := cassette.Interactions[0]
+		assert.Equal(t, Redacted, interaction.Response.Headers.Get("X-Echo"))
+		assert.NotContains(t, string(interaction.Response.Body), "provided-api-key")
+	})
+}
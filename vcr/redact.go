@@ -0,0 +1,123 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/redact"
+)
+
+// Redacted replaces secrets in recorded interactions.
+const Redacted = "REDACTED"
+
+// DefaultRedactedHeaders are the headers whose values are redacted from
+// recorded requests and responses: the authentication headers of requests,
+// including the ID of their API key, and cookies.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"x-xdr-auth-id",
+	"x-xdr-nonce",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// DefaultRedactedFields are the JSON object keys whose string values are
+// redacted from recorded bodies. Keys are matched case-insensitively. They
+// are the fields redacted from the debug dumps of the SDK.
+var DefaultRedactedFields = slices.Clone(redact.Fields)
+
+// redactor removes secrets from recorded interactions.
+type redactor struct {
+	headers []string
+	fields  map[string]bool
+
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// addSecrets adds the non-empty secrets not yet known to those redacted.
+func (r *redactor) addSecrets(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, secret := range secrets {
+		if secret != "" && !slices.Contains(r.secrets, secret) {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+}
+
+// header returns a copy of h with secrets redacted.
+func (r *redactor) header(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range r.headers {
+		if values := out.Values(name); len(values) > 0 {
+			out[http.CanonicalHeaderKey(name)] = []string{Redacted}
+		}
+	}
+	for name, values := range out {
+		for i, v := range values {
+			values[i] = r.text(v)
+		}
+		out[name] = values
+	}
+	return out
+}
+
+// body returns body with secrets redacted. The values of redacted fields are
+// replaced in JSON bodies, which are otherwise preserved byte for byte.
+func (r *redactor) body(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if v, err := decodeJSON(body); err == nil && r.redactFields(v) {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err == nil {
+			body = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		}
+	}
+	return []byte(r.text(string(body)))
+}
+
+// redactFields replaces the values of redacted fields in v, reporting
+// whether any were found.
+func (r *redactor) redactFields(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, isString := value.(string); isString && r.fields[strings.ToLower(key)] {
+				v[key] = Redacted
+				found = true
+				continue
+			}
+			found = r.redactFields(value) || found
+		}
+	case []any:
+		for _, value := range v {
+			found = r.redactFields(value) || found
+		}
+	}
+	return found
+}
+
+// text returns s with every known secret value replaced.
+func (r *redactor) text(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Palo Alto Networks, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cortex
+
+const (
+	// ModuleName is the name of this module.
+	ModuleName = "cortex"
+)
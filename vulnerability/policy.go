@@ -8,13 +8,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/PaloAltoNetworks/cortex-cloud-go/internal/client"
 	types "github.com/PaloAltoNetworks/cortex-cloud-go/types/vulnerability"
 )
 
 // CreatePolicy creates a new vulnerability management policy.
 func (c *Client) CreatePolicy(ctx context.Context, req types.CreateVulnerabilityManagementPolicyRequest) (*types.CreateVulnerabilityManagementPolicyResponse, error) {
 	var resp types.CreateVulnerabilityManagementPolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, CreatePolicyEndpoint, nil, nil, req, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, CreatePolicyEndpoint, nil, nil, req, &resp, &client.DoOptions{Operation: "CreatePolicy"})
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +25,7 @@ func (c *Client) CreatePolicy(ctx context.Context, req types.CreateVulnerability
 // GetPolicy retrieves a specific vulnerability policy by ID.
 func (c *Client) GetPolicy(ctx context.Context, id string) (*types.VulnerabilityManagementPolicy, error) {
 	var resp types.VulnerabilityManagementPolicy
-	_, err := c.internalClient.Do(ctx, http.MethodGet, GetPolicyEndpoint, &[]string{id}, nil, nil, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodGet, GetPolicyEndpoint, &[]string{id}, nil, nil, &resp, &client.DoOptions{Operation: "GetPolicy"})
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +37,7 @@ func (c *Client) GetPolicy(ctx context.Context, id string) (*types.Vulnerability
 // The caller MUST provide ALL required fields in the request.
 func (c *Client) UpdatePolicy(ctx context.Context, id string, req types.UpdateVulnerabilityManagementPolicyRequest) (*types.UpdateVulnerabilityManagementPolicyResponse, error) {
 	var resp types.UpdateVulnerabilityManagementPolicyResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPut, UpdatePolicyEndpoint, &[]string{id}, nil, req, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPut, UpdatePolicyEndpoint, &[]string{id}, nil, req, &resp, &client.DoOptions{Operation: "UpdatePolicy"})
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +48,7 @@ func (c *Client) UpdatePolicy(ctx context.Context, id string, req types.UpdateVu
 // Note: The API returns a boolean value (true on success), not a success object.
 func (c *Client) DeletePolicy(ctx context.Context, id string) (bool, error) {
 	var resp bool
-	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeletePolicyEndpoint, &[]string{id}, nil, nil, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodDelete, DeletePolicyEndpoint, &[]string{id}, nil, nil, &resp, &client.DoOptions{Operation: "DeletePolicy"})
 	if err != nil {
 		return false, err
 	}
@@ -57,7 +58,7 @@ func (c *Client) DeletePolicy(ctx context.Context, id string) (bool, error) {
 // ListPolicies retrieves a paginated list of vulnerability policies with optional filtering and sorting.
 func (c *Client) ListPolicies(ctx context.Context, req types.ListVulnerabilityManagementPoliciesRequest) (*types.ListVulnerabilityManagementPoliciesResponse, error) {
 	var resp types.ListVulnerabilityManagementPoliciesResponse
-	_, err := c.internalClient.Do(ctx, http.MethodPost, ListPoliciesEndpoint, nil, nil, req, &resp, nil)
+	_, err := c.internalClient.Do(ctx, http.MethodPost, ListPoliciesEndpoint, nil, nil, req, &resp, &client.DoOptions{Operation: "ListPolicies"})
 	if err != nil {
 		return nil, err
 	}